@@ -14,6 +14,10 @@ import (
 // Write generates the code for initializing a struct
 // with data from a parquet file.
 func Write(f fields.Field) string {
+	if m, ok := f.Map(); ok {
+		return writeMap(f, m)
+	}
+
 	if f.Repeated() {
 		return writeRepeated(f)
 	}
@@ -28,6 +32,10 @@ func Write(f fields.Field) string {
 // Read generates the code for reading a struct
 // and using the data to write to a parquet file.
 func Read(f fields.Field) string {
+	if m, ok := f.Map(); ok {
+		return readMap(f, m)
+	}
+
 	if f.Repeated() {
 		return readRepeated(f)
 	}
@@ -128,8 +128,8 @@ func TestPersonLevels(t *testing.T) {
 		{Name: "name"},
 		{Name: "hobby.name", Defs: []uint8{1}},
 		{Name: "hobby.difficulty", Defs: []uint8{2}},
-		{Name: "hobby.skills.name", Defs: []uint8{2, 2}, Reps: []uint8{0, 1}},
-		{Name: "hobby.skills.difficulty", Defs: []uint8{2, 2}, Reps: []uint8{0, 1}},
+		{Name: "hobby.skills.list.element.name", Defs: []uint8{2, 2}, Reps: []uint8{0, 1}},
+		{Name: "hobby.skills.list.element.difficulty", Defs: []uint8{2, 2}, Reps: []uint8{0, 1}},
 	}
 
 	assert.Equal(t, expected, pr.Levels())
@@ -0,0 +1,185 @@
+package dremel
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/parsyl/parquet/cmd/parquetgen/fields"
+)
+
+// mapField holds what's needed to generate the code that reads and
+// writes the key or value column of a map.
+type mapField struct {
+	f fields.Field
+	m fields.Field
+	// path is the map's path in the struct (like x.Hobby.Attrs)
+	path string
+	// nils are the cases for the map's optional parents
+	nils []string
+	// inits create the map's optional parents
+	inits []string
+	// def is the definition level of a key/value pair
+	def int
+	rep int
+}
+
+func newMapField(f, m fields.Field) mapField {
+	mf := mapField{f: f, m: m, rep: m.MaxRep() + 1}
+	names := []string{"x"}
+	for _, fld := range fields.Reverse(m.Chain()) {
+		if fld.IsRoot() {
+			continue
+		}
+		names = append(names, fld.Name)
+		pth := strings.Join(names, ".")
+		if fld.IsMap() {
+			mf.path = pth
+			break
+		}
+
+		if fld.RepetitionType == fields.Optional {
+			mf.nils = append(mf.nils, fmt.Sprintf(`case %s == nil:
+		defs = append(defs, %d)
+		reps = append(reps, 0)`, pth, mf.def))
+			mf.def++
+			mf.inits = append(mf.inits, fmt.Sprintf(`if def >= %d && %s == nil {
+				%s = &%s{}
+			}`, mf.def, pth, pth, fld.Type))
+		}
+	}
+
+	mf.nils = append(mf.nils, fmt.Sprintf(`case %s == nil:
+		defs = append(defs, %d)
+		reps = append(reps, 0)`, mf.path, mf.def))
+	mf.inits = append(mf.inits, fmt.Sprintf(`if def >= %d && %s == nil {
+				%s = %s{}
+			}`, mf.def+1, mf.path, mf.path, m.Type))
+	mf.def += 2
+	return mf
+}
+
+func (mf mapField) funcName() string {
+	return strings.Join(mf.f.FieldNames(), "")
+}
+
+// sortedKeys is the name of the function that returns the map's keys
+// in order, so that the key and value columns line up.
+func (mf mapField) sortedKeys() string {
+	return "sorted" + strings.Title(mf.f.MapKeys())
+}
+
+// readMap generates the code that reads the key or value column of a
+// map from a struct.
+func readMap(f, m fields.Field) string {
+	mf := newMapField(f, m)
+	typ := cleanTypeName(f.Type)
+
+	var val string
+	if f.IsMapKey() {
+		val = fmt.Sprintf(`defs = append(defs, %d)
+			vals = append(vals, k)`, mf.def)
+	} else if f.RepetitionType == fields.Optional {
+		val = fmt.Sprintf(`if v := %s[k]; v == nil {
+				defs = append(defs, %d)
+			} else {
+				defs = append(defs, %d)
+				vals = append(vals, *v)
+			}`, mf.path, mf.def, mf.def+1)
+	} else {
+		val = fmt.Sprintf(`defs = append(defs, %d)
+			vals = append(vals, %s[k])`, mf.def, mf.path)
+	}
+
+	out := fmt.Sprintf(`func read%s(x %s, vals []%s, defs, reps []uint8) ([]%s, []uint8, []uint8) {
+	switch {
+	%s
+	case len(%s) == 0:
+		defs = append(defs, %d)
+		reps = append(reps, 0)
+	default:
+		var rep uint8
+		for _, k := range %s(%s) {
+			%s
+			reps = append(reps, rep)
+			rep = %d
+		}
+	}
+
+	return vals, defs, reps
+}`, mf.funcName(), f.StructType(), typ, typ, strings.Join(mf.nils, "\n\t"), mf.path, mf.def-1, mf.sortedKeys(), mf.path, val, mf.rep)
+
+	if !f.IsMapKey() {
+		return out
+	}
+
+	return out + fmt.Sprintf(`
+
+func %s(m %s) []%s {
+	keys := make([]%s, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+	return keys
+}`, mf.sortedKeys(), m.Type, typ, typ)
+}
+
+// writeMap generates the code that writes the key or value column of
+// a map to a struct.  The key column's function adds the keys to the
+// map and passes them, in the order they were read, to the value
+// column's function.
+func writeMap(f, m fields.Field) string {
+	mf := newMapField(f, m)
+	typ := cleanTypeName(f.Type)
+	keyType := cleanTypeName(f.Parent.Children[0].Type)
+
+	var body string
+	if f.IsMapKey() {
+		body = fmt.Sprintf(`%s
+			if def >= %d {
+				var v %s
+				%s[vals[nVals]] = v
+				*keys = append(*keys, vals[nVals])
+				nVals++
+			}`, strings.Join(mf.inits, "\n\t\t\t"), mf.def, f.Parent.Children[1].TypeName(), mf.path)
+	} else {
+		def := mf.def
+		set := fmt.Sprintf("%s[k] = vals[nVals]", mf.path)
+		if f.RepetitionType == fields.Optional {
+			def++
+			set = fmt.Sprintf("%s[k] = %s(vals[nVals])", mf.path, f.PointerFunc())
+		}
+		body = fmt.Sprintf(`if def < %d {
+				continue
+			}
+
+			// the keys are missing if the key column isn't being read
+			if len(*keys) > 0 {
+				k := (*keys)[0]
+				*keys = (*keys)[1:]
+				if def == %d {
+					%s
+				}
+			}
+			if def == %d {
+				nVals++
+			}`, mf.def, def, set, def)
+	}
+
+	return fmt.Sprintf(`func write%s(keys *[]%s) func(x *%s, vals []%s, defs, reps []uint8) (int, int) {
+	return func(x *%s, vals []%s, defs, reps []uint8) (int, int) {
+		var nVals, nLevels int
+		for i := range defs {
+			def := defs[i]
+			if i > 0 && reps[i] == 0 {
+				break
+			}
+			nLevels++
+
+			%s
+		}
+
+		return nVals, nLevels
+	}
+}`, mf.funcName(), keyType, f.StructType(), typ, f.StructType(), typ, body)
+}
@@ -42,7 +42,7 @@ func readOptional(f fields.Field) string {
 }
 
 func cleanTypeName(s string) string {
-	return strings.Replace(strings.Replace(s, "*", "", 1), "[]", "", 1)
+	return strings.Replace(s, "*", "", 1)
 }
 
 func nilField(i int, f fields.Field) string {
@@ -3,11 +3,18 @@ package doc
 // Code generated by github.com/parsyl/parquet.  DO NOT EDIT.
 
 import (
+	"bytes"
 	"encoding/binary"
 	"fmt"
 	"io"
 	"math"
+	"math/big"
+	"reflect"
+	"runtime"
+	"sort"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/parsyl/parquet"
 	sch "github.com/parsyl/parquet/schema"
@@ -15,6 +22,8 @@ import (
 )
 
 var _ = math.MaxInt32 // to avoid unused import
+var _ = time.Second   // to avoid unused import
+var _ = sort.Slice    // to avoid unused import
 
 type compression int
 
@@ -22,37 +31,91 @@ const (
 	compressionUncompressed compression = 0
 	compressionSnappy       compression = 1
 	compressionGzip         compression = 2
+	compressionBrotli       compression = 4
+	compressionZstd         compression = 6
+	compressionLz4          compression = 7
 	compressionUnknown      compression = -1
 )
 
+// codec is a compression algorithm along with its level, where 0 means
+// the algorithm's default level.
+type codec struct {
+	compression compression
+	level       int
+}
+
+// codecs is the compression of every column along with the columns
+// that override it.
+type codecs struct {
+	codec
+	columns map[string]codec
+}
+
+// tagCodecs are the codecs set with the codec option of the struct tags.
+var tagCodecs = map[string]codec{}
+
+// column returns the codec of a column.  A ColumnCompression option
+// takes precedence over the struct tag, which takes precedence over the
+// compression of every column.
+func (c codecs) column(name string) codec {
+	if cd, ok := c.columns[name]; ok {
+		return cd
+	}
+
+	if cd, ok := tagCodecs[name]; ok {
+		return cd
+	}
+	return c.codec
+}
+
 var buffpool = bytebufferpool.Pool{}
 
 // ParquetWriter reprents a row group
 type ParquetWriter struct {
+	// fields are the fields of the page that records are added to
 	fields []Field
 
-	len int
+	// chunks holds the encoded (and compressed) pages of each column
+	// chunk until the row group is written
+	chunks []bytes.Buffer
 
-	// child points to the next page
-	child *ParquetWriter
+	// len is the number of records in the current page and size is the
+	// estimated size of the pages in chunks
+	len  int
+	size int
 
 	// max is the number of Record items that can get written before
-	// a new set of column chunks is written
+	// a new page is started (0 means no limit)
 	max int
 
+	// pageBytes and rowGroupBytes are the estimated sizes at which a new
+	// page is started and the row group is written, and maxBuffered is
+	// the number of buffered bytes at which the row group is written
+	// (0 means no limit)
+	pageBytes     int
+	rowGroupBytes int
+	maxBuffered   int
+
+	// err is the error from writing a row group that got too big
+	err error
+
+	keyValues []keyValue
+
 	meta        *parquet.Metadata
-	w           io.Writer
-	compression compression
+	w           *offsetWriter
+	compression codecs
+	dictionary  bool
+	dataPageV2  bool
 }
 
-func Fields(compression compression) []Field {
+func Fields(compression codecs, dictionary bool) []Field {
 	return []Field{
-		NewInt64Field(readDocID, writeDocID, []string{"docid"}, fieldCompression(compression)),
-		NewInt64OptionalField(readLinksBackward, writeLinksBackward, []string{"link", "backward"}, []int{1, 2}, optionalFieldCompression(compression)),
-		NewInt64OptionalField(readLinksForward, writeLinksForward, []string{"link", "forward"}, []int{1, 2}, optionalFieldCompression(compression)),
-		NewStringOptionalField(readNamesLanguagesCode, writeNamesLanguagesCode, []string{"names", "languages", "code"}, []int{2, 2, 0}, optionalFieldCompression(compression)),
-		NewStringOptionalField(readNamesLanguagesCountry, writeNamesLanguagesCountry, []string{"names", "languages", "country"}, []int{2, 2, 1}, optionalFieldCompression(compression)),
-		NewStringOptionalField(readNamesURL, writeNamesURL, []string{"names", "url"}, []int{2, 1}, optionalFieldCompression(compression)),
+		NewInt64Field(readDocID, writeDocID, []string{"docid"}, fieldCompression(compression.column("docid")), fieldDictionary(dictionary)),
+		NewInt64OptionalField(readLinksBackward, writeLinksBackward, []string{"link", "backward"}, []int{1, 2}, optionalFieldCompression(compression.column("link.backward")), optionalFieldDictionary(dictionary)),
+		NewInt64OptionalField(readLinksForward, writeLinksForward, []string{"link", "forward"}, []int{1, 2}, optionalFieldCompression(compression.column("link.forward")), optionalFieldDictionary(dictionary)),
+		NewStringOptionalField(readNamesLanguagesCode, writeNamesLanguagesCode, []string{"names", "languages", "code"}, []int{2, 2, 0}, optionalFieldCompression(compression.column("names.languages.code")), optionalFieldDictionary(dictionary)),
+		NewStringOptionalField(readNamesLanguagesCountry, writeNamesLanguagesCountry, []string{"names", "languages", "country"}, []int{2, 2, 1}, optionalFieldCompression(compression.column("names.languages.country")), optionalFieldDictionary(dictionary)),
+		NewStringOptionalField(readNamesURL, writeNamesURL, []string{"names", "url"}, []int{2, 1}, optionalFieldCompression(compression.column("names.url")), optionalFieldDictionary(dictionary)),
 	}
 }
 
@@ -338,30 +401,70 @@ func writeNamesURL(x *Document, vals []string, defs, reps []uint8) (int, int) {
 	return nVals, nLevels
 }
 
-func fieldCompression(c compression) func(*parquet.RequiredField) {
-	switch c {
+func fieldCompression(c codec) func(*parquet.RequiredField) {
+	var opt func(*parquet.RequiredField)
+	switch c.compression {
 	case compressionUncompressed:
-		return parquet.RequiredFieldUncompressed
+		opt = parquet.RequiredFieldUncompressed
 	case compressionSnappy:
-		return parquet.RequiredFieldSnappy
+		opt = parquet.RequiredFieldSnappy
 	case compressionGzip:
-		return parquet.RequiredFieldGzip
+		opt = parquet.RequiredFieldGzip
+	case compressionZstd:
+		opt = parquet.RequiredFieldZstd
+	case compressionLz4:
+		opt = parquet.RequiredFieldLz4
+	case compressionBrotli:
+		opt = parquet.RequiredFieldBrotli
 	default:
-		return parquet.RequiredFieldUncompressed
+		opt = parquet.RequiredFieldUncompressed
+	}
+
+	level := parquet.RequiredFieldCompressionLevel(c.level)
+	return func(f *parquet.RequiredField) {
+		opt(f)
+		level(f)
 	}
 }
 
-func optionalFieldCompression(c compression) func(*parquet.OptionalField) {
-	switch c {
+func optionalFieldCompression(c codec) func(*parquet.OptionalField) {
+	var opt func(*parquet.OptionalField)
+	switch c.compression {
 	case compressionUncompressed:
-		return parquet.OptionalFieldUncompressed
+		opt = parquet.OptionalFieldUncompressed
 	case compressionSnappy:
-		return parquet.OptionalFieldSnappy
+		opt = parquet.OptionalFieldSnappy
 	case compressionGzip:
-		return parquet.OptionalFieldGzip
+		opt = parquet.OptionalFieldGzip
+	case compressionZstd:
+		opt = parquet.OptionalFieldZstd
+	case compressionLz4:
+		opt = parquet.OptionalFieldLz4
+	case compressionBrotli:
+		opt = parquet.OptionalFieldBrotli
 	default:
-		return parquet.OptionalFieldUncompressed
+		opt = parquet.OptionalFieldUncompressed
+	}
+
+	level := parquet.OptionalFieldCompressionLevel(c.level)
+	return func(f *parquet.OptionalField) {
+		opt(f)
+		level(f)
+	}
+}
+
+func fieldDictionary(d bool) func(*parquet.RequiredField) {
+	if d {
+		return parquet.RequiredFieldDictionary
+	}
+	return func(*parquet.RequiredField) {}
+}
+
+func optionalFieldDictionary(d bool) func(*parquet.OptionalField) {
+	if d {
+		return parquet.OptionalFieldDictionary
 	}
+	return func(*parquet.OptionalField) {}
 }
 
 func NewParquetWriter(w io.Writer, opts ...func(*ParquetWriter) error) (*ParquetWriter, error) {
@@ -370,9 +473,8 @@ func NewParquetWriter(w io.Writer, opts ...func(*ParquetWriter) error) (*Parquet
 
 func newParquetWriter(w io.Writer, opts ...func(*ParquetWriter) error) (*ParquetWriter, error) {
 	p := &ParquetWriter{
-		max:         1000,
-		w:           w,
-		compression: compressionSnappy,
+		w:           &offsetWriter{w: w},
+		compression: codecs{codec: codec{compression: compressionSnappy}},
 	}
 
 	for _, opt := range opts {
@@ -381,20 +483,46 @@ func newParquetWriter(w io.Writer, opts ...func(*ParquetWriter) error) (*Parquet
 		}
 	}
 
-	p.fields = Fields(p.compression)
+	// the pages are only cut by their size if there's a TargetPageBytes
+	// and no MaxPageSize
+	if p.max == 0 && p.pageBytes == 0 {
+		p.max = 1000
+	}
+
 	if p.meta == nil {
-		ff := Fields(p.compression)
+		ff := Fields(p.compression, p.dictionary)
 		schema := make([]parquet.Field, len(ff))
 		for i, f := range ff {
 			schema[i] = f.Schema()
 		}
 		p.meta = parquet.New(schema...)
+		if p.dataPageV2 {
+			p.meta.DataPageV2()
+		}
+		for _, kv := range p.keyValues {
+			p.meta.SetKeyValue(kv.key, kv.value)
+		}
+
+		// the columns are named as they are by parquet.Metadata.Column
+		columns := make(map[string]codec, len(p.compression.columns))
+		for col, c := range p.compression.columns {
+			name, ok := p.meta.Column(col)
+			if !ok {
+				return nil, fmt.Errorf("unknown column %s", col)
+			}
+			columns[name] = c
+		}
+		p.compression.columns = columns
 	}
 
+	p.fields = Fields(p.compression, p.dictionary)
+
 	return p, nil
 }
 
 // MaxPageSize is the maximum number of rows in each row groups' page.
+// It's 1000 unless there's a TargetPageBytes, in which case the number
+// of rows isn't limited.
 func MaxPageSize(m int) func(*ParquetWriter) error {
 	return func(p *ParquetWriter) error {
 		p.max = m
@@ -402,58 +530,211 @@ func MaxPageSize(m int) func(*ParquetWriter) error {
 	}
 }
 
-var par1 = []byte("PAR1")
+// TargetPageBytes starts a new page once the estimated size of a page's
+// values and levels (before compression) reaches n bytes.
+func TargetPageBytes(n int) func(*ParquetWriter) error {
+	return func(p *ParquetWriter) error {
+		if n <= 0 {
+			return fmt.Errorf("invalid page size %d, it must be greater than 0", n)
+		}
+		p.pageBytes = n
+		return nil
+	}
+}
 
-func begin(p *ParquetWriter) error {
-	_, err := p.w.Write(par1)
-	return err
+// TargetRowGroupBytes writes the row group (as if Write was called) once
+// its estimated size (before compression) reaches n bytes.
+func TargetRowGroupBytes(n int) func(*ParquetWriter) error {
+	return func(p *ParquetWriter) error {
+		if n <= 0 {
+			return fmt.Errorf("invalid row group size %d, it must be greater than 0", n)
+		}
+		p.rowGroupBytes = n
+		return nil
+	}
 }
 
-func withMeta(m *parquet.Metadata) func(*ParquetWriter) error {
+// MaxBufferedBytes writes the row group (as if Write was called) once
+// the writer holds n bytes.  That includes the encoded pages of the row
+// group, its dictionaries and the estimated size of the page that records
+// are being added to.  The encoded pages are held until the row group is
+// written, so n bounds the size of the row groups as well.
+func MaxBufferedBytes(n int) func(*ParquetWriter) error {
 	return func(p *ParquetWriter) error {
-		p.meta = m
+		if n <= 0 {
+			return fmt.Errorf("invalid buffer size %d, it must be greater than 0", n)
+		}
+		p.maxBuffered = n
 		return nil
 	}
 }
 
+type keyValue struct {
+	key   string
+	value string
+}
+
+// KeyValue adds key and value to the file's key/value metadata.  The last
+// value wins if the same key is added more than once.
+func KeyValue(key, value string) func(*ParquetWriter) error {
+	return func(p *ParquetWriter) error {
+		p.keyValues = append(p.keyValues, keyValue{key: key, value: value})
+		return nil
+	}
+}
+
+var par1 = []byte("PAR1")
+
+// offsetWriter counts the bytes that are written to the file so the
+// footer has the offsets of the column chunks and page indexes.
+type offsetWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (w *offsetWriter) Write(b []byte) (int, error) {
+	n, err := w.w.Write(b)
+	w.n += int64(n)
+	return n, err
+}
+
+func begin(p *ParquetWriter) error {
+	_, err := p.w.Write(par1)
+	return err
+}
+
 func Uncompressed(p *ParquetWriter) error {
-	p.compression = compressionUncompressed
+	p.compression.codec = codec{compression: compressionUncompressed}
 	return nil
 }
 
 func Snappy(p *ParquetWriter) error {
-	p.compression = compressionSnappy
+	p.compression.codec = codec{compression: compressionSnappy}
 	return nil
 }
 
 func Gzip(p *ParquetWriter) error {
-	p.compression = compressionGzip
+	p.compression.codec = codec{compression: compressionGzip}
 	return nil
 }
 
-func withCompression(c compression) func(*ParquetWriter) error {
+// Zstd sets the compression of every column to zstd.
+func Zstd(p *ParquetWriter) error {
+	p.compression.codec = codec{compression: compressionZstd}
+	return nil
+}
+
+// Lz4 sets the compression of every column to LZ4_RAW.
+func Lz4(p *ParquetWriter) error {
+	p.compression.codec = codec{compression: compressionLz4}
+	return nil
+}
+
+// Brotli sets the compression of every column to brotli.
+func Brotli(p *ParquetWriter) error {
+	p.compression.codec = codec{compression: compressionBrotli}
+	return nil
+}
+
+// GzipLevel sets the compression of every column to gzip with a
+// level between 1 (fastest) and 9 (smallest).
+func GzipLevel(level int) func(*ParquetWriter) error {
+	return compressionLevel(compressionGzip, level, 1, 9)
+}
+
+// ZstdLevel sets the compression of every column to zstd with a
+// level between 1 (fastest) and 22 (smallest).
+func ZstdLevel(level int) func(*ParquetWriter) error {
+	return compressionLevel(compressionZstd, level, 1, 22)
+}
+
+// BrotliLevel sets the compression of every column to brotli with a
+// level between 1 (fastest) and 11 (smallest).
+func BrotliLevel(level int) func(*ParquetWriter) error {
+	return compressionLevel(compressionBrotli, level, 1, 11)
+}
+
+func compressionLevel(c compression, level, min, max int) func(*ParquetWriter) error {
+	return func(p *ParquetWriter) error {
+		if level < min || level > max {
+			return fmt.Errorf("invalid compression level %d, it must be between %d and %d", level, min, max)
+		}
+		p.compression.codec = codec{compression: c, level: level}
+		return nil
+	}
+}
+
+// ColumnCompression overrides the compression of a single column with
+// one of the compression options, for example
+// ColumnCompression("notes", GzipLevel(9)).
+func ColumnCompression(col string, opt func(*ParquetWriter) error) func(*ParquetWriter) error {
 	return func(p *ParquetWriter) error {
-		p.compression = c
+		c := ParquetWriter{compression: codecs{codec: codec{compression: compressionUnknown}}}
+		if err := opt(&c); err != nil {
+			return err
+		}
+
+		if c.compression.compression == compressionUnknown {
+			return fmt.Errorf("column %s: not a compression option", col)
+		}
+
+		if p.compression.columns == nil {
+			p.compression.columns = map[string]codec{}
+		}
+		p.compression.columns[col] = c.compression.codec
 		return nil
 	}
 }
 
+// Dictionary turns on dictionary encoding for every column that supports
+// it.  A column chunk falls back to PLAIN encoding if its dictionary gets
+// larger than parquet.MaxDictionarySize.
+func Dictionary(p *ParquetWriter) error {
+	p.dictionary = true
+	return nil
+}
+
+// DataPageV2 writes the pages as DATA_PAGE_V2 pages instead of DATA_PAGE
+// pages.
+func DataPageV2(p *ParquetWriter) error {
+	p.dataPageV2 = true
+	return nil
+}
+
 func (p *ParquetWriter) Write() error {
-	for i, f := range p.fields {
-		if err := f.Write(p.w, p.meta); err != nil {
+	if p.err != nil {
+		return p.err
+	}
+
+	// an empty row group isn't written (the row group might have just
+	// been written because of its target size)
+	if p.len == 0 && p.chunks == nil {
+		return nil
+	}
+
+	// a page that is started but empty isn't written
+	if p.len > 0 {
+		if err := p.flushPage(); err != nil {
 			return err
 		}
+	}
 
-		for child := p.child; child != nil; child = child.child {
-			if err := child.fields[i].Write(p.w, p.meta); err != nil {
-				return err
-			}
+	err := p.eachColumn(func(i int) error {
+		return p.meta.FlushColumn(&p.chunks[i], p.fields[i].Name())
+	})
+	if err != nil {
+		return err
+	}
+
+	p.meta.SetOffset(p.w.n)
+	for i := range p.chunks {
+		if _, err := p.chunks[i].WriteTo(p.w); err != nil {
+			return err
 		}
 	}
 
-	p.fields = Fields(p.compression)
-	p.child = nil
-	p.len = 0
+	p.chunks = nil
+	p.size = 0
 
 	schema := make([]parquet.Field, len(p.fields))
 	for i, f := range p.fields {
@@ -463,7 +744,66 @@ func (p *ParquetWriter) Write() error {
 	return nil
 }
 
+// flushPage encodes and compresses the current page of each column into
+// the column's chunk and starts a new page.
+func (p *ParquetWriter) flushPage() error {
+	if p.chunks == nil {
+		p.chunks = make([]bytes.Buffer, len(p.fields))
+	}
+
+	err := p.eachColumn(func(i int) error {
+		return p.fields[i].Write(&p.chunks[i], p.meta)
+	})
+
+	p.fields = Fields(p.compression, p.dictionary)
+	p.len = 0
+	return err
+}
+
+// eachColumn calls fn for each column, on up to GOMAXPROCS goroutines at
+// once, and returns the error of the first column that fails.  The
+// columns are done one after another if GOMAXPROCS is 1.
+func (p *ParquetWriter) eachColumn(fn func(i int) error) error {
+	procs := runtime.GOMAXPROCS(0)
+	if procs == 1 {
+		for i := range p.fields {
+			if err := fn(i); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	errs := make([]error, len(p.fields))
+	sem := make(chan struct{}, procs)
+	var wg sync.WaitGroup
+	for i := range p.fields {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			errs[i] = fn(i)
+		}(i)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (p *ParquetWriter) Close() error {
+	if p.err != nil {
+		return p.err
+	}
+
+	p.meta.SetOffset(p.w.n)
 	if err := p.meta.Footer(p.w); err != nil {
 		return err
 	}
@@ -473,32 +813,195 @@ func (p *ParquetWriter) Close() error {
 }
 
 func (p *ParquetWriter) Add(rec Document) {
-	if p.len == p.max {
-		if p.child == nil {
-			// an error can't happen here
-			p.child, _ = newParquetWriter(p.w, MaxPageSize(p.max), withMeta(p.meta), withCompression(p.compression))
+	p.meta.NextDoc()
+	for _, f := range p.fields {
+		f.Add(rec)
+	}
+
+	p.len++
+	p.cut()
+}
+
+// AddBatch adds all of the records in recs.  It's faster than calling Add
+// for each record since each field adds all of the records at once.
+func (p *ParquetWriter) AddBatch(recs []Document) {
+	for len(recs) > 0 {
+		n := p.room(len(recs))
+		for _, f := range p.fields {
+			f.AddBatch(recs[:n])
 		}
+		for range recs[:n] {
+			p.meta.NextDoc()
+		}
+		p.len += n
+		p.cut()
+		recs = recs[n:]
+	}
+}
+
+// AppendColumns adds rows that are already in columns.  cols must have a
+// Column (in the format that ParquetReader.ReadColumns returns) for each
+// of the file's columns, and each of them must have the same number of
+// rows.
+func (p *ParquetWriter) AppendColumns(cols []Column) error {
+	byName := make(map[string]Column, len(cols))
+	for _, c := range cols {
+		byName[c.Name] = c
+	}
+
+	ordered := make([]Column, len(p.fields))
+	rows := -1
+	for i, f := range p.fields {
+		c, ok := byName[f.Name()]
+		if !ok {
+			return fmt.Errorf("missing column %s", f.Name())
+		}
+		delete(byName, f.Name())
+
+		// a column that doesn't have any values can leave Vals nil
+		if c.Vals == nil {
+			c.Vals = reflect.Zero(reflect.TypeOf(f.Vals())).Interface()
+		}
+		if err := c.check(f); err != nil {
+			return err
+		}
+
+		n := c.rows(f)
+		if rows >= 0 && n != rows {
+			return fmt.Errorf("column %s has %d rows, but column %s has %d", f.Name(), n, p.fields[0].Name(), rows)
+		}
+		rows = n
+		ordered[i] = c
+	}
+
+	for name := range byName {
+		return fmt.Errorf("unknown column %s", name)
+	}
+
+	return p.appendColumns(ordered, rows)
+}
 
-		p.child.Add(rec)
+func (p *ParquetWriter) appendColumns(cols []Column, rows int) error {
+	for rows > 0 {
+		n := p.room(rows)
+		for i, f := range p.fields {
+			head := cols[i]
+			if n < rows {
+				head, cols[i] = cols[i].split(f, n)
+			}
+			if err := f.Append(head.Vals, head.Defs, head.Reps); err != nil {
+				return err
+			}
+		}
+		for i := 0; i < n; i++ {
+			p.meta.NextDoc()
+		}
+		p.len += n
+		p.cut()
+		rows -= n
+	}
+	return p.err
+}
+
+// room returns how many of n records can be added to the current page
+// before it has to be cut.  When there is a target size the number of
+// records is estimated from the size of the records that are already in
+// the page.
+func (p *ParquetWriter) room(n int) int {
+	if m := p.max - p.len; p.max > 0 && m < n {
+		n = m
+	}
+
+	if p.pageBytes == 0 && p.rowGroupBytes == 0 && p.maxBuffered == 0 {
+		return n
+	}
+
+	if p.len == 0 {
+		return 1
+	}
+
+	size := p.pageSize()
+	per := size/p.len + 1
+	if p.pageBytes > 0 {
+		n = fit(n, (p.pageBytes-size)/per)
+	}
+	if p.rowGroupBytes > 0 {
+		n = fit(n, (p.rowGroupBytes-p.size-size)/per)
+	}
+	if p.maxBuffered > 0 {
+		n = fit(n, (p.maxBuffered-p.buffered()-size)/per)
+	}
+	return n
+}
+
+// fit returns m if it's between 1 and n.
+func fit(n, m int) int {
+	if m < 1 {
+		return 1
+	}
+	if m < n {
+		return m
+	}
+	return n
+}
+
+// cut encodes the current page once it's full and writes the row group
+// once it reaches its target size or the writer holds too many bytes.
+func (p *ParquetWriter) cut() {
+	if p.err != nil {
 		return
 	}
 
-	p.meta.NextDoc()
-	for _, f := range p.fields {
-		f.Add(rec)
+	var size int
+	if p.pageBytes > 0 || p.rowGroupBytes > 0 || p.maxBuffered > 0 {
+		size = p.pageSize()
 	}
 
-	p.len++
+	if (p.max > 0 && p.len == p.max) || (p.pageBytes > 0 && size >= p.pageBytes) {
+		p.size += size
+		size = 0
+		if p.err = p.flushPage(); p.err != nil {
+			return
+		}
+	}
+
+	if (p.rowGroupBytes > 0 && p.size+size >= p.rowGroupBytes) || (p.maxBuffered > 0 && p.buffered()+size >= p.maxBuffered) {
+		p.err = p.Write()
+	}
+}
+
+// buffered returns the number of bytes of the row group's encoded pages
+// and dictionaries.
+func (p *ParquetWriter) buffered() int {
+	n := p.meta.DictionaryBytes()
+	for i := range p.chunks {
+		n += p.chunks[i].Len()
+	}
+	return n
+}
+
+// pageSize returns the estimated size of the current page.
+func (p *ParquetWriter) pageSize() int {
+	var size int
+	for _, f := range p.fields {
+		size += f.Size()
+	}
+	return size
 }
 
 type Field interface {
 	Add(r Document)
+	AddBatch(rs []Document)
+	Append(vals interface{}, defs, reps []uint8) error
 	Write(w io.Writer, meta *parquet.Metadata) error
 	Schema() parquet.Field
 	Scan(r *Document)
 	Read(r io.ReadSeeker, pg parquet.Page) error
 	Name() string
 	Levels() ([]uint8, []uint8)
+	SetLevels(defs, reps []uint8)
+	Vals() interface{}
+	Size() int
 }
 
 func getFields(ff []Field) map[string]Field {
@@ -510,7 +1013,7 @@ func getFields(ff []Field) map[string]Field {
 }
 
 func NewParquetReader(r io.ReadSeeker, opts ...func(*ParquetReader)) (*ParquetReader, error) {
-	ff := Fields(compressionUnknown)
+	ff := Fields(codecs{}, false)
 	pr := &ParquetReader{
 		r: r,
 	}
@@ -525,10 +1028,28 @@ func NewParquetReader(r io.ReadSeeker, opts ...func(*ParquetReader)) (*ParquetRe
 		schema[i] = f.Schema()
 	}
 
+	if pr.concurrency > 1 {
+		ra, ok := r.(io.ReaderAt)
+		if !ok {
+			return nil, fmt.Errorf("concurrency needs a reader that is an io.ReaderAt")
+		}
+		size, err := r.Seek(0, io.SeekEnd)
+		if err != nil {
+			return nil, err
+		}
+		pr.ra, pr.size = ra, size
+	}
+
 	meta := parquet.New(schema...)
 	if err := meta.ReadFooter(r); err != nil {
 		return nil, err
 	}
+	if err := meta.Filter(r, pr.filters...); err != nil {
+		return nil, err
+	}
+	if err := meta.Project(pr.columns...); err != nil {
+		return nil, err
+	}
 	pr.rows = meta.Rows()
 	var err error
 	pr.pages, err = meta.Pages()
@@ -543,7 +1064,15 @@ func NewParquetReader(r io.ReadSeeker, opts ...func(*ParquetReader)) (*ParquetRe
 	}
 	pr.meta = meta
 
-	return pr, pr.readRowGroup()
+	return pr, pr.readRowGroup(0)
+}
+
+// NewParquetReaderAt returns a reader that reads the parquet file of size
+// bytes from r.  It's the same as NewParquetReader except that it doesn't
+// need an io.ReadSeeker, so it can be used with the Concurrency option
+// on any io.ReaderAt.
+func NewParquetReaderAt(r io.ReaderAt, size int64, opts ...func(*ParquetReader)) (*ParquetReader, error) {
+	return NewParquetReader(io.NewSectionReader(r, 0, size), opts...)
 }
 
 func readerIndex(i int) func(*ParquetReader) {
@@ -552,6 +1081,41 @@ func readerIndex(i int) func(*ParquetReader) {
 	}
 }
 
+// Where makes the reader only read the rows whose value in column col
+// (like "age" or "hobby.name") matches pred, for example
+// Where("age", parquet.Gt(int32(30))).  Row groups and pages that can't
+// have a matching row are skipped using the file's statistics and page
+// indexes.  A reader with more than one Where only reads the rows that
+// match all of them.
+func Where(col string, pred parquet.Predicate) func(*ParquetReader) {
+	return func(p *ParquetReader) {
+		p.filters = append(p.filters, parquet.Filter{Column: col, Predicate: pred})
+	}
+}
+
+// Columns makes the reader only read the columns in cols, like
+// Columns("id", "friends.list.element.name").  A group's name (like
+// "friends") selects all of the group's columns, and a map's value
+// column is always read along with its key column.  The column chunks
+// of the other columns aren't read and Scan leaves their fields empty.
+func Columns(cols ...string) func(*ParquetReader) {
+	return func(p *ParquetReader) {
+		p.columns = append(p.columns, cols...)
+	}
+}
+
+// Concurrency makes the reader decode (decompress the pages of and scan
+// the rows of) up to n row groups at once, each on its own goroutine.
+// Next and Scan still return the rows in order.  The reader's io.ReadSeeker
+// must also be an io.ReaderAt (like an *os.File or a *bytes.Reader), or
+// the reader must be created with NewParquetReaderAt.  Close stops the
+// goroutines if the reader isn't read to the end.
+func Concurrency(n int) func(*ParquetReader) {
+	return func(p *ParquetReader) {
+		p.concurrency = n
+	}
+}
+
 // ParquetReader reads one page from a row group.
 type ParquetReader struct {
 	fields         map[string]Field
@@ -563,10 +1127,21 @@ type ParquetReader struct {
 	rowGroupCount  int64
 	pages          map[string][]parquet.Page
 	meta           *parquet.Metadata
+	filters        []parquet.Filter
+	columns        []string
 	err            error
 
 	r         io.ReadSeeker
 	rowGroups []parquet.RowGroup
+	// rowGroup is the index of the next row group to read.
+	rowGroup int
+
+	// ra is used instead of r when row groups are decoded on more than
+	// one goroutine, since each goroutine needs its own reader.
+	ra          io.ReaderAt
+	size        int64
+	concurrency int
+	pipeline    *pipeline
 }
 
 type Levels struct {
@@ -590,43 +1165,429 @@ func (p *ParquetReader) Levels() []Levels {
 	return out
 }
 
+// KeyValueMetadata returns the key/value metadata of the file.
+func (p *ParquetReader) KeyValueMetadata() map[string]string {
+	return p.meta.KeyValueMetadata()
+}
+
 func (p *ParquetReader) Error() error {
 	return p.err
 }
 
-func (p *ParquetReader) readRowGroup() error {
+// Column holds the decoded values and levels of a column of a row group.
+type Column struct {
+	Name string
+	// Vals is a slice of the column's Go type (like []int64 or []string)
+	// that has the column's values.  The values of an optional column
+	// don't include its nulls, so there is one value for each of Defs that
+	// is equal to the column's max definition level.
+	Vals interface{}
+	// Defs and Reps are the definition and repetition levels of the
+	// column.  They are nil for a required column.
+	Defs []uint8
+	Reps []uint8
+}
+
+// check returns an error if c's values and levels don't fit field f.
+func (c Column) check(f Field) error {
+	if reflect.TypeOf(c.Vals) != reflect.TypeOf(f.Vals()) {
+		return fmt.Errorf("can't append a %T to column %s, it needs a %T", c.Vals, f.Name(), f.Vals())
+	}
+
+	types := f.Schema().Types
+	def, rep := maxDef(types), maxRep(types)
+	if def == 0 {
+		if len(c.Defs) > 0 || len(c.Reps) > 0 {
+			return fmt.Errorf("column %s is required, so it can't have levels", f.Name())
+		}
+		return nil
+	}
+
+	var n int
+	for _, d := range c.Defs {
+		if d > def {
+			return fmt.Errorf("column %s has a definition level of %d, which is more than its max of %d", f.Name(), d, def)
+		}
+		if d == def {
+			n++
+		}
+	}
+	if vals := reflect.ValueOf(c.Vals).Len(); n != vals {
+		return fmt.Errorf("column %s has %d values, but its definition levels have %d", f.Name(), vals, n)
+	}
+
+	if rep == 0 {
+		if len(c.Reps) > 0 {
+			return fmt.Errorf("column %s isn't repeated, so it can't have repetition levels", f.Name())
+		}
+		return nil
+	}
+
+	if len(c.Reps) != len(c.Defs) {
+		return fmt.Errorf("column %s has %d repetition levels and %d definition levels", f.Name(), len(c.Reps), len(c.Defs))
+	}
+	for i, r := range c.Reps {
+		if r > rep || (i == 0 && r != 0) {
+			return fmt.Errorf("column %s has an invalid repetition level of %d at %d", f.Name(), r, i)
+		}
+	}
+	return nil
+}
+
+// rows returns the number of rows in c, which has been checked against
+// field f.
+func (c Column) rows(f Field) int {
+	types := f.Schema().Types
+	switch {
+	case maxDef(types) == 0:
+		return reflect.ValueOf(c.Vals).Len()
+	case maxRep(types) == 0:
+		return len(c.Defs)
+	}
+
+	var n int
+	for _, r := range c.Reps {
+		if r == 0 {
+			n++
+		}
+	}
+	return n
+}
+
+// split splits c (which has been checked against field f) into its first
+// n rows and the rest of its rows.
+func (c Column) split(f Field, n int) (Column, Column) {
+	vals := reflect.ValueOf(c.Vals)
+	types := f.Schema().Types
+	def := maxDef(types)
+	if def == 0 {
+		return Column{Name: c.Name, Vals: vals.Slice(0, n).Interface()},
+			Column{Name: c.Name, Vals: vals.Slice(n, vals.Len()).Interface()}
+	}
+
+	l := n
+	if maxRep(types) > 0 {
+		l = len(c.Reps)
+		var rows int
+		for i, r := range c.Reps {
+			if r == 0 {
+				if rows == n {
+					l = i
+					break
+				}
+				rows++
+			}
+		}
+	}
+
+	var v int
+	for _, d := range c.Defs[:l] {
+		if d == def {
+			v++
+		}
+	}
+
+	head := Column{Name: c.Name, Vals: vals.Slice(0, v).Interface(), Defs: c.Defs[:l]}
+	tail := Column{Name: c.Name, Vals: vals.Slice(v, vals.Len()).Interface(), Defs: c.Defs[l:]}
+	if c.Reps != nil {
+		head.Reps, tail.Reps = c.Reps[:l], c.Reps[l:]
+	}
+	return head, tail
+}
+
+// ReadColumns returns the columns of the rest of the current row group
+// (or of the next row group if all of the current row group's rows have
+// been read) without building any structs.  It returns io.EOF once all of
+// the rows have been read.  The columns that aren't read because of the
+// Columns option are left out.
+func (p *ParquetReader) ReadColumns() ([]Column, error) {
+	if p.err != nil {
+		return nil, p.err
+	}
+	if p.eof() {
+		return nil, io.EOF
+	}
+	if p.rowGroupCursor >= p.rowGroupCount {
+		if p.err = p.readRowGroup(0); p.err != nil {
+			return nil, p.err
+		}
+	}
+
+	var out []Column
+	for _, name := range p.fieldNames {
+		if !p.meta.Projected(name) {
+			continue
+		}
+		f := p.fields[name]
+		defs, reps := f.Levels()
+		out = append(out, Column{Name: name, Vals: f.Vals(), Defs: defs, Reps: reps})
+	}
+
+	p.cursor += p.rowGroupCount - p.rowGroupCursor
+	p.rowGroupCursor = p.rowGroupCount
+	return out, nil
+}
+
+// readRowGroup reads the next row group, skipping its first skip rows.
+func (p *ParquetReader) readRowGroup(skip int64) error {
 	p.rowGroupCursor = 0
 
-	if len(p.rowGroups) == 0 {
+	if p.rowGroup >= len(p.rowGroups) {
 		p.rowGroupCount = 0
 		return nil
 	}
 
-	rg := p.rowGroups[0]
-	p.fields = getFields(Fields(compressionUnknown))
-	p.rowGroupCount = rg.Rows
-	p.rowGroupCursor = 0
+	var rg decodedRowGroup
+	if p.ra != nil {
+		if p.pipeline == nil {
+			p.pipeline = p.decodeRowGroups(p.rowGroup, skip)
+		}
+		rg = <-<-p.pipeline.results
+	} else {
+		rg = p.decodeRowGroup(p.r, p.rowGroup, skip)
+	}
+	if rg.err != nil {
+		return rg.err
+	}
+
+	p.fields = rg.fields
+	p.rowGroupCount = rg.rows
+	p.rowGroup++
+	return nil
+}
+
+// decodedRowGroup holds the fields of a row group that has been read.
+type decodedRowGroup struct {
+	fields map[string]Field
+	rows   int64
+	err    error
+}
+
+// decodeRowGroup reads row group i, skipping its first skip rows.  It
+// doesn't change the state of the reader, so more than one row group
+// can be decoded at once (as long as each is given its own r).
+func (p *ParquetReader) decodeRowGroup(r io.ReadSeeker, i int, skip int64) decodedRowGroup {
+	rg := p.rowGroups[i]
+	fields := getFields(Fields(codecs{}, false))
 	for _, col := range rg.Columns() {
 		name := strings.Join(col.MetaData.PathInSchema, ".")
-		f, ok := p.fields[name]
+		f, ok := fields[name]
 		if !ok {
-			return fmt.Errorf("unknown field: %s", name)
+			return decodedRowGroup{err: fmt.Errorf("unknown field: %s", name)}
 		}
 		pages := p.pages[name]
-		if len(pages) <= p.index {
+		if len(pages) <= i+p.index {
 			break
 		}
 
-		pg := pages[0]
-		if err := f.Read(p.r, pg); err != nil {
-			return fmt.Errorf("unable to read field %s, err: %s", f.Name(), err)
+		if !p.meta.Projected(name) {
+			continue
+		}
+		pg, err := p.meta.SkipRows(r, pages[i], skip)
+		if err != nil {
+			return decodedRowGroup{err: fmt.Errorf("unable to seek field %s, err: %s", f.Name(), err)}
+		}
+		if err := f.Read(r, pg); err != nil {
+			return decodedRowGroup{err: fmt.Errorf("unable to read field %s, err: %s", f.Name(), err)}
+		}
+	}
+
+	// the columns that weren't read get the levels they need in order to
+	// build the nested structs of the columns that were.
+	for _, name := range p.fieldNames {
+		if src, ok := p.meta.LevelsSource(name); ok {
+			defs, reps := fields[src].Levels()
+			fields[name].SetLevels(p.meta.DeriveLevels(name, defs, reps))
+		}
+	}
+	return decodedRowGroup{fields: fields, rows: rg.Rows - skip}
+}
+
+// pipeline decodes row groups on goroutines.  The results of the row
+// groups are sent to results in order.
+type pipeline struct {
+	results chan chan decodedRowGroup
+	done    chan struct{}
+	once    sync.Once
+}
+
+// stop stops the pipeline from decoding any more row groups.
+func (pl *pipeline) stop() {
+	pl.once.Do(func() { close(pl.done) })
+}
+
+// decodeRowGroups decodes the row groups starting at row group start
+// (skipping its first skip rows) on p.concurrency goroutines.
+func (p *ParquetReader) decodeRowGroups(start int, skip int64) *pipeline {
+	pl := &pipeline{
+		results: make(chan chan decodedRowGroup, p.concurrency-1),
+		done:    make(chan struct{}),
+	}
+
+	go func() {
+		for i := start; i < len(p.rowGroups); i++ {
+			res := make(chan decodedRowGroup, 1)
+			select {
+			case pl.results <- res:
+			case <-pl.done:
+				return
+			}
+
+			go func(i int, skip int64) {
+				res <- p.decodeRowGroup(io.NewSectionReader(p.ra, 0, p.size), i, skip)
+			}(i, skip)
+			skip = 0
+		}
+	}()
+
+	return pl
+}
+
+// Close stops the goroutines of a reader that has the Concurrency option
+// or that is being read with Unordered.  It doesn't close the underlying
+// io.ReadSeeker or io.ReaderAt.
+func (p *ParquetReader) Close() error {
+	if p.pipeline != nil {
+		p.pipeline.stop()
+		p.pipeline = nil
+	}
+	return nil
+}
+
+// Unordered returns a channel that gets all of the rows that haven't been
+// read yet.  The row groups are decoded on the goroutines of the
+// Concurrency option (or one goroutine if it isn't used) and each row
+// group's rows are sent as soon as the row group is decoded, so while
+// the rows of a row group are in order, the row groups aren't.  The
+// channel is closed after the last row or after an error, which is
+// returned by Error.  Next and Scan can't be used once Unordered is
+// called.
+func (p *ParquetReader) Unordered() <-chan Document {
+	p.Close()
+	out := make(chan Document)
+	if p.err != nil {
+		close(out)
+		return out
+	}
+
+	pl := &pipeline{done: make(chan struct{})}
+	p.pipeline = pl
+
+	send := func(fields map[string]Field, rows int64) bool {
+		for i := int64(0); i < rows; i++ {
+			var x Document
+			for _, name := range p.fieldNames {
+				fields[name].Scan(&x)
+			}
+			select {
+			case out <- x:
+			case <-pl.done:
+				return false
+			}
+		}
+		return true
+	}
+
+	n := p.concurrency
+	if n < 1 || p.ra == nil {
+		n = 1
+	}
+
+	jobs := make(chan int)
+	var once sync.Once
+	var wg sync.WaitGroup
+	wg.Add(n)
+	for i := 0; i < n; i++ {
+		go func() {
+			defer wg.Done()
+			r := p.r
+			if p.ra != nil {
+				r = io.NewSectionReader(p.ra, 0, p.size)
+			}
+			for i := range jobs {
+				rg := p.decodeRowGroup(r, i, 0)
+				if rg.err != nil {
+					once.Do(func() { p.err = rg.err })
+					pl.stop()
+					return
+				}
+				if !send(rg.fields, rg.rows) {
+					return
+				}
+			}
+		}()
+	}
+
+	fields, rows, start := p.fields, p.rowGroupCount-p.rowGroupCursor, p.rowGroup
+	go func() {
+		defer close(out)
+		go func() {
+			defer close(jobs)
+			for i := start; i < len(p.rowGroups); i++ {
+				select {
+				case jobs <- i:
+				case <-pl.done:
+					return
+				}
+			}
+		}()
+
+		send(fields, rows)
+		wg.Wait()
+	}()
+
+	p.cursor = p.rows
+	return out
+}
+
+// NumRowGroups returns the number of row groups that the reader reads
+// (row groups that don't have any rows that match the reader's Where
+// options are skipped).
+func (p *ParquetReader) NumRowGroups() int {
+	return len(p.rowGroups)
+}
+
+// SeekToRowGroup makes the next call to Next return the first row of
+// row group i.
+func (p *ParquetReader) SeekToRowGroup(i int) error {
+	if i < 0 || i >= len(p.rowGroups) {
+		return fmt.Errorf("row group %d is out of range, the reader has %d row groups", i, len(p.rowGroups))
+	}
+
+	var n int64
+	for _, rg := range p.rowGroups[:i] {
+		n += rg.Rows
+	}
+	return p.seek(i, n, 0)
+}
+
+// SeekToRow makes the next call to Next return row n (counting from 0).
+// The row counts of the row groups are used to find the row group that
+// has the row, and if the file has an OffsetIndex the pages before the
+// one that has the row aren't read.
+func (p *ParquetReader) SeekToRow(n int64) error {
+	if n < 0 || n >= p.rows {
+		return fmt.Errorf("row %d is out of range, the reader has %d rows", n, p.rows)
+	}
+
+	var start int64
+	for i, rg := range p.rowGroups {
+		if n < start+rg.Rows {
+			return p.seek(i, n, n-start)
 		}
-		p.pages[name] = p.pages[name][1:]
+		start += rg.Rows
 	}
-	p.rowGroups = p.rowGroups[1:]
 	return nil
 }
 
+func (p *ParquetReader) seek(rowGroup int, cursor, skip int64) error {
+	p.Close()
+	p.rowGroup = rowGroup
+	p.cursor = cursor
+	p.err = p.readRowGroup(skip)
+	return p.err
+}
+
 func (p *ParquetReader) Rows() int64 {
 	return p.rows
 }
@@ -636,7 +1597,7 @@ func (p *ParquetReader) Next() bool {
 		return false
 	}
 	if p.rowGroupCursor >= p.rowGroupCount {
-		p.err = p.readRowGroup()
+		p.err = p.readRowGroup(0)
 		if p.err != nil {
 			return false
 		}
@@ -647,6 +1608,57 @@ func (p *ParquetReader) Next() bool {
 	return true
 }
 
+// eof returns true if all of the reader's rows have been read.
+func (p *ParquetReader) eof() bool {
+	return p.cursor >= p.rows || (p.rowGroupCursor >= p.rowGroupCount && p.rowGroup >= len(p.rowGroups))
+}
+
+// ReadBatch reads up to len(dst) rows into dst and returns the number of
+// rows that were read.  It's faster than calling Next and Scan for each
+// row since it scans one field at a time for all of the rows.  It returns
+// io.EOF once all of the rows have been read.
+func (p *ParquetReader) ReadBatch(dst []Document) (int, error) {
+	var n int
+	for n < len(dst) {
+		if p.err != nil {
+			return n, p.err
+		}
+		if p.eof() {
+			if n == 0 {
+				return 0, io.EOF
+			}
+			break
+		}
+		if p.rowGroupCursor >= p.rowGroupCount {
+			if p.err = p.readRowGroup(0); p.err != nil {
+				return n, p.err
+			}
+			continue
+		}
+
+		k := len(dst) - n
+		if rest := int(p.rowGroupCount - p.rowGroupCursor); rest < k {
+			k = rest
+		}
+
+		batch := dst[n : n+k]
+		for i := range batch {
+			batch[i] = Document{}
+		}
+		for _, name := range p.fieldNames {
+			f := p.fields[name]
+			for i := range batch {
+				f.Scan(&batch[i])
+			}
+		}
+
+		n += k
+		p.cursor += int64(k)
+		p.rowGroupCursor += int64(k)
+	}
+	return n, nil
+}
+
 func (p *ParquetReader) Scan(x *Document) {
 	if p.err != nil {
 		return
@@ -720,10 +1732,40 @@ func (f *Int64Field) Add(r Document) {
 	f.vals = append(f.vals, v)
 }
 
+func (f *Int64Field) AddBatch(rs []Document) {
+	for _, r := range rs {
+		v := f.read(r)
+		f.stats.add(v)
+		f.vals = append(f.vals, v)
+	}
+}
+
+func (f *Int64Field) Append(vals interface{}, defs, reps []uint8) error {
+	v, ok := vals.([]int64)
+	if !ok {
+		return fmt.Errorf("can't append a %T to column %s, it needs a []int64", vals, f.Name())
+	}
+	for _, x := range v {
+		f.stats.add(x)
+	}
+	f.vals = append(f.vals, v...)
+	return nil
+}
+
+func (f *Int64Field) Vals() interface{} {
+	return f.vals
+}
+
 func (f *Int64Field) Levels() ([]uint8, []uint8) {
 	return nil, nil
 }
 
+func (f *Int64Field) SetLevels(defs, reps []uint8) {}
+
+func (f *Int64Field) Size() int {
+	return len(f.vals) * 8
+}
+
 type Int64OptionalField struct {
 	parquet.OptionalField
 	vals  []int64
@@ -742,7 +1784,7 @@ func NewInt64OptionalField(read func(r Document, vals []int64, defs, reps []uint
 }
 
 func (f *Int64OptionalField) Schema() parquet.Field {
-	return parquet.Field{Name: f.Name(), Path: f.Path(), Type: Int64Type, RepetitionType: f.RepetitionType, Types: f.Types}
+	return parquet.Field{Name: f.Name(), Path: f.Path(), Type: Int64Type, RepetitionType: f.RepetitionType, Types: f.Types, Groups: f.Groups}
 }
 
 func (f *Int64OptionalField) Write(w io.Writer, meta *parquet.Metadata) error {
@@ -792,16 +1834,53 @@ func (f *Int64OptionalField) Scan(r *Document) {
 	}
 }
 
+func (f *Int64OptionalField) AddBatch(rs []Document) {
+	n, l := len(f.vals), len(f.Defs)
+	for _, r := range rs {
+		f.vals, f.Defs, f.Reps = f.read(r, f.vals, f.Defs, f.Reps)
+	}
+	f.stats.add(f.vals[n:], f.Defs[l:])
+}
+
+func (f *Int64OptionalField) Append(vals interface{}, defs, reps []uint8) error {
+	v, ok := vals.([]int64)
+	if !ok {
+		return fmt.Errorf("can't append a %T to column %s, it needs a []int64", vals, f.Name())
+	}
+	f.stats.add(v, defs)
+	f.vals = append(f.vals, v...)
+	f.Defs = append(f.Defs, defs...)
+	f.Reps = append(f.Reps, reps...)
+	return nil
+}
+
+func (f *Int64OptionalField) Vals() interface{} {
+	return f.vals
+}
+
 func (f *Int64OptionalField) Levels() ([]uint8, []uint8) {
 	return f.Defs, f.Reps
 }
 
+func (f *Int64OptionalField) SetLevels(defs, reps []uint8) {
+	f.Defs = defs
+	f.Reps = reps
+	f.vals = make([]int64, f.Values())
+}
+
+func (f *Int64OptionalField) Size() int {
+	return len(f.vals)*8 + f.LevelsSize()
+}
+
 type StringOptionalField struct {
 	parquet.OptionalField
 	vals  []string
 	read  func(r Document, vals []string, def, rep []uint8) ([]string, []uint8, []uint8)
 	write func(r *Document, vals []string, def, rep []uint8) (int, int)
 	stats *stringOptionalStats
+	// size is the size of the first sized values
+	size  int
+	sized int
 }
 
 func NewStringOptionalField(read func(r Document, vals []string, def, rep []uint8) ([]string, []uint8, []uint8), write func(r *Document, vals []string, defs, reps []uint8) (int, int), path []string, types []int, opts ...func(*parquet.OptionalField)) *StringOptionalField {
@@ -814,7 +1893,7 @@ func NewStringOptionalField(read func(r Document, vals []string, def, rep []uint
 }
 
 func (f *StringOptionalField) Schema() parquet.Field {
-	return parquet.Field{Name: f.Name(), Path: f.Path(), Type: StringType, RepetitionType: f.RepetitionType, Types: f.Types}
+	return parquet.Field{Name: f.Name(), Path: f.Path(), Type: StringType, RepetitionType: f.RepetitionType, Types: f.Types, Groups: f.Groups}
 }
 
 func (f *StringOptionalField) Add(r Document) {
@@ -875,10 +1954,48 @@ func (f *StringOptionalField) Read(r io.ReadSeeker, pg parquet.Page) error {
 	return nil
 }
 
+func (f *StringOptionalField) AddBatch(rs []Document) {
+	n, l := len(f.vals), len(f.Defs)
+	for _, r := range rs {
+		f.vals, f.Defs, f.Reps = f.read(r, f.vals, f.Defs, f.Reps)
+	}
+	f.stats.add(f.vals[n:], f.Defs[l:])
+}
+
+func (f *StringOptionalField) Append(vals interface{}, defs, reps []uint8) error {
+	v, ok := vals.([]string)
+	if !ok {
+		return fmt.Errorf("can't append a %T to column %s, it needs a []string", vals, f.Name())
+	}
+	f.stats.add(v, defs)
+	f.vals = append(f.vals, v...)
+	f.Defs = append(f.Defs, defs...)
+	f.Reps = append(f.Reps, reps...)
+	return nil
+}
+
+func (f *StringOptionalField) Vals() interface{} {
+	return f.vals
+}
+
 func (f *StringOptionalField) Levels() ([]uint8, []uint8) {
 	return f.Defs, f.Reps
 }
 
+func (f *StringOptionalField) SetLevels(defs, reps []uint8) {
+	f.Defs = defs
+	f.Reps = reps
+	f.vals = make([]string, f.Values())
+}
+
+func (f *StringOptionalField) Size() int {
+	for _, s := range f.vals[f.sized:] {
+		f.size += 4 + len(s)
+	}
+	f.sized = len(f.vals)
+	return f.size + f.LevelsSize()
+}
+
 type int64stats struct {
 	min int64
 	max int64
@@ -887,6 +2004,7 @@ type int64stats struct {
 func newInt64stats() *int64stats {
 	return &int64stats{
 		min: int64(math.MaxInt64),
+		max: math.MinInt64,
 	}
 }
 
@@ -932,6 +2050,7 @@ type int64optionalStats struct {
 func newint64optionalStats(d uint8) *int64optionalStats {
 	return &int64optionalStats{
 		min:    int64(math.MaxInt64),
+		max:    math.MinInt64,
 		maxDef: d,
 	}
 }
@@ -1049,14 +2168,17 @@ func (s *stringOptionalStats) Max() []byte {
 	return []byte(s.max)
 }
 
-func pint32(i int32) *int32       { return &i }
-func puint32(i uint32) *uint32    { return &i }
-func pint64(i int64) *int64       { return &i }
-func puint64(i uint64) *uint64    { return &i }
-func pbool(b bool) *bool          { return &b }
-func pstring(s string) *string    { return &s }
-func pfloat32(f float32) *float32 { return &f }
-func pfloat64(f float64) *float64 { return &f }
+func pint32(i int32) *int32            { return &i }
+func puint32(i uint32) *uint32         { return &i }
+func pint64(i int64) *int64            { return &i }
+func puint64(i uint64) *uint64         { return &i }
+func pbool(b bool) *bool               { return &b }
+func pstring(s string) *string         { return &s }
+func pfloat32(f float32) *float32      { return &f }
+func pfloat64(f float64) *float64      { return &f }
+func ptimeTime(t time.Time) *time.Time { return &t }
+func pbigInt(b big.Int) *big.Int       { return &b }
+func pbytes(b []byte) *[]byte          { return &b }
 
 // keeps track of the indices of repeated fields
 // that have already been handled by a previous field
@@ -1072,6 +2194,16 @@ func (i indices) rep(rep uint8) {
 	}
 }
 
+func maxRep(types []int) uint8 {
+	var out uint8
+	for _, typ := range types {
+		if typ == 2 {
+			out++
+		}
+	}
+	return out
+}
+
 func maxDef(types []int) uint8 {
 	var out uint8
 	for _, typ := range types {
@@ -1124,4 +2256,12 @@ func BoolType(se *sch.SchemaElement) {
 func StringType(se *sch.SchemaElement) {
 	t := sch.Type_BYTE_ARRAY
 	se.Type = &t
+	ct := sch.ConvertedType_UTF8
+	se.ConvertedType = &ct
+	se.LogicalType = &sch.LogicalType{STRING: sch.NewStringType()}
+}
+
+func BytesType(se *sch.SchemaElement) {
+	t := sch.Type_BYTE_ARRAY
+	se.Type = &t
 }
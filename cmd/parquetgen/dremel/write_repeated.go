@@ -30,7 +30,7 @@ type writeRepeatedInput struct {
 func init() {
 	funcs := template.FuncMap{
 		"removeStar": func(s string) string {
-			return strings.Replace(strings.Replace(s, "*", "", 1), "[]", "", 1)
+			return strings.Replace(s, "*", "", 1)
 		},
 		"newDefCase": func(def int, f fields.Field) defCase {
 			return defCase{Def: def, Field: f}
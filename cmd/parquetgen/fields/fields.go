@@ -2,6 +2,7 @@ package fields
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
 )
 
@@ -17,6 +18,29 @@ type Field struct {
 	Embedded       bool
 	NthChild       int
 	Defined        bool
+	// Encoding is set by the encoding option of a field's struct tag,
+	// for example `parquet:"id,encoding=delta_binary_packed"`
+	Encoding string
+	// Codec is set by the codec option of a field's struct tag,
+	// for example `parquet:"notes,codec=zstd"`
+	Codec string
+	// TimeUnit, UTC and Date are set by the unit, utc and date options
+	// of a time.Time field's struct tag, for example
+	// `parquet:"ts,unit=millis,utc"` or `parquet:"birthday,date"`
+	TimeUnit string
+	UTC      bool
+	Date     bool
+	// Decimal is set by the decimal option of a field's struct tag,
+	// for example `parquet:"price,decimal=18:2"` (precision:scale)
+	Decimal string
+	// UUID is set by the uuid option of a [16]byte field's struct tag,
+	// for example `parquet:"id,uuid"`
+	UUID bool
+	// List is set on a repeated field that is written as a LIST group
+	// that holds a repeated group named list whose only child, element,
+	// is the field's value (unless parquetgen's -legacy-lists flag is
+	// set, which writes a repeated field on its own).
+	List bool
 }
 
 type input struct {
@@ -110,6 +134,9 @@ func (f Field) ColumnNames() []string {
 		if fld.ColumnName != "" {
 			out = append(out, fld.ColumnName)
 		}
+		if fld.List {
+			out = append(out, "list", "element")
+		}
 	}
 	return out
 }
@@ -122,6 +149,21 @@ func (f Field) RepetitionTypes() RepetitionTypes {
 	return out[1:]
 }
 
+// ColumnTypes returns the repetition types of the groups and column in
+// the field's column path, where a list's LIST group and element are
+// required and its list group is repeated.
+func (f Field) ColumnTypes() RepetitionTypes {
+	var out []RepetitionType
+	for _, fld := range Reverse(f.Chain())[1:] {
+		if fld.List {
+			out = append(out, Required, Repeated, Required)
+		} else {
+			out = append(out, fld.RepetitionType)
+		}
+	}
+	return out
+}
+
 // DefIndex calculates the index of the
 // nested field with the given definition level.
 func (f Field) DefIndex(def int) int {
@@ -400,15 +442,15 @@ func (f Field) Init(def, rep int) string {
 		case Optional:
 			if fld.Primitive() {
 				if f.NthChild == 0 && fld.Parent.Optional() && !fld.Parent.Repeated() {
-					right = fmt.Sprintf(right, fmt.Sprintf("%s: p%s(vals[0])%%s", fld.Name, fld.Type))
+					right = fmt.Sprintf(right, fmt.Sprintf("%s: %s(vals[0])%%s", fld.Name, fld.PointerFunc()))
 				} else if fld.Parent.RepetitionType == Repeated {
-					right = fmt.Sprintf(right, fmt.Sprintf("p%s(vals[nVals])%%s", fld.Type))
+					right = fmt.Sprintf(right, fmt.Sprintf("%s(vals[nVals])%%s", fld.PointerFunc()))
 				} else if fld.Parent.Repeated() && f.NthChild == 0 {
-					right = fmt.Sprintf(right, fmt.Sprintf("%s: p%s(vals[nVals])%%s", fld.Name, fld.Type))
+					right = fmt.Sprintf(right, fmt.Sprintf("%s: %s(vals[nVals])%%s", fld.Name, fld.PointerFunc()))
 				} else if fld.Parent.Repeated() && f.NthChild > 0 {
-					right = fmt.Sprintf(right, fmt.Sprintf("p%s(vals[nVals])%%s", fld.Type))
+					right = fmt.Sprintf(right, fmt.Sprintf("%s(vals[nVals])%%s", fld.PointerFunc()))
 				} else {
-					right = fmt.Sprintf(right, fmt.Sprintf("p%s(vals[0])%%s", fld.Type))
+					right = fmt.Sprintf(right, fmt.Sprintf("%s(vals[0])%%s", fld.PointerFunc()))
 				}
 			} else {
 				if j == 0 {
@@ -476,7 +518,21 @@ func (f Field) Path() string {
 
 func (f Field) Primitive() bool {
 	_, ok := primitiveTypes[f.Type]
-	return ok
+	return ok || f.FixedLength() > 0
+}
+
+// FixedLength is N if the field is a [N]byte, which is written as a
+// FIXED_LEN_BYTE_ARRAY, and 0 otherwise.
+func (f Field) FixedLength() int {
+	if !strings.HasPrefix(f.Type, "[") || !strings.HasSuffix(f.Type, "]byte") {
+		return 0
+	}
+
+	n, err := strconv.Atoi(f.Type[1 : len(f.Type)-5])
+	if err != nil || n < 1 {
+		return 0
+	}
+	return n
 }
 
 func (f Field) FieldType() string {
@@ -485,12 +541,12 @@ func (f Field) FieldType() string {
 		op = "Optional"
 	}
 
-	ft := primitiveTypes[f.Type]
+	ft := f.fieldType()
 	return fmt.Sprintf(ft.name, op, "Field")
 }
 
 func (f Field) ParquetType() string {
-	ft := primitiveTypes[f.Type]
+	ft := f.fieldType()
 	return fmt.Sprintf(ft.name, "", "Type")
 }
 
@@ -500,10 +556,126 @@ func (f Field) Category() string {
 		op = "Optional"
 	}
 
-	ft := primitiveTypes[f.Type]
+	ft := f.fieldType()
 	return fmt.Sprintf(ft.category, op)
 }
 
+func (f Field) fieldType() fieldType {
+	if f.Date {
+		return dateType
+	}
+	if ft, ok := decimalTypes[f.Type]; ok && f.Decimal != "" {
+		return ft
+	}
+	if n := f.FixedLength(); n > 0 {
+		return fieldType{fmt.Sprintf("Fixed%d%%s%%s", n), "fixed%s"}
+	}
+	return primitiveTypes[f.Type]
+}
+
+// IsMap reports whether the field is a map, which is written as a MAP
+// group that holds a repeated key_value group with a key and a value.
+func (f Field) IsMap() bool {
+	return strings.HasPrefix(f.Type, "map[")
+}
+
+// Map returns the map field that f is the key or value of.
+func (f Field) Map() (Field, bool) {
+	if f.Parent == nil || f.Parent.Parent == nil || !f.Parent.Parent.IsMap() {
+		return Field{}, false
+	}
+	return *f.Parent.Parent, true
+}
+
+// IsMapKey reports whether f is the key of a map field.
+func (f Field) IsMapKey() bool {
+	_, ok := f.Map()
+	return ok && f.NthChild == 0
+}
+
+// MapKeys is the name of the variable that the generated code uses to
+// pass the keys of a map from its key column to its value column (or
+// "" if f isn't the key or value of a map).
+func (f Field) MapKeys() string {
+	m, ok := f.Map()
+	if !ok {
+		return ""
+	}
+	return "keys" + strings.Join(m.FieldNames(), "")
+}
+
+// Groups creates gocode for the FieldFuncs of the groups in the field's
+// path (or "" if none of them have a logical type).
+func (f Field) Groups() string {
+	var out []string
+	var n int
+	for _, fld := range Reverse(f.Chain())[1:] {
+		if fld.List {
+			out = append(out, "parquet.ListType", "nil", "nil")
+			n = len(out) - 2
+			continue
+		}
+		if fld.Primitive() {
+			break
+		}
+		if fld.IsMap() {
+			out = append(out, "parquet.MapType")
+			n = len(out)
+		} else {
+			out = append(out, "nil")
+		}
+	}
+	return strings.Join(out[:n], ", ")
+}
+
+// mapKeyTypes are the types that a map's keys can be.
+var mapKeyTypes = map[string]bool{
+	"int32":   true,
+	"uint32":  true,
+	"int64":   true,
+	"uint64":  true,
+	"float32": true,
+	"float64": true,
+	"string":  true,
+}
+
+// CheckMap returns an error if f is the key of a map whose key or
+// value isn't supported or that is inside of a repeated field.
+func (f Field) CheckMap() error {
+	m, ok := f.Map()
+	if !ok || !f.IsMapKey() {
+		return nil
+	}
+
+	if !mapKeyTypes[f.Type] {
+		return fmt.Errorf("unsupported key type %s for map field %s", f.Type, m.Name)
+	}
+
+	v := f.Parent.Children[1]
+	if !v.Primitive() || v.RepetitionType == Repeated {
+		return fmt.Errorf("unsupported value type for map field %s of type %s", m.Name, m.Type)
+	}
+
+	for _, rt := range m.RepetitionTypes() {
+		if rt == Repeated {
+			return fmt.Errorf("map field %s can't be inside of a repeated field", m.Name)
+		}
+	}
+	return nil
+}
+
+// PointerFunc is the name of the generated function that returns
+// a pointer to a value of the field's type (for example pint32).
+func (f Field) PointerFunc() string {
+	if n := f.FixedLength(); n > 0 {
+		return fmt.Sprintf("pfixed%d", n)
+	}
+	if f.Type == "[]byte" {
+		return "pbytes"
+	}
+	return "p" + strings.Replace(f.Type, ".", "", -1)
+}
+
 func (f Field) TypeName() string {
 	var star string
 	if f.RepetitionType == Optional {
@@ -512,6 +684,153 @@ func (f Field) TypeName() string {
 	return fmt.Sprintf("%s%s", star, f.Type)
 }
 
+// encodings are the encodings that can be set with a struct tag
+// along with the types that support them.
+var encodings = map[string][]string{
+	"delta_binary_packed":     {"int32", "uint32", "int64", "uint64"},
+	"delta_length_byte_array": {"string", "[]byte"},
+	"delta_byte_array":        {"string", "[]byte"},
+	"byte_stream_split":       {"float32", "float64"},
+}
+
+// CheckEncoding returns an error if the field's Encoding
+// isn't supported by its type.
+func (f Field) CheckEncoding() error {
+	if f.Encoding == "" {
+		return nil
+	}
+
+	types, ok := encodings[f.Encoding]
+	if !ok {
+		return fmt.Errorf("unknown encoding %s for field %s", f.Encoding, f.Name)
+	}
+
+	for _, t := range types {
+		if t == f.Type {
+			return nil
+		}
+	}
+	return fmt.Errorf("encoding %s is not supported for field %s of type %s", f.Encoding, f.Name, f.Type)
+}
+
+// timeUnits are the units that can be set with the unit option of a
+// time.Time field's struct tag.
+var timeUnits = map[string]bool{
+	"millis": true,
+	"micros": true,
+	"nanos":  true,
+}
+
+// CheckTime returns an error if the field's TimeUnit, UTC and Date
+// don't go together or don't go with its type.
+func (f Field) CheckTime() error {
+	if f.TimeUnit == "" && !f.UTC && !f.Date {
+		return nil
+	}
+
+	if f.Type != "time.Time" {
+		return fmt.Errorf("the unit, utc and date options are not supported for field %s of type %s", f.Name, f.Type)
+	}
+
+	if f.TimeUnit != "" && !timeUnits[f.TimeUnit] {
+		return fmt.Errorf("unknown unit %s for field %s", f.TimeUnit, f.Name)
+	}
+
+	if f.Date && (f.TimeUnit != "" || f.UTC) {
+		return fmt.Errorf("field %s is a date, so it can't have a unit or be utc", f.Name)
+	}
+	return nil
+}
+
+// maxPrecisions are the largest precisions of the types that
+// can have the decimal option (0 means there is no limit).
+var maxPrecisions = map[string]int{
+	"int32":   9,
+	"int64":   18,
+	"big.Int": 0,
+}
+
+// Precision is the precision set by the decimal option.
+func (f Field) Precision() int {
+	p, _, _ := f.decimal()
+	return p
+}
+
+// Scale is the scale set by the decimal option.
+func (f Field) Scale() int {
+	_, s, _ := f.decimal()
+	return s
+}
+
+func (f Field) decimal() (int, int, error) {
+	parts := strings.Split(f.Decimal, ":")
+	if len(parts) == 2 {
+		p, perr := strconv.Atoi(parts[0])
+		s, serr := strconv.Atoi(parts[1])
+		if perr == nil && serr == nil {
+			return p, s, nil
+		}
+	}
+	return 0, 0, fmt.Errorf("invalid decimal %s for field %s, it must be precision:scale (like 18:2)", f.Decimal, f.Name)
+}
+
+// CheckDecimal returns an error if the field's Decimal isn't a valid
+// precision and scale for its type.  A big.Int field must have one.
+func (f Field) CheckDecimal() error {
+	if f.Decimal == "" {
+		if f.Type == "big.Int" {
+			return fmt.Errorf("field %s of type big.Int needs the decimal option", f.Name)
+		}
+		return nil
+	}
+
+	max, ok := maxPrecisions[f.Type]
+	if !ok {
+		return fmt.Errorf("the decimal option is not supported for field %s of type %s", f.Name, f.Type)
+	}
+
+	p, s, err := f.decimal()
+	if err != nil {
+		return err
+	}
+
+	if p < 1 || (max > 0 && p > max) {
+		return fmt.Errorf("invalid precision %d for field %s of type %s", p, f.Name, f.Type)
+	}
+
+	if s < 0 || s > p {
+		return fmt.Errorf("invalid scale %d for field %s, it must be between 0 and the precision", s, f.Name)
+	}
+	return nil
+}
+
+// CheckUUID returns an error if a field that isn't a [16]byte
+// has the uuid option.
+func (f Field) CheckUUID() error {
+	if f.UUID && f.FixedLength() != 16 {
+		return fmt.Errorf("the uuid option is not supported for field %s of type %s", f.Name, f.Type)
+	}
+	return nil
+}
+
+// codecs are the compression codecs that can be set with a struct tag.
+var codecs = map[string]bool{
+	"uncompressed": true,
+	"snappy":       true,
+	"gzip":         true,
+	"zstd":         true,
+	"lz4":          true,
+	"brotli":       true,
+}
+
+// CheckCodec returns an error if the field's Codec is unknown.
+func (f Field) CheckCodec() error {
+	if f.Codec == "" || codecs[f.Codec] {
+		return nil
+	}
+	return fmt.Errorf("unknown codec %s for field %s", f.Codec, f.Name)
+}
+
 type fieldType struct {
 	name     string
 	category string
@@ -526,6 +845,21 @@ var primitiveTypes = map[string]fieldType{
 	"float64": {"Float64%s%s", "numeric%s"},
 	"bool":    {"Bool%s%s", "bool%s"},
 	"string":  {"String%s%s", "string%s"},
+	"[]byte":  {"Bytes%s%s", "bytes%s"},
+	// a [N]byte is a FixedN (see FixedLength)
+	// a time.Time is a Date if it has the date option
+	"time.Time": {"Time%s%s", "time%s"},
+	// a big.Int must have the decimal option
+	"big.Int": {"BigDecimal%s%s", "bigDecimal%s"},
+}
+
+var dateType = fieldType{"Date%s%s", "time%s"}
+
+// decimalTypes are the field types of the fields with the decimal option.
+var decimalTypes = map[string]fieldType{
+	"int32":   {"Decimal32%s%s", "decimal%s"},
+	"int64":   {"Decimal64%s%s", "decimal%s"},
+	"big.Int": {"BigDecimal%s%s", "bigDecimal%s"},
 }
 
 func max(i []int) int {
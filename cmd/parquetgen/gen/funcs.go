@@ -13,13 +13,13 @@ import (
 var (
 	funcs = template.FuncMap{
 		"removeStar": func(s string) string {
-			return strings.Replace(strings.Replace(s, "*", "", 1), "[]", "", 1)
+			return strings.Replace(s, "*", "", 1)
 		},
 		"camelCase": func(s string) string {
 			return cases.Camel(s)
 		},
 		"camelCaseRemoveStar": func(s string) string {
-			return cases.Camel(strings.Replace(strings.Replace(s, "*", "", 1), "[]", "", 1))
+			return cases.Camel(strings.Replace(s, "*", "", 1))
 		},
 		"dedupe": dedupe,
 		"compressionFunc": func(f fields.Field) string {
@@ -28,6 +28,21 @@ var (
 			}
 			return "fieldCompression"
 		},
+		"dictionaryFunc": func(f fields.Field) string {
+			if strings.Contains(f.Category(), "Optional") {
+				return "optionalFieldDictionary"
+			}
+			return "fieldDictionary"
+		},
+		"encodingFunc": func(f fields.Field) string {
+			if strings.Contains(f.Category(), "Optional") {
+				return "parquet.OptionalField" + cases.Camel(f.Encoding)
+			}
+			return "parquet.RequiredField" + cases.Camel(f.Encoding)
+		},
+		"codecConst": func(f fields.Field) string {
+			return "compression" + cases.Camel(f.Codec)
+		},
 		"funcName": func(f fields.Field) string {
 			return strings.Join(f.FieldNames(), "")
 		},
@@ -59,6 +74,22 @@ var (
 			}
 			return out
 		},
+		"minType": func(f fields.Field) string {
+			var out string
+			switch f.Type {
+			case "int32", "*int32":
+				out = "math.MinInt32"
+			case "int64", "*int64":
+				out = "math.MinInt64"
+			case "uint32", "*uint32", "uint64", "*uint64":
+				out = "0"
+			case "float32", "*float32":
+				out = "-math.MaxFloat32"
+			case "float64", "*float64":
+				out = "-math.MaxFloat64"
+			}
+			return out
+		},
 		"columnName":    func(f fields.Field) string { return strings.Join(f.ColumnNames(), ".") },
 		"writeFunc":     dremel.Write,
 		"readFunc":      dremel.Read,
@@ -77,6 +108,11 @@ var (
 				out = "4"
 			case "int64", "*int64", "uint64", "*uint64", "float64", "*float64":
 				out = "8"
+			case "time.Time":
+				out = "8"
+				if f.Date {
+					out = "4"
+				}
 			}
 			return out
 		},
@@ -88,6 +124,11 @@ var (
 				out = "PutUint32"
 			case "int64", "*int64", "uint64", "*uint64", "float64", "*float64":
 				out = "PutUint64"
+			case "time.Time":
+				out = "PutUint64"
+				if f.Date {
+					out = "PutUint32"
+				}
 			}
 			return out
 		},
@@ -119,8 +160,55 @@ var (
 				out = "math.Float64bits(v)"
 			case "*float64":
 				out = "math.Float64bits(*v)"
+			case "time.Time":
+				out = "uint64(f.unit.Int64(v, f.utc))"
+				if f.Date {
+					out = "uint32(f.unit.Int64(v, f.utc))"
+				}
 			}
 			return out
 		},
+		// timeInt and timeUint are the types that the values of a
+		// time.Time field are written as.
+		"timeInt": func(f fields.Field) string {
+			if f.Date {
+				return "int32"
+			}
+			return "int64"
+		},
+		"timeUint": func(f fields.Field) string {
+			if f.Date {
+				return "uint32"
+			}
+			return "uint64"
+		},
+		"statsName": func(f fields.Field) string {
+			s := strings.TrimSuffix(f.FieldType(), "Field")
+			return strings.ToLower(s[:1]) + s[1:] + "Stats"
+		},
+		"decimalType": func(f fields.Field) string {
+			if f.Type == "int32" {
+				return "sch.Type_INT32"
+			}
+			return "sch.Type_INT64"
+		},
+		"fixedType": func(f fields.Field) string {
+			if f.UUID {
+				return "parquet.UUIDType"
+			}
+			return fmt.Sprintf("parquet.FixedType(%d)", f.FixedLength())
+		},
+		"timeUnit": func(f fields.Field) string {
+			if f.Date {
+				return "parquet.Days"
+			}
+			switch f.TimeUnit {
+			case "millis":
+				return "parquet.Millis"
+			case "nanos":
+				return "parquet.Nanos"
+			}
+			return "parquet.Micros"
+		},
 	}
 )
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"go/format"
 	"os"
+	"strings"
 	"text/template"
 
 	"github.com/parsyl/parquet"
@@ -26,8 +27,9 @@ var (
 )
 
 // FromStruct generates a parquet reader and writer based on the struct
-// of type 'typ' that is defined in the go file at 'pth'.
-func FromStruct(pth, outPth, typ, pkg, imp string, ignore bool) error {
+// of type 'typ' that is defined in the go file at 'pth'.  Its repeated
+// fields are written as LISTs unless legacyLists is true.
+func FromStruct(pth, outPth, typ, pkg, imp string, ignore, legacyLists bool) error {
 	result, err := parse.Fields(typ, pth)
 	if err != nil {
 		return err
@@ -37,6 +39,10 @@ func FromStruct(pth, outPth, typ, pkg, imp string, ignore bool) error {
 		return fmt.Errorf("not generating parquet.go (-ignore set to false), err: %v", result.Errors)
 	}
 
+	if !legacyLists {
+		lists(&result.Parent)
+	}
+
 	i := input{
 		Package: pkg,
 		Type:    typ,
@@ -64,6 +70,26 @@ func FromStruct(pth, outPth, typ, pkg, imp string, ignore bool) error {
 		boolOptionalStatsTpl,
 		stringStatsTpl,
 		stringOptionalStatsTpl,
+		timeTpl,
+		timeOptionalTpl,
+		timeStatsTpl,
+		timeOptionalStatsTpl,
+		decimalTpl,
+		decimalOptionalTpl,
+		decimalStatsTpl,
+		decimalOptionalStatsTpl,
+		bigDecimalTpl,
+		bigDecimalOptionalTpl,
+		bigDecimalStatsTpl,
+		bigDecimalOptionalStatsTpl,
+		bytesTpl,
+		bytesOptionalTpl,
+		bytesStatsTpl,
+		bytesOptionalStatsTpl,
+		fixedTpl,
+		fixedOptionalTpl,
+		fixedStatsTpl,
+		fixedOptionalStatsTpl,
 	} {
 		var err error
 		tmpl, err = tmpl.Parse(t)
@@ -98,7 +124,7 @@ func FromStruct(pth, outPth, typ, pkg, imp string, ignore bool) error {
 
 // FromParquet generates a go struct, a reader, and a writer based
 // on the parquet file at 'parq'
-func FromParquet(parq, pth, outPth, typ, pkg, imp string, ignore bool) error {
+func FromParquet(parq, pth, outPth, typ, pkg, imp string, ignore, legacyLists bool) error {
 	pf, err := os.Open(parq)
 	if err != nil {
 		return err
@@ -122,6 +148,10 @@ func FromParquet(parq, pth, outPth, typ, pkg, imp string, ignore bool) error {
 		Structs: structs.Struct(typ, footer.Schema),
 	}
 
+	if strings.Contains(n.Structs, "big.Int") {
+		n.Imports = append(n.Imports, "math/big")
+	}
+
 	var buf bytes.Buffer
 	err = tmpl.Execute(&buf, n)
 	if err != nil {
@@ -144,7 +174,19 @@ func FromParquet(parq, pth, outPth, typ, pkg, imp string, ignore bool) error {
 	}
 
 	f.Close()
-	return FromStruct(pth, outPth, typ, pkg, imp, ignore)
+	return FromStruct(pth, outPth, typ, pkg, imp, ignore, legacyLists)
+}
+
+// lists sets List on the repeated fields under f, but not on the
+// key_value groups of its maps.
+func lists(f *fields.Field) {
+	for i := range f.Children {
+		ch := &f.Children[i]
+		if ch.RepetitionType == fields.Repeated && !f.IsMap() {
+			ch.List = true
+		}
+		lists(ch)
+	}
 }
 
 type input struct {
@@ -198,6 +240,7 @@ func getImport(i string) string {
 
 type newStruct struct {
 	Package string
+	Imports []string
 	Structs string
 	Fields  []fields.Field
 }
@@ -59,9 +59,35 @@ func (f *BoolField) Add(r {{.StructType}}) {
 	f.vals = append(f.vals, v)
 }
 
+func (f *BoolField) AddBatch(rs []{{.StructType}}) {
+	for _, r := range rs {
+		v := f.read(r)
+		f.vals = append(f.vals, v)
+	}
+}
+
+func (f *BoolField) Append(vals interface{}, defs, reps []uint8) error {
+	v, ok := vals.([]bool)
+	if !ok {
+		return fmt.Errorf("can't append a %T to column %s, it needs a []bool", vals, f.Name())
+	}
+	f.vals = append(f.vals, v...)
+	return nil
+}
+
+func (f *BoolField) Vals() interface{} {
+	return f.vals
+}
+
 func (f *BoolField) Levels() ([]uint8, []uint8) {
 	return nil, nil
 }
+
+func (f *BoolField) SetLevels(defs, reps []uint8) {}
+
+func (f *BoolField) Size() int {
+	return (len(f.vals) + 7) / 8
+}
 {{end}}`
 
 var boolStatsTpl = `{{define "boolStats"}}
@@ -18,7 +18,7 @@ func NewBoolOptionalField(read func(r {{.StructType}}, vals []{{removeStar .Type
 }
 
 func (f *BoolOptionalField) Schema() parquet.Field {
-	return parquet.Field{Name: f.Name(), Path: f.Path(), Type: BoolType, RepetitionType: f.RepetitionType, Types: f.Types}
+	return parquet.Field{Name: f.Name(), Path: f.Path(), Type: BoolType, RepetitionType: f.RepetitionType, Types: f.Types, Groups: f.Groups}
 }
 
 func (f *BoolOptionalField) Read(r io.ReadSeeker, pg parquet.Page) error {
@@ -67,9 +67,43 @@ func (f *BoolOptionalField) Write(w io.Writer, meta *parquet.Metadata) error {
 	return f.DoWrite(w, meta, rawBuf, len(f.Defs), f.stats)
 }
 
+func (f *BoolOptionalField) AddBatch(rs []{{.StructType}}) {
+	n, l := len(f.vals), len(f.Defs)
+	for _, r := range rs {
+		f.vals, f.Defs, f.Reps = f.read(r, f.vals, f.Defs, f.Reps)
+	}
+	f.stats.add(f.vals[n:], f.Defs[l:])
+}
+
+func (f *BoolOptionalField) Append(vals interface{}, defs, reps []uint8) error {
+	v, ok := vals.([]bool)
+	if !ok {
+		return fmt.Errorf("can't append a %T to column %s, it needs a []bool", vals, f.Name())
+	}
+	f.stats.add(v, defs)
+	f.vals = append(f.vals, v...)
+	f.Defs = append(f.Defs, defs...)
+	f.Reps = append(f.Reps, reps...)
+	return nil
+}
+
+func (f *BoolOptionalField) Vals() interface{} {
+	return f.vals
+}
+
 func (f *BoolOptionalField) Levels() ([]uint8, []uint8) {
 	return f.Defs, f.Reps
 }
+
+func (f *BoolOptionalField) SetLevels(defs, reps []uint8) {
+	f.Defs = defs
+	f.Reps = reps
+	f.vals = make([]bool, f.Values())
+}
+
+func (f *BoolOptionalField) Size() int {
+	return (len(f.vals) + 7) / 8 + f.LevelsSize()
+}
 {{end}}`
 
 var boolOptionalStatsTpl = `{{define "boolOptionalStats"}}
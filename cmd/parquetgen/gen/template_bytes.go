@@ -0,0 +1,336 @@
+package gen
+
+var bytesTpl = `{{define "bytesField"}}
+type BytesField struct {
+	parquet.RequiredField
+	vals  [][]byte
+	read  func(r {{.StructType}}) []byte
+	write func(r *{{.StructType}}, vals [][]byte)
+	stats *bytesStats
+	// size is the size of the first sized values
+	size  int
+	sized int
+}
+
+func NewBytesField(read func(r {{.StructType}}) []byte, write func(r *{{.StructType}}, vals [][]byte), path []string, opts ...func(*parquet.RequiredField)) *BytesField {
+	return &BytesField{
+		read:          read,
+		write:         write,
+		RequiredField: parquet.NewRequiredField(path, opts...),
+		stats:         newBytesStats(),
+	}
+}
+
+func (f *BytesField) Schema() parquet.Field {
+	return parquet.Field{Name: f.Name(), Path: f.Path(), Type: BytesType, RepetitionType: parquet.RepetitionRequired, Types: []int{0}}
+}
+
+func (f *BytesField) Write(w io.Writer, meta *parquet.Metadata) error {
+	buf := buffpool.Get()
+	defer buffpool.Put(buf)
+
+	bs := make([]byte, 4)
+	for _, b := range f.vals {
+		binary.LittleEndian.PutUint32(bs, uint32(len(b)))
+		if _, err := buf.Write(bs); err != nil {
+			return err
+		}
+		buf.Write(b)
+	}
+
+	return f.DoWrite(w, meta, buf.Bytes(), len(f.vals), f.stats)
+}
+
+func (f *BytesField) Read(r io.ReadSeeker, pg parquet.Page) error {
+	rr, _, err := f.DoRead(r, pg)
+	if err != nil {
+		return err
+	}
+
+	for j := 0; j < pg.N; j++ {
+		var x int32
+		if err := binary.Read(rr, binary.LittleEndian, &x); err != nil {
+			return err
+		}
+		// an empty value is read as a nil []byte
+		var b []byte
+		if x > 0 {
+			b = make([]byte, x)
+			if _, err := io.ReadFull(rr, b); err != nil {
+				return err
+			}
+		}
+
+		f.vals = append(f.vals, b)
+	}
+	return nil
+}
+
+func (f *BytesField) Scan(r *{{.StructType}}) {
+	if len(f.vals) == 0 {
+		return
+	}
+
+	f.write(r, f.vals)
+	f.vals = f.vals[1:]
+}
+
+// Add copies the record's value since the record might be reused.
+func (f *BytesField) Add(r {{.StructType}}) {
+	v := append([]byte{}, f.read(r)...)
+	f.stats.add(v)
+	f.vals = append(f.vals, v)
+}
+
+func (f *BytesField) AddBatch(rs []{{.StructType}}) {
+	for _, r := range rs {
+		f.Add(r)
+	}
+}
+
+func (f *BytesField) Append(vals interface{}, defs, reps []uint8) error {
+	v, ok := vals.([][]byte)
+	if !ok {
+		return fmt.Errorf("can't append a %T to column %s, it needs a [][]byte", vals, f.Name())
+	}
+	for _, x := range v {
+		f.stats.add(x)
+	}
+	f.vals = append(f.vals, v...)
+	return nil
+}
+
+func (f *BytesField) Vals() interface{} {
+	return f.vals
+}
+
+func (f *BytesField) Levels() ([]uint8, []uint8) {
+	return nil, nil
+}
+
+func (f *BytesField) SetLevels(defs, reps []uint8) {}
+
+func (f *BytesField) Size() int {
+	for _, b := range f.vals[f.sized:] {
+		f.size += 4 + len(b)
+	}
+	f.sized = len(f.vals)
+	return f.size
+}
+{{end}}`
+
+var bytesStatsTpl = `{{define "bytesStats"}}
+type bytesStats struct {
+	min []byte
+	max []byte
+}
+
+func newBytesStats() *bytesStats {
+	return &bytesStats{}
+}
+
+func (s *bytesStats) add(val []byte) {
+	if s.min == nil || bytes.Compare(val, s.min) < 0 {
+		s.min = append([]byte{}, val...)
+	}
+	if s.max == nil || bytes.Compare(val, s.max) > 0 {
+		s.max = append([]byte{}, val...)
+	}
+}
+
+func (s *bytesStats) NullCount() *int64 {
+	return nil
+}
+
+func (s *bytesStats) DistinctCount() *int64 {
+	return nil
+}
+
+func (s *bytesStats) Min() []byte {
+	return s.min
+}
+
+func (s *bytesStats) Max() []byte {
+	return s.max
+}
+{{end}}`
+
+var bytesOptionalTpl = `{{define "bytesOptionalField"}}
+type BytesOptionalField struct {
+	parquet.OptionalField
+	vals  [][]byte
+	read  func(r {{.StructType}}, vals [][]byte, def, rep []uint8) ([][]byte, []uint8, []uint8)
+	write func(r *{{.StructType}}, vals [][]byte, def, rep []uint8) (int, int)
+	stats *bytesOptionalStats
+	// size is the size of the first sized values
+	size  int
+	sized int
+}
+
+func NewBytesOptionalField(read func(r {{.StructType}}, vals [][]byte, def, rep []uint8) ([][]byte, []uint8, []uint8), write func(r *{{.StructType}}, vals [][]byte, defs, reps []uint8) (int, int), path []string, types []int, opts ...func(*parquet.OptionalField)) *BytesOptionalField {
+	return &BytesOptionalField{
+		read:          read,
+		write:         write,
+		OptionalField: parquet.NewOptionalField(path, types, opts...),
+		stats:         newBytesOptionalStats(maxDef(types)),
+	}
+}
+
+func (f *BytesOptionalField) Schema() parquet.Field {
+	return parquet.Field{Name: f.Name(), Path: f.Path(), Type: BytesType, RepetitionType: f.RepetitionType, Types: f.Types, Groups: f.Groups}
+}
+
+// Add copies the record's values since the record might be reused.
+func (f *BytesOptionalField) Add(r {{.StructType}}) {
+	n, l := len(f.vals), len(f.Defs)
+	f.vals, f.Defs, f.Reps = f.read(r, f.vals, f.Defs, f.Reps)
+	for i := n; i < len(f.vals); i++ {
+		f.vals[i] = append([]byte{}, f.vals[i]...)
+	}
+	f.stats.add(f.vals[n:], f.Defs[l:])
+}
+
+func (f *BytesOptionalField) Scan(r *{{.StructType}}) {
+	if len(f.Defs) == 0 {
+		return
+	}
+
+	v, l := f.write(r, f.vals, f.Defs, f.Reps)
+	f.vals = f.vals[v:]
+	f.Defs = f.Defs[l:]
+	if len(f.Reps) > 0 {
+		f.Reps = f.Reps[l:]
+	}
+}
+
+func (f *BytesOptionalField) Write(w io.Writer, meta *parquet.Metadata) error {
+	buf := buffpool.Get()
+	defer buffpool.Put(buf)
+
+	bs := make([]byte, 4)
+	for _, b := range f.vals {
+		binary.LittleEndian.PutUint32(bs, uint32(len(b)))
+		if _, err := buf.Write(bs); err != nil {
+			return err
+		}
+		buf.Write(b)
+	}
+
+	return f.DoWrite(w, meta, buf.Bytes(), len(f.Defs), f.stats)
+}
+
+func (f *BytesOptionalField) Read(r io.ReadSeeker, pg parquet.Page) error {
+	rr, _, err := f.DoRead(r, pg)
+	if err != nil {
+		return err
+	}
+
+	for n := f.Values() - len(f.vals); n > 0; n-- {
+		var x int32
+		if err := binary.Read(rr, binary.LittleEndian, &x); err != nil {
+			return err
+		}
+		// an empty value is read as a nil []byte
+		var b []byte
+		if x > 0 {
+			b = make([]byte, x)
+			if _, err := io.ReadFull(rr, b); err != nil {
+				return err
+			}
+		}
+
+		f.vals = append(f.vals, b)
+	}
+	return nil
+}
+
+func (f *BytesOptionalField) AddBatch(rs []{{.StructType}}) {
+	for _, r := range rs {
+		f.Add(r)
+	}
+}
+
+func (f *BytesOptionalField) Append(vals interface{}, defs, reps []uint8) error {
+	v, ok := vals.([][]byte)
+	if !ok {
+		return fmt.Errorf("can't append a %T to column %s, it needs a [][]byte", vals, f.Name())
+	}
+	f.stats.add(v, defs)
+	f.vals = append(f.vals, v...)
+	f.Defs = append(f.Defs, defs...)
+	f.Reps = append(f.Reps, reps...)
+	return nil
+}
+
+func (f *BytesOptionalField) Vals() interface{} {
+	return f.vals
+}
+
+func (f *BytesOptionalField) Levels() ([]uint8, []uint8) {
+	return f.Defs, f.Reps
+}
+
+func (f *BytesOptionalField) SetLevels(defs, reps []uint8) {
+	f.Defs = defs
+	f.Reps = reps
+	f.vals = make([][]byte, f.Values())
+}
+
+func (f *BytesOptionalField) Size() int {
+	for _, b := range f.vals[f.sized:] {
+		f.size += 4 + len(b)
+	}
+	f.sized = len(f.vals)
+	return f.size + f.LevelsSize()
+}
+{{end}}`
+
+var bytesOptionalStatsTpl = `{{define "bytesOptionalStats"}}
+type bytesOptionalStats struct {
+	min    []byte
+	max    []byte
+	nils   int64
+	maxDef uint8
+}
+
+func newBytesOptionalStats(d uint8) *bytesOptionalStats {
+	return &bytesOptionalStats{
+		maxDef: d,
+	}
+}
+
+func (s *bytesOptionalStats) add(vals [][]byte, defs []uint8) {
+	var i int
+	for _, def := range defs {
+		if def < s.maxDef {
+			s.nils++
+			continue
+		}
+
+		val := vals[i]
+		i++
+		if s.min == nil || bytes.Compare(val, s.min) < 0 {
+			s.min = append([]byte{}, val...)
+		}
+		if s.max == nil || bytes.Compare(val, s.max) > 0 {
+			s.max = append([]byte{}, val...)
+		}
+	}
+}
+
+func (s *bytesOptionalStats) NullCount() *int64 {
+	return &s.nils
+}
+
+func (s *bytesOptionalStats) DistinctCount() *int64 {
+	return nil
+}
+
+func (s *bytesOptionalStats) Min() []byte {
+	return s.min
+}
+
+func (s *bytesOptionalStats) Max() []byte {
+	return s.max
+}
+{{end}}`
@@ -0,0 +1,695 @@
+package gen
+
+var decimalTpl = `{{define "decimalField"}}
+type {{.FieldType}} struct {
+	vals []{{.Type}}
+	parquet.RequiredField
+	read      func(r {{.StructType}}) {{.Type}}
+	write     func(r *{{.StructType}}, vals []{{.Type}})
+	precision int32
+	scale     int32
+	stats     *{{statsName .}}
+}
+
+func New{{.FieldType}}(read func(r {{.StructType}}) {{.Type}}, write func(r *{{.StructType}}, vals []{{.Type}}), path []string, precision, scale int32, opts ...func(*parquet.RequiredField)) *{{.FieldType}} {
+	return &{{.FieldType}}{
+		read:          read,
+		write:         write,
+		precision:     precision,
+		scale:         scale,
+		RequiredField: parquet.NewRequiredField(path, opts...),
+		stats:         new{{camelCase (statsName .)}}(),
+	}
+}
+
+func (f *{{.FieldType}}) Schema() parquet.Field {
+	return parquet.Field{Name: f.Name(), Path: f.Path(), Type: parquet.DecimalType({{decimalType .}}, f.precision, f.scale), RepetitionType: parquet.RepetitionRequired, Types: []int{0}}
+}
+
+func (f *{{.FieldType}}) Read(r io.ReadSeeker, pg parquet.Page) error {
+	if _, err := pg.Decimal({{decimalType .}}, f.precision, f.scale); err != nil {
+		return err
+	}
+
+	rr, _, err := f.DoRead(r, pg)
+	if err != nil {
+		return err
+	}
+
+	v := make([]{{.Type}}, int(pg.N))
+	err = binary.Read(rr, binary.LittleEndian, &v)
+	f.vals = append(f.vals, v...)
+	return err
+}
+
+func (f *{{.FieldType}}) Write(w io.Writer, meta *parquet.Metadata) error {
+	buf := buffpool.Get()
+	defer buffpool.Put(buf)
+
+	bs := make([]byte, {{byteSize .}})
+	for _, v := range f.vals {
+		binary.LittleEndian.{{putFunc .}}(bs, {{uintFunc .}})
+		if _, err := buf.Write(bs); err != nil {
+			return err
+		}
+	}
+	return f.DoWrite(w, meta, buf.Bytes(), len(f.vals), f.stats)
+}
+
+func (f *{{.FieldType}}) Scan(r *{{.StructType}}) {
+	if len(f.vals) == 0 {
+		return
+	}
+
+	f.write(r, f.vals)
+	f.vals = f.vals[1:]
+}
+
+func (f *{{.FieldType}}) Add(r {{.StructType}}) {
+	v := f.read(r)
+	f.stats.add(v)
+	f.vals = append(f.vals, v)
+}
+
+func (f *{{.FieldType}}) AddBatch(rs []{{.StructType}}) {
+	for _, r := range rs {
+		v := f.read(r)
+		f.stats.add(v)
+		f.vals = append(f.vals, v)
+	}
+}
+
+func (f *{{.FieldType}}) Append(vals interface{}, defs, reps []uint8) error {
+	v, ok := vals.([]{{.Type}})
+	if !ok {
+		return fmt.Errorf("can't append a %T to column %s, it needs a []{{.Type}}", vals, f.Name())
+	}
+	for _, x := range v {
+		f.stats.add(x)
+	}
+	f.vals = append(f.vals, v...)
+	return nil
+}
+
+func (f *{{.FieldType}}) Vals() interface{} {
+	return f.vals
+}
+
+func (f *{{.FieldType}}) Levels() ([]uint8, []uint8) {
+	return nil, nil
+}
+
+func (f *{{.FieldType}}) SetLevels(defs, reps []uint8) {}
+
+func (f *{{.FieldType}}) Size() int {
+	return len(f.vals) * {{byteSize .}}
+}
+{{end}}`
+
+var decimalOptionalTpl = `{{define "decimalOptionalField"}}
+type {{.FieldType}} struct {
+	parquet.OptionalField
+	vals      []{{.Type}}
+	read      func(r {{.StructType}}, vals []{{.Type}}, defs, reps []uint8) ([]{{.Type}}, []uint8, []uint8)
+	write     func(r *{{.StructType}}, vals []{{.Type}}, defs, reps []uint8) (int, int)
+	precision int32
+	scale     int32
+	stats     *{{statsName .}}
+}
+
+func New{{.FieldType}}(read func(r {{.StructType}}, vals []{{.Type}}, defs, reps []uint8) ([]{{.Type}}, []uint8, []uint8), write func(r *{{.StructType}}, vals []{{.Type}}, defs, reps []uint8) (int, int), path []string, types []int, precision, scale int32, opts ...func(*parquet.OptionalField)) *{{.FieldType}} {
+	return &{{.FieldType}}{
+		read:          read,
+		write:         write,
+		precision:     precision,
+		scale:         scale,
+		OptionalField: parquet.NewOptionalField(path, types, opts...),
+		stats:         new{{camelCase (statsName .)}}(maxDef(types)),
+	}
+}
+
+func (f *{{.FieldType}}) Schema() parquet.Field {
+	return parquet.Field{Name: f.Name(), Path: f.Path(), Type: parquet.DecimalType({{decimalType .}}, f.precision, f.scale), RepetitionType: f.RepetitionType, Types: f.Types, Groups: f.Groups}
+}
+
+func (f *{{.FieldType}}) Write(w io.Writer, meta *parquet.Metadata) error {
+	buf := buffpool.Get()
+	defer buffpool.Put(buf)
+
+	bs := make([]byte, {{byteSize .}})
+	for _, v := range f.vals {
+		binary.LittleEndian.{{putFunc .}}(bs, {{uintFunc .}})
+		if _, err := buf.Write(bs); err != nil {
+			return err
+		}
+	}
+	return f.DoWrite(w, meta, buf.Bytes(), len(f.Defs), f.stats)
+}
+
+func (f *{{.FieldType}}) Read(r io.ReadSeeker, pg parquet.Page) error {
+	if _, err := pg.Decimal({{decimalType .}}, f.precision, f.scale); err != nil {
+		return err
+	}
+
+	rr, _, err := f.DoRead(r, pg)
+	if err != nil {
+		return err
+	}
+
+	v := make([]{{.Type}}, f.Values()-len(f.vals))
+	err = binary.Read(rr, binary.LittleEndian, &v)
+	f.vals = append(f.vals, v...)
+	return err
+}
+
+func (f *{{.FieldType}}) Add(r {{.StructType}}) {
+	vals, defs, reps := f.read(r, f.vals, f.Defs, f.Reps)
+	f.stats.add(vals[len(f.vals):], defs[len(f.Defs):])
+	f.vals = vals
+	f.Defs = defs
+	f.Reps = reps
+}
+
+func (f *{{.FieldType}}) Scan(r *{{.StructType}}) {
+	if len(f.Defs) == 0 {
+		return
+	}
+
+	v, l := f.write(r, f.vals, f.Defs, f.Reps)
+	f.vals = f.vals[v:]
+	f.Defs = f.Defs[l:]
+	if len(f.Reps) > 0 {
+		f.Reps = f.Reps[l:]
+	}
+}
+
+func (f *{{.FieldType}}) AddBatch(rs []{{.StructType}}) {
+	n, l := len(f.vals), len(f.Defs)
+	for _, r := range rs {
+		f.vals, f.Defs, f.Reps = f.read(r, f.vals, f.Defs, f.Reps)
+	}
+	f.stats.add(f.vals[n:], f.Defs[l:])
+}
+
+func (f *{{.FieldType}}) Append(vals interface{}, defs, reps []uint8) error {
+	v, ok := vals.([]{{.Type}})
+	if !ok {
+		return fmt.Errorf("can't append a %T to column %s, it needs a []{{.Type}}", vals, f.Name())
+	}
+	f.stats.add(v, defs)
+	f.vals = append(f.vals, v...)
+	f.Defs = append(f.Defs, defs...)
+	f.Reps = append(f.Reps, reps...)
+	return nil
+}
+
+func (f *{{.FieldType}}) Vals() interface{} {
+	return f.vals
+}
+
+func (f *{{.FieldType}}) Levels() ([]uint8, []uint8) {
+	return f.Defs, f.Reps
+}
+
+func (f *{{.FieldType}}) SetLevels(defs, reps []uint8) {
+	f.Defs = defs
+	f.Reps = reps
+	f.vals = make([]{{.Type}}, f.Values())
+}
+
+func (f *{{.FieldType}}) Size() int {
+	return len(f.vals) * {{byteSize .}} + f.LevelsSize()
+}
+{{end}}`
+
+var decimalStatsTpl = `{{define "decimalStats"}}
+type {{statsName .}} struct {
+	min {{.Type}}
+	max {{.Type}}
+}
+
+func new{{camelCase (statsName .)}}() *{{statsName .}} {
+	return &{{statsName .}}{
+		min: {{maxType .}},
+		max: {{minType .}},
+	}
+}
+
+func (s *{{statsName .}}) add(val {{.Type}}) {
+	if val < s.min {
+		s.min = val
+	}
+	if val > s.max {
+		s.max = val
+	}
+}
+
+func (s *{{statsName .}}) bytes(v {{.Type}}) []byte {
+	bs := make([]byte, {{byteSize .}})
+	binary.LittleEndian.{{putFunc .}}(bs, {{uintFunc .}})
+	return bs
+}
+
+func (s *{{statsName .}}) NullCount() *int64 {
+	return nil
+}
+
+func (s *{{statsName .}}) DistinctCount() *int64 {
+	return nil
+}
+
+func (s *{{statsName .}}) Min() []byte {
+	return s.bytes(s.min)
+}
+
+func (s *{{statsName .}}) Max() []byte {
+	return s.bytes(s.max)
+}
+{{end}}`
+
+var decimalOptionalStatsTpl = `{{define "decimalOptionalStats"}}
+type {{statsName .}} struct {
+	min     {{.Type}}
+	max     {{.Type}}
+	nils    int64
+	nonNils int64
+	maxDef  uint8
+}
+
+func new{{camelCase (statsName .)}}(d uint8) *{{statsName .}} {
+	return &{{statsName .}}{
+		min:    {{maxType .}},
+		max:    {{minType .}},
+		maxDef: d,
+	}
+}
+
+func (s *{{statsName .}}) add(vals []{{.Type}}, defs []uint8) {
+	var i int
+	for _, def := range defs {
+		if def < s.maxDef {
+			s.nils++
+		} else {
+			val := vals[i]
+			i++
+
+			s.nonNils++
+			if val < s.min {
+				s.min = val
+			}
+			if val > s.max {
+				s.max = val
+			}
+		}
+	}
+}
+
+func (s *{{statsName .}}) bytes(v {{.Type}}) []byte {
+	bs := make([]byte, {{byteSize .}})
+	binary.LittleEndian.{{putFunc .}}(bs, {{uintFunc .}})
+	return bs
+}
+
+func (s *{{statsName .}}) NullCount() *int64 {
+	return &s.nils
+}
+
+func (s *{{statsName .}}) DistinctCount() *int64 {
+	return nil
+}
+
+func (s *{{statsName .}}) Min() []byte {
+	if s.nonNils == 0 {
+		return nil
+	}
+	return s.bytes(s.min)
+}
+
+func (s *{{statsName .}}) Max() []byte {
+	if s.nonNils == 0 {
+		return nil
+	}
+	return s.bytes(s.max)
+}
+{{end}}`
+
+var bigDecimalTpl = `{{define "bigDecimalField"}}
+type {{.FieldType}} struct {
+	vals []big.Int
+	parquet.RequiredField
+	read      func(r {{.StructType}}) big.Int
+	write     func(r *{{.StructType}}, vals []big.Int)
+	precision int32
+	scale     int32
+	size      int
+	stats     *{{statsName .}}
+}
+
+func New{{.FieldType}}(read func(r {{.StructType}}) big.Int, write func(r *{{.StructType}}, vals []big.Int), path []string, precision, scale int32, opts ...func(*parquet.RequiredField)) *{{.FieldType}} {
+	return &{{.FieldType}}{
+		read:          read,
+		write:         write,
+		precision:     precision,
+		scale:         scale,
+		size:          parquet.DecimalSize(precision),
+		RequiredField: parquet.NewRequiredField(path, opts...),
+		stats:         new{{camelCase (statsName .)}}(precision),
+	}
+}
+
+func (f *{{.FieldType}}) Schema() parquet.Field {
+	return parquet.Field{Name: f.Name(), Path: f.Path(), Type: parquet.DecimalType(sch.Type_FIXED_LEN_BYTE_ARRAY, f.precision, f.scale), RepetitionType: parquet.RepetitionRequired, Types: []int{0}}
+}
+
+// Read reads the values of a page, whose length comes from the
+// file since it might not be the one of the field's precision.
+func (f *{{.FieldType}}) Read(r io.ReadSeeker, pg parquet.Page) error {
+	size, err := pg.Decimal(sch.Type_FIXED_LEN_BYTE_ARRAY, f.precision, f.scale)
+	if err != nil {
+		return err
+	}
+
+	rr, _, err := f.DoRead(r, pg)
+	if err != nil {
+		return err
+	}
+
+	for i := 0; i < int(pg.N); i++ {
+		v, err := parquet.ReadDecimal(rr, size)
+		if err != nil {
+			return err
+		}
+		f.vals = append(f.vals, *v)
+	}
+	return nil
+}
+
+func (f *{{.FieldType}}) Write(w io.Writer, meta *parquet.Metadata) error {
+	buf := buffpool.Get()
+	defer buffpool.Put(buf)
+
+	bs := make([]byte, f.size)
+	for i := range f.vals {
+		if err := parquet.PutDecimal(bs, &f.vals[i]); err != nil {
+			return fmt.Errorf("can't write %s to column %s, it doesn't fit in %d bytes", &f.vals[i], f.Name(), f.size)
+		}
+		if _, err := buf.Write(bs); err != nil {
+			return err
+		}
+	}
+	return f.DoWrite(w, meta, buf.Bytes(), len(f.vals), f.stats)
+}
+
+func (f *{{.FieldType}}) Scan(r *{{.StructType}}) {
+	if len(f.vals) == 0 {
+		return
+	}
+
+	f.write(r, f.vals)
+	f.vals = f.vals[1:]
+}
+
+// Add copies the record's value since a big.Int shares its memory
+// with its copies and the record might be reused.
+func (f *{{.FieldType}}) Add(r {{.StructType}}) {
+	v := f.read(r)
+	v = *new(big.Int).Set(&v)
+	f.stats.add(&v)
+	f.vals = append(f.vals, v)
+}
+
+func (f *{{.FieldType}}) AddBatch(rs []{{.StructType}}) {
+	for _, r := range rs {
+		f.Add(r)
+	}
+}
+
+func (f *{{.FieldType}}) Append(vals interface{}, defs, reps []uint8) error {
+	v, ok := vals.([]big.Int)
+	if !ok {
+		return fmt.Errorf("can't append a %T to column %s, it needs a []big.Int", vals, f.Name())
+	}
+	for i := range v {
+		f.stats.add(&v[i])
+	}
+	f.vals = append(f.vals, v...)
+	return nil
+}
+
+func (f *{{.FieldType}}) Vals() interface{} {
+	return f.vals
+}
+
+func (f *{{.FieldType}}) Levels() ([]uint8, []uint8) {
+	return nil, nil
+}
+
+func (f *{{.FieldType}}) SetLevels(defs, reps []uint8) {}
+
+func (f *{{.FieldType}}) Size() int {
+	return len(f.vals) * f.size
+}
+{{end}}`
+
+var bigDecimalOptionalTpl = `{{define "bigDecimalOptionalField"}}
+type {{.FieldType}} struct {
+	parquet.OptionalField
+	vals      []big.Int
+	read      func(r {{.StructType}}, vals []big.Int, defs, reps []uint8) ([]big.Int, []uint8, []uint8)
+	write     func(r *{{.StructType}}, vals []big.Int, defs, reps []uint8) (int, int)
+	precision int32
+	scale     int32
+	size      int
+	stats     *{{statsName .}}
+}
+
+func New{{.FieldType}}(read func(r {{.StructType}}, vals []big.Int, defs, reps []uint8) ([]big.Int, []uint8, []uint8), write func(r *{{.StructType}}, vals []big.Int, defs, reps []uint8) (int, int), path []string, types []int, precision, scale int32, opts ...func(*parquet.OptionalField)) *{{.FieldType}} {
+	return &{{.FieldType}}{
+		read:          read,
+		write:         write,
+		precision:     precision,
+		scale:         scale,
+		size:          parquet.DecimalSize(precision),
+		OptionalField: parquet.NewOptionalField(path, types, opts...),
+		stats:         new{{camelCase (statsName .)}}(maxDef(types), precision),
+	}
+}
+
+func (f *{{.FieldType}}) Schema() parquet.Field {
+	return parquet.Field{Name: f.Name(), Path: f.Path(), Type: parquet.DecimalType(sch.Type_FIXED_LEN_BYTE_ARRAY, f.precision, f.scale), RepetitionType: f.RepetitionType, Types: f.Types, Groups: f.Groups}
+}
+
+func (f *{{.FieldType}}) Write(w io.Writer, meta *parquet.Metadata) error {
+	buf := buffpool.Get()
+	defer buffpool.Put(buf)
+
+	bs := make([]byte, f.size)
+	for i := range f.vals {
+		if err := parquet.PutDecimal(bs, &f.vals[i]); err != nil {
+			return fmt.Errorf("can't write %s to column %s, it doesn't fit in %d bytes", &f.vals[i], f.Name(), f.size)
+		}
+		if _, err := buf.Write(bs); err != nil {
+			return err
+		}
+	}
+	return f.DoWrite(w, meta, buf.Bytes(), len(f.Defs), f.stats)
+}
+
+// Read reads the values of a page, whose length comes from the
+// file since it might not be the one of the field's precision.
+func (f *{{.FieldType}}) Read(r io.ReadSeeker, pg parquet.Page) error {
+	size, err := pg.Decimal(sch.Type_FIXED_LEN_BYTE_ARRAY, f.precision, f.scale)
+	if err != nil {
+		return err
+	}
+
+	rr, _, err := f.DoRead(r, pg)
+	if err != nil {
+		return err
+	}
+
+	for n := f.Values() - len(f.vals); n > 0; n-- {
+		v, err := parquet.ReadDecimal(rr, size)
+		if err != nil {
+			return err
+		}
+		f.vals = append(f.vals, *v)
+	}
+	return nil
+}
+
+// Add copies the record's values since a big.Int shares its memory
+// with its copies and the record might be reused.
+func (f *{{.FieldType}}) Add(r {{.StructType}}) {
+	n, l := len(f.vals), len(f.Defs)
+	f.vals, f.Defs, f.Reps = f.read(r, f.vals, f.Defs, f.Reps)
+	for i := n; i < len(f.vals); i++ {
+		f.vals[i] = *new(big.Int).Set(&f.vals[i])
+	}
+	f.stats.add(f.vals[n:], f.Defs[l:])
+}
+
+func (f *{{.FieldType}}) Scan(r *{{.StructType}}) {
+	if len(f.Defs) == 0 {
+		return
+	}
+
+	v, l := f.write(r, f.vals, f.Defs, f.Reps)
+	f.vals = f.vals[v:]
+	f.Defs = f.Defs[l:]
+	if len(f.Reps) > 0 {
+		f.Reps = f.Reps[l:]
+	}
+}
+
+func (f *{{.FieldType}}) AddBatch(rs []{{.StructType}}) {
+	for _, r := range rs {
+		f.Add(r)
+	}
+}
+
+func (f *{{.FieldType}}) Append(vals interface{}, defs, reps []uint8) error {
+	v, ok := vals.([]big.Int)
+	if !ok {
+		return fmt.Errorf("can't append a %T to column %s, it needs a []big.Int", vals, f.Name())
+	}
+	f.stats.add(v, defs)
+	f.vals = append(f.vals, v...)
+	f.Defs = append(f.Defs, defs...)
+	f.Reps = append(f.Reps, reps...)
+	return nil
+}
+
+func (f *{{.FieldType}}) Vals() interface{} {
+	return f.vals
+}
+
+func (f *{{.FieldType}}) Levels() ([]uint8, []uint8) {
+	return f.Defs, f.Reps
+}
+
+func (f *{{.FieldType}}) SetLevels(defs, reps []uint8) {
+	f.Defs = defs
+	f.Reps = reps
+	f.vals = make([]big.Int, f.Values())
+}
+
+func (f *{{.FieldType}}) Size() int {
+	return len(f.vals) * f.size + f.LevelsSize()
+}
+{{end}}`
+
+var bigDecimalStatsTpl = `{{define "bigDecimalStats"}}
+type {{statsName .}} struct {
+	size int
+	min  *big.Int
+	max  *big.Int
+}
+
+func new{{camelCase (statsName .)}}(precision int32) *{{statsName .}} {
+	return &{{statsName .}}{
+		size: parquet.DecimalSize(precision),
+	}
+}
+
+func (s *{{statsName .}}) add(val *big.Int) {
+	if s.min == nil || val.Cmp(s.min) < 0 {
+		s.min = new(big.Int).Set(val)
+	}
+	if s.max == nil || val.Cmp(s.max) > 0 {
+		s.max = new(big.Int).Set(val)
+	}
+}
+
+func (s *{{statsName .}}) bytes(val *big.Int) []byte {
+	if val == nil {
+		return nil
+	}
+
+	bs := make([]byte, s.size)
+	if err := parquet.PutDecimal(bs, val); err != nil {
+		return nil
+	}
+	return bs
+}
+
+func (s *{{statsName .}}) NullCount() *int64 {
+	return nil
+}
+
+func (s *{{statsName .}}) DistinctCount() *int64 {
+	return nil
+}
+
+func (s *{{statsName .}}) Min() []byte {
+	return s.bytes(s.min)
+}
+
+func (s *{{statsName .}}) Max() []byte {
+	return s.bytes(s.max)
+}
+{{end}}`
+
+var bigDecimalOptionalStatsTpl = `{{define "bigDecimalOptionalStats"}}
+type {{statsName .}} struct {
+	size   int
+	min    *big.Int
+	max    *big.Int
+	nils   int64
+	maxDef uint8
+}
+
+func new{{camelCase (statsName .)}}(d uint8, precision int32) *{{statsName .}} {
+	return &{{statsName .}}{
+		size:   parquet.DecimalSize(precision),
+		maxDef: d,
+	}
+}
+
+func (s *{{statsName .}}) add(vals []big.Int, defs []uint8) {
+	var i int
+	for _, def := range defs {
+		if def < s.maxDef {
+			s.nils++
+			continue
+		}
+
+		val := &vals[i]
+		i++
+		if s.min == nil || val.Cmp(s.min) < 0 {
+			s.min = new(big.Int).Set(val)
+		}
+		if s.max == nil || val.Cmp(s.max) > 0 {
+			s.max = new(big.Int).Set(val)
+		}
+	}
+}
+
+func (s *{{statsName .}}) bytes(val *big.Int) []byte {
+	if val == nil {
+		return nil
+	}
+
+	bs := make([]byte, s.size)
+	if err := parquet.PutDecimal(bs, val); err != nil {
+		return nil
+	}
+	return bs
+}
+
+func (s *{{statsName .}}) NullCount() *int64 {
+	return &s.nils
+}
+
+func (s *{{statsName .}}) DistinctCount() *int64 {
+	return nil
+}
+
+func (s *{{statsName .}}) Min() []byte {
+	return s.bytes(s.min)
+}
+
+func (s *{{statsName .}}) Max() []byte {
+	return s.bytes(s.max)
+}
+{{end}}`
@@ -0,0 +1,307 @@
+package gen
+
+var fixedTpl = `{{define "fixedField"}}
+type {{.FieldType}} struct {
+	parquet.RequiredField
+	vals  []{{.Type}}
+	read  func(r {{.StructType}}) {{.Type}}
+	write func(r *{{.StructType}}, vals []{{.Type}})
+	typ   parquet.FieldFunc
+	stats *{{statsName .}}
+}
+
+func New{{.FieldType}}(read func(r {{.StructType}}) {{.Type}}, write func(r *{{.StructType}}, vals []{{.Type}}), path []string, typ parquet.FieldFunc, opts ...func(*parquet.RequiredField)) *{{.FieldType}} {
+	return &{{.FieldType}}{
+		read:          read,
+		write:         write,
+		typ:           typ,
+		RequiredField: parquet.NewRequiredField(path, opts...),
+		stats:         &{{statsName .}}{},
+	}
+}
+
+func (f *{{.FieldType}}) Schema() parquet.Field {
+	return parquet.Field{Name: f.Name(), Path: f.Path(), Type: f.typ, RepetitionType: parquet.RepetitionRequired, Types: []int{0}}
+}
+
+func (f *{{.FieldType}}) Read(r io.ReadSeeker, pg parquet.Page) error {
+	rr, _, err := f.DoRead(r, pg)
+	if err != nil {
+		return err
+	}
+
+	v := make([]{{.Type}}, pg.N)
+	for i := range v {
+		if _, err := io.ReadFull(rr, v[i][:]); err != nil {
+			return err
+		}
+	}
+	f.vals = append(f.vals, v...)
+	return nil
+}
+
+func (f *{{.FieldType}}) Write(w io.Writer, meta *parquet.Metadata) error {
+	buf := buffpool.Get()
+	defer buffpool.Put(buf)
+
+	for i := range f.vals {
+		if _, err := buf.Write(f.vals[i][:]); err != nil {
+			return err
+		}
+	}
+	return f.DoWrite(w, meta, buf.Bytes(), len(f.vals), f.stats)
+}
+
+func (f *{{.FieldType}}) Scan(r *{{.StructType}}) {
+	if len(f.vals) == 0 {
+		return
+	}
+
+	f.write(r, f.vals)
+	f.vals = f.vals[1:]
+}
+
+func (f *{{.FieldType}}) Add(r {{.StructType}}) {
+	v := f.read(r)
+	f.stats.add(v)
+	f.vals = append(f.vals, v)
+}
+
+func (f *{{.FieldType}}) AddBatch(rs []{{.StructType}}) {
+	for _, r := range rs {
+		f.Add(r)
+	}
+}
+
+func (f *{{.FieldType}}) Append(vals interface{}, defs, reps []uint8) error {
+	v, ok := vals.([]{{.Type}})
+	if !ok {
+		return fmt.Errorf("can't append a %T to column %s, it needs a []{{.Type}}", vals, f.Name())
+	}
+	for _, x := range v {
+		f.stats.add(x)
+	}
+	f.vals = append(f.vals, v...)
+	return nil
+}
+
+func (f *{{.FieldType}}) Vals() interface{} {
+	return f.vals
+}
+
+func (f *{{.FieldType}}) Levels() ([]uint8, []uint8) {
+	return nil, nil
+}
+
+func (f *{{.FieldType}}) SetLevels(defs, reps []uint8) {}
+
+func (f *{{.FieldType}}) Size() int {
+	return len(f.vals) * {{.FixedLength}}
+}
+{{end}}`
+
+var fixedStatsTpl = `{{define "fixedStats"}}
+type {{statsName .}} struct {
+	min *{{.Type}}
+	max *{{.Type}}
+}
+
+func (s *{{statsName .}}) add(val {{.Type}}) {
+	if s.min == nil || bytes.Compare(val[:], s.min[:]) < 0 {
+		v := val
+		s.min = &v
+	}
+	if s.max == nil || bytes.Compare(val[:], s.max[:]) > 0 {
+		v := val
+		s.max = &v
+	}
+}
+
+func (s *{{statsName .}}) NullCount() *int64 {
+	return nil
+}
+
+func (s *{{statsName .}}) DistinctCount() *int64 {
+	return nil
+}
+
+func (s *{{statsName .}}) Min() []byte {
+	if s.min == nil {
+		return nil
+	}
+	return s.min[:]
+}
+
+func (s *{{statsName .}}) Max() []byte {
+	if s.max == nil {
+		return nil
+	}
+	return s.max[:]
+}
+{{end}}`
+
+var fixedOptionalTpl = `{{define "fixedOptionalField"}}
+type {{.FieldType}} struct {
+	parquet.OptionalField
+	vals  []{{.Type}}
+	read  func(r {{.StructType}}, vals []{{.Type}}, defs, reps []uint8) ([]{{.Type}}, []uint8, []uint8)
+	write func(r *{{.StructType}}, vals []{{.Type}}, defs, reps []uint8) (int, int)
+	typ   parquet.FieldFunc
+	stats *{{statsName .}}
+}
+
+func New{{.FieldType}}(read func(r {{.StructType}}, vals []{{.Type}}, defs, reps []uint8) ([]{{.Type}}, []uint8, []uint8), write func(r *{{.StructType}}, vals []{{.Type}}, defs, reps []uint8) (int, int), path []string, types []int, typ parquet.FieldFunc, opts ...func(*parquet.OptionalField)) *{{.FieldType}} {
+	return &{{.FieldType}}{
+		read:          read,
+		write:         write,
+		typ:           typ,
+		OptionalField: parquet.NewOptionalField(path, types, opts...),
+		stats:         &{{statsName .}}{maxDef: maxDef(types)},
+	}
+}
+
+func (f *{{.FieldType}}) Schema() parquet.Field {
+	return parquet.Field{Name: f.Name(), Path: f.Path(), Type: f.typ, RepetitionType: f.RepetitionType, Types: f.Types, Groups: f.Groups}
+}
+
+func (f *{{.FieldType}}) Write(w io.Writer, meta *parquet.Metadata) error {
+	buf := buffpool.Get()
+	defer buffpool.Put(buf)
+
+	for i := range f.vals {
+		if _, err := buf.Write(f.vals[i][:]); err != nil {
+			return err
+		}
+	}
+	return f.DoWrite(w, meta, buf.Bytes(), len(f.Defs), f.stats)
+}
+
+func (f *{{.FieldType}}) Read(r io.ReadSeeker, pg parquet.Page) error {
+	rr, _, err := f.DoRead(r, pg)
+	if err != nil {
+		return err
+	}
+
+	v := make([]{{.Type}}, f.Values()-len(f.vals))
+	for i := range v {
+		if _, err := io.ReadFull(rr, v[i][:]); err != nil {
+			return err
+		}
+	}
+	f.vals = append(f.vals, v...)
+	return nil
+}
+
+func (f *{{.FieldType}}) Add(r {{.StructType}}) {
+	vals, defs, reps := f.read(r, f.vals, f.Defs, f.Reps)
+	f.stats.add(vals[len(f.vals):], defs[len(f.Defs):])
+	f.vals = vals
+	f.Defs = defs
+	f.Reps = reps
+}
+
+func (f *{{.FieldType}}) Scan(r *{{.StructType}}) {
+	if len(f.Defs) == 0 {
+		return
+	}
+
+	v, l := f.write(r, f.vals, f.Defs, f.Reps)
+	f.vals = f.vals[v:]
+	f.Defs = f.Defs[l:]
+	if len(f.Reps) > 0 {
+		f.Reps = f.Reps[l:]
+	}
+}
+
+func (f *{{.FieldType}}) AddBatch(rs []{{.StructType}}) {
+	n, l := len(f.vals), len(f.Defs)
+	for _, r := range rs {
+		f.vals, f.Defs, f.Reps = f.read(r, f.vals, f.Defs, f.Reps)
+	}
+	f.stats.add(f.vals[n:], f.Defs[l:])
+}
+
+func (f *{{.FieldType}}) Append(vals interface{}, defs, reps []uint8) error {
+	v, ok := vals.([]{{.Type}})
+	if !ok {
+		return fmt.Errorf("can't append a %T to column %s, it needs a []{{.Type}}", vals, f.Name())
+	}
+	f.stats.add(v, defs)
+	f.vals = append(f.vals, v...)
+	f.Defs = append(f.Defs, defs...)
+	f.Reps = append(f.Reps, reps...)
+	return nil
+}
+
+func (f *{{.FieldType}}) Vals() interface{} {
+	return f.vals
+}
+
+func (f *{{.FieldType}}) Levels() ([]uint8, []uint8) {
+	return f.Defs, f.Reps
+}
+
+func (f *{{.FieldType}}) SetLevels(defs, reps []uint8) {
+	f.Defs = defs
+	f.Reps = reps
+	f.vals = make([]{{.Type}}, f.Values())
+}
+
+func (f *{{.FieldType}}) Size() int {
+	return len(f.vals) * {{.FixedLength}} + f.LevelsSize()
+}
+
+func {{.PointerFunc}}(b {{.Type}}) *{{.Type}} { return &b }
+{{end}}`
+
+var fixedOptionalStatsTpl = `{{define "fixedOptionalStats"}}
+type {{statsName .}} struct {
+	min    *{{.Type}}
+	max    *{{.Type}}
+	nils   int64
+	maxDef uint8
+}
+
+func (s *{{statsName .}}) add(vals []{{.Type}}, defs []uint8) {
+	var i int
+	for _, def := range defs {
+		if def < s.maxDef {
+			s.nils++
+			continue
+		}
+
+		val := vals[i]
+		i++
+		if s.min == nil || bytes.Compare(val[:], s.min[:]) < 0 {
+			v := val
+			s.min = &v
+		}
+		if s.max == nil || bytes.Compare(val[:], s.max[:]) > 0 {
+			v := val
+			s.max = &v
+		}
+	}
+}
+
+func (s *{{statsName .}}) NullCount() *int64 {
+	return &s.nils
+}
+
+func (s *{{statsName .}}) DistinctCount() *int64 {
+	return nil
+}
+
+func (s *{{statsName .}}) Min() []byte {
+	if s.min == nil {
+		return nil
+	}
+	return s.min[:]
+}
+
+func (s *{{statsName .}}) Max() []byte {
+	if s.max == nil {
+		return nil
+	}
+	return s.max[:]
+}
+{{end}}`
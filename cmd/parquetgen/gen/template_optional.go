@@ -22,7 +22,7 @@ func New{{.FieldType}}(read func(r {{.StructType}}, vals []{{removeStar .TypeNam
 }
 
 func (f *{{.FieldType}}) Schema() parquet.Field {
-	return parquet.Field{Name: f.Name(), Path: f.Path(), Type: {{.ParquetType}}, RepetitionType: f.RepetitionType, Types: f.Types}
+	return parquet.Field{Name: f.Name(), Path: f.Path(), Type: {{.ParquetType}}, RepetitionType: f.RepetitionType, Types: f.Types, Groups: f.Groups}
 }
 
 func (f *{{.FieldType}}) Write(w io.Writer, meta *parquet.Metadata) error {
@@ -72,9 +72,43 @@ func (f *{{.FieldType}}) Scan(r *{{.StructType}}) {
 	}
 }
 
+func (f *{{.FieldType}}) AddBatch(rs []{{.StructType}}) {
+	n, l := len(f.vals), len(f.Defs)
+	for _, r := range rs {
+		f.vals, f.Defs, f.Reps = f.read(r, f.vals, f.Defs, f.Reps)
+	}
+	f.stats.add(f.vals[n:], f.Defs[l:])
+}
+
+func (f *{{.FieldType}}) Append(vals interface{}, defs, reps []uint8) error {
+	v, ok := vals.([]{{removeStar .TypeName}})
+	if !ok {
+		return fmt.Errorf("can't append a %T to column %s, it needs a []{{removeStar .TypeName}}", vals, f.Name())
+	}
+	f.stats.add(v, defs)
+	f.vals = append(f.vals, v...)
+	f.Defs = append(f.Defs, defs...)
+	f.Reps = append(f.Reps, reps...)
+	return nil
+}
+
+func (f *{{.FieldType}}) Vals() interface{} {
+	return f.vals
+}
+
 func (f *{{.FieldType}}) Levels() ([]uint8, []uint8) {
 	return f.Defs, f.Reps
 }
+
+func (f *{{.FieldType}}) SetLevels(defs, reps []uint8) {
+	f.Defs = defs
+	f.Reps = reps
+	f.vals = make([]{{removeStar .TypeName}}, f.Values())
+}
+
+func (f *{{.FieldType}}) Size() int {
+	return len(f.vals) * {{byteSize .}} + f.LevelsSize()
+}
 {{end}}`
 
 var optionalStatsTpl = `{{define "optionalStats"}}
@@ -89,6 +123,7 @@ type {{removeStar .TypeName}}optionalStats struct {
 func new{{removeStar .TypeName}}optionalStats(d uint8) *{{removeStar .TypeName}}optionalStats {
 	return &{{removeStar .TypeName}}optionalStats{
 		min: {{removeStar .TypeName}}(math.Max{{camelCaseRemoveStar .TypeName}}),
+		max: {{minType .}},
 		maxDef: d,
 	}
 }
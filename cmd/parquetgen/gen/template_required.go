@@ -63,9 +63,39 @@ func (f *{{.FieldType}}) Add(r {{.Parent.StructType}}) {
 	f.vals = append(f.vals, v)
 }
 
+func (f *{{.FieldType}}) AddBatch(rs []{{.Parent.StructType}}) {
+	for _, r := range rs {
+		v := f.read(r)
+		f.stats.add(v)
+		f.vals = append(f.vals, v)
+	}
+}
+
+func (f *{{.FieldType}}) Append(vals interface{}, defs, reps []uint8) error {
+	v, ok := vals.([]{{.TypeName}})
+	if !ok {
+		return fmt.Errorf("can't append a %T to column %s, it needs a []{{.TypeName}}", vals, f.Name())
+	}
+	for _, x := range v {
+		f.stats.add(x)
+	}
+	f.vals = append(f.vals, v...)
+	return nil
+}
+
+func (f *{{.FieldType}}) Vals() interface{} {
+	return f.vals
+}
+
 func (f *{{.FieldType}}) Levels() ([]uint8, []uint8) {
 	return nil, nil
 }
+
+func (f *{{.FieldType}}) SetLevels(defs, reps []uint8) {}
+
+func (f *{{.FieldType}}) Size() int {
+	return len(f.vals) * {{byteSize .}}
+}
 {{end}}`
 
 var requiredStatsTpl = `{{define "requiredStats"}}
@@ -77,6 +107,7 @@ type {{.TypeName}}stats struct {
 func new{{camelCase .TypeName}}stats() *{{.TypeName}}stats {
 	return &{{.TypeName}}stats{
 		min: {{.TypeName}}(math.Max{{camelCase .TypeName}}),
+		max: {{minType .}},
 	}
 }
 
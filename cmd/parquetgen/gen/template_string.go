@@ -7,6 +7,9 @@ type StringField struct {
 	read  func(r {{.StructType}}) {{.TypeName}}
 	write func(r *{{.StructType}}, vals []{{removeStar .TypeName}})
 	stats *stringStats
+	// size is the size of the first sized values
+	size  int
+	sized int
 }
 
 func NewStringField(read func(r {{.StructType}}) {{.TypeName}}, write func(r *{{.StructType}}, vals []{{removeStar .TypeName}}), path []string, opts ...func(*parquet.RequiredField)) *StringField {
@@ -74,9 +77,43 @@ func (f *StringField) Add(r {{.StructType}}) {
 	f.vals = append(f.vals, v)
 }
 
+func (f *StringField) AddBatch(rs []{{.StructType}}) {
+	for _, r := range rs {
+		v := f.read(r)
+		f.stats.add(v)
+		f.vals = append(f.vals, v)
+	}
+}
+
+func (f *StringField) Append(vals interface{}, defs, reps []uint8) error {
+	v, ok := vals.([]string)
+	if !ok {
+		return fmt.Errorf("can't append a %T to column %s, it needs a []string", vals, f.Name())
+	}
+	for _, x := range v {
+		f.stats.add(x)
+	}
+	f.vals = append(f.vals, v...)
+	return nil
+}
+
+func (f *StringField) Vals() interface{} {
+	return f.vals
+}
+
 func (f *StringField) Levels() ([]uint8, []uint8) {
 	return nil, nil
 }
+
+func (f *StringField) SetLevels(defs, reps []uint8) {}
+
+func (f *StringField) Size() int {
+	for _, s := range f.vals[f.sized:] {
+		f.size += 4 + len(s)
+	}
+	f.sized = len(f.vals)
+	return f.size
+}
 {{end}}`
 
 var stringStatsTpl = `{{define "stringStats"}}
@@ -7,6 +7,9 @@ type StringOptionalField struct {
 	read   func(r {{.StructType}}, vals []{{removeStar .TypeName}}, def, rep []uint8) ([]{{removeStar .TypeName}}, []uint8, []uint8)
 	write  func(r *{{.StructType}}, vals []{{removeStar .TypeName}}, def, rep []uint8) (int, int)
 	stats *stringOptionalStats
+	// size is the size of the first sized values
+	size  int
+	sized int
 }
 
 func NewStringOptionalField(read func(r {{.StructType}}, vals []{{removeStar .TypeName}}, def, rep []uint8) ([]{{removeStar .TypeName}}, []uint8, []uint8), write func(r *{{.StructType}}, vals []{{removeStar .TypeName}}, defs, reps []uint8) (int, int), path []string, types []int, opts ...func(*parquet.OptionalField)) *StringOptionalField {
@@ -19,7 +22,7 @@ func NewStringOptionalField(read func(r {{.StructType}}, vals []{{removeStar .Ty
 }
 
 func (f *StringOptionalField) Schema() parquet.Field {
-	return parquet.Field{Name: f.Name(), Path: f.Path(), Type: StringType, RepetitionType: f.RepetitionType, Types: f.Types}
+	return parquet.Field{Name: f.Name(), Path: f.Path(), Type: StringType, RepetitionType: f.RepetitionType, Types: f.Types, Groups: f.Groups}
 }
 
 func (f *StringOptionalField) Add(r {{.StructType}}) {
@@ -80,9 +83,47 @@ func (f *StringOptionalField) Read(r io.ReadSeeker, pg parquet.Page) error {
 	return nil
 }
 
+func (f *StringOptionalField) AddBatch(rs []{{.StructType}}) {
+	n, l := len(f.vals), len(f.Defs)
+	for _, r := range rs {
+		f.vals, f.Defs, f.Reps = f.read(r, f.vals, f.Defs, f.Reps)
+	}
+	f.stats.add(f.vals[n:], f.Defs[l:])
+}
+
+func (f *StringOptionalField) Append(vals interface{}, defs, reps []uint8) error {
+	v, ok := vals.([]string)
+	if !ok {
+		return fmt.Errorf("can't append a %T to column %s, it needs a []string", vals, f.Name())
+	}
+	f.stats.add(v, defs)
+	f.vals = append(f.vals, v...)
+	f.Defs = append(f.Defs, defs...)
+	f.Reps = append(f.Reps, reps...)
+	return nil
+}
+
+func (f *StringOptionalField) Vals() interface{} {
+	return f.vals
+}
+
 func (f *StringOptionalField) Levels() ([]uint8, []uint8) {
 	return f.Defs, f.Reps
 }
+
+func (f *StringOptionalField) SetLevels(defs, reps []uint8) {
+	f.Defs = defs
+	f.Reps = reps
+	f.vals = make([]string, f.Values())
+}
+
+func (f *StringOptionalField) Size() int {
+	for _, s := range f.vals[f.sized:] {
+		f.size += 4 + len(s)
+	}
+	f.sized = len(f.vals)
+	return f.size + f.LevelsSize()
+}
 {{end}}`
 
 var stringOptionalStatsTpl = `{{define "stringOptionalStats"}}
@@ -3,5 +3,9 @@ package gen
 var structTpl = `package {{.Package}}
 
 // This code is generated by github.com/parsyl/parquet.
-
+{{if .Imports}}
+import (
+{{range .Imports}}	"{{.}}"
+{{end}})
+{{end}}
 {{.Structs}}`
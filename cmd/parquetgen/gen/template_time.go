@@ -0,0 +1,355 @@
+package gen
+
+var timeTpl = `{{define "timeField"}}
+type {{.FieldType}} struct {
+	vals []time.Time
+	parquet.RequiredField
+	read  func(r {{.StructType}}) time.Time
+	write func(r *{{.StructType}}, vals []time.Time)
+	unit  parquet.TimeUnit
+	utc   bool
+	stats *{{statsName .}}
+}
+
+func New{{.FieldType}}(read func(r {{.StructType}}) time.Time, write func(r *{{.StructType}}, vals []time.Time), path []string, unit parquet.TimeUnit, utc bool, opts ...func(*parquet.RequiredField)) *{{.FieldType}} {
+	return &{{.FieldType}}{
+		read:          read,
+		write:         write,
+		unit:          unit,
+		utc:           utc,
+		RequiredField: parquet.NewRequiredField(path, opts...),
+		stats:         new{{camelCase (statsName .)}}(unit, utc),
+	}
+}
+
+func (f *{{.FieldType}}) Schema() parquet.Field {
+	return parquet.Field{Name: f.Name(), Path: f.Path(), Type: parquet.TimeType(f.unit, f.utc), RepetitionType: parquet.RepetitionRequired, Types: []int{0}}
+}
+
+func (f *{{.FieldType}}) Read(r io.ReadSeeker, pg parquet.Page) error {
+	unit, err := pg.TimeUnit(f.unit)
+	if err != nil {
+		return err
+	}
+
+	rr, _, err := f.DoRead(r, pg)
+	if err != nil {
+		return err
+	}
+
+	v := make([]{{timeInt .}}, int(pg.N))
+	err = binary.Read(rr, binary.LittleEndian, &v)
+	for _, x := range v {
+		f.vals = append(f.vals, unit.Time(int64(x)))
+	}
+	return err
+}
+
+func (f *{{.FieldType}}) Write(w io.Writer, meta *parquet.Metadata) error {
+	buf := buffpool.Get()
+	defer buffpool.Put(buf)
+
+	bs := make([]byte, {{byteSize .}})
+	for _, v := range f.vals {
+		if err := f.unit.CheckRange(v, f.utc); err != nil {
+			return fmt.Errorf("column %s: %s", f.Name(), err)
+		}
+		binary.LittleEndian.{{putFunc .}}(bs, {{uintFunc .}})
+		if _, err := buf.Write(bs); err != nil {
+			return err
+		}
+	}
+	return f.DoWrite(w, meta, buf.Bytes(), len(f.vals), f.stats)
+}
+
+func (f *{{.FieldType}}) Scan(r *{{.StructType}}) {
+	if len(f.vals) == 0 {
+		return
+	}
+
+	f.write(r, f.vals)
+	f.vals = f.vals[1:]
+}
+
+func (f *{{.FieldType}}) Add(r {{.StructType}}) {
+	v := f.read(r)
+	f.stats.add(v)
+	f.vals = append(f.vals, v)
+}
+
+func (f *{{.FieldType}}) AddBatch(rs []{{.StructType}}) {
+	for _, r := range rs {
+		v := f.read(r)
+		f.stats.add(v)
+		f.vals = append(f.vals, v)
+	}
+}
+
+func (f *{{.FieldType}}) Append(vals interface{}, defs, reps []uint8) error {
+	v, ok := vals.([]time.Time)
+	if !ok {
+		return fmt.Errorf("can't append a %T to column %s, it needs a []time.Time", vals, f.Name())
+	}
+	for _, x := range v {
+		f.stats.add(x)
+	}
+	f.vals = append(f.vals, v...)
+	return nil
+}
+
+func (f *{{.FieldType}}) Vals() interface{} {
+	return f.vals
+}
+
+func (f *{{.FieldType}}) Levels() ([]uint8, []uint8) {
+	return nil, nil
+}
+
+func (f *{{.FieldType}}) SetLevels(defs, reps []uint8) {}
+
+func (f *{{.FieldType}}) Size() int {
+	return len(f.vals) * {{byteSize .}}
+}
+{{end}}`
+
+var timeOptionalTpl = `{{define "timeOptionalField"}}
+type {{.FieldType}} struct {
+	parquet.OptionalField
+	vals  []time.Time
+	read  func(r {{.StructType}}, vals []time.Time, defs, reps []uint8) ([]time.Time, []uint8, []uint8)
+	write func(r *{{.StructType}}, vals []time.Time, defs, reps []uint8) (int, int)
+	unit  parquet.TimeUnit
+	utc   bool
+	stats *{{statsName .}}
+}
+
+func New{{.FieldType}}(read func(r {{.StructType}}, vals []time.Time, defs, reps []uint8) ([]time.Time, []uint8, []uint8), write func(r *{{.StructType}}, vals []time.Time, defs, reps []uint8) (int, int), path []string, types []int, unit parquet.TimeUnit, utc bool, opts ...func(*parquet.OptionalField)) *{{.FieldType}} {
+	return &{{.FieldType}}{
+		read:          read,
+		write:         write,
+		unit:          unit,
+		utc:           utc,
+		OptionalField: parquet.NewOptionalField(path, types, opts...),
+		stats:         new{{camelCase (statsName .)}}(maxDef(types), unit, utc),
+	}
+}
+
+func (f *{{.FieldType}}) Schema() parquet.Field {
+	return parquet.Field{Name: f.Name(), Path: f.Path(), Type: parquet.TimeType(f.unit, f.utc), RepetitionType: f.RepetitionType, Types: f.Types, Groups: f.Groups}
+}
+
+func (f *{{.FieldType}}) Write(w io.Writer, meta *parquet.Metadata) error {
+	buf := buffpool.Get()
+	defer buffpool.Put(buf)
+
+	bs := make([]byte, {{byteSize .}})
+	for _, v := range f.vals {
+		if err := f.unit.CheckRange(v, f.utc); err != nil {
+			return fmt.Errorf("column %s: %s", f.Name(), err)
+		}
+		binary.LittleEndian.{{putFunc .}}(bs, {{uintFunc .}})
+		if _, err := buf.Write(bs); err != nil {
+			return err
+		}
+	}
+	return f.DoWrite(w, meta, buf.Bytes(), len(f.Defs), f.stats)
+}
+
+func (f *{{.FieldType}}) Read(r io.ReadSeeker, pg parquet.Page) error {
+	unit, err := pg.TimeUnit(f.unit)
+	if err != nil {
+		return err
+	}
+
+	rr, _, err := f.DoRead(r, pg)
+	if err != nil {
+		return err
+	}
+
+	v := make([]{{timeInt .}}, f.Values()-len(f.vals))
+	err = binary.Read(rr, binary.LittleEndian, &v)
+	for _, x := range v {
+		f.vals = append(f.vals, unit.Time(int64(x)))
+	}
+	return err
+}
+
+func (f *{{.FieldType}}) Add(r {{.StructType}}) {
+	vals, defs, reps := f.read(r, f.vals, f.Defs, f.Reps)
+	f.stats.add(vals[len(f.vals):], defs[len(f.Defs):])
+	f.vals = vals
+	f.Defs = defs
+	f.Reps = reps
+}
+
+func (f *{{.FieldType}}) Scan(r *{{.StructType}}) {
+	if len(f.Defs) == 0 {
+		return
+	}
+
+	v, l := f.write(r, f.vals, f.Defs, f.Reps)
+	f.vals = f.vals[v:]
+	f.Defs = f.Defs[l:]
+	if len(f.Reps) > 0 {
+		f.Reps = f.Reps[l:]
+	}
+}
+
+func (f *{{.FieldType}}) AddBatch(rs []{{.StructType}}) {
+	n, l := len(f.vals), len(f.Defs)
+	for _, r := range rs {
+		f.vals, f.Defs, f.Reps = f.read(r, f.vals, f.Defs, f.Reps)
+	}
+	f.stats.add(f.vals[n:], f.Defs[l:])
+}
+
+func (f *{{.FieldType}}) Append(vals interface{}, defs, reps []uint8) error {
+	v, ok := vals.([]time.Time)
+	if !ok {
+		return fmt.Errorf("can't append a %T to column %s, it needs a []time.Time", vals, f.Name())
+	}
+	f.stats.add(v, defs)
+	f.vals = append(f.vals, v...)
+	f.Defs = append(f.Defs, defs...)
+	f.Reps = append(f.Reps, reps...)
+	return nil
+}
+
+func (f *{{.FieldType}}) Vals() interface{} {
+	return f.vals
+}
+
+func (f *{{.FieldType}}) Levels() ([]uint8, []uint8) {
+	return f.Defs, f.Reps
+}
+
+func (f *{{.FieldType}}) SetLevels(defs, reps []uint8) {
+	f.Defs = defs
+	f.Reps = reps
+	f.vals = make([]time.Time, f.Values())
+}
+
+func (f *{{.FieldType}}) Size() int {
+	return len(f.vals) * {{byteSize .}} + f.LevelsSize()
+}
+{{end}}`
+
+var timeStatsTpl = `{{define "timeStats"}}
+type {{statsName .}} struct {
+	unit parquet.TimeUnit
+	utc  bool
+	min  int64
+	max  int64
+}
+
+func new{{camelCase (statsName .)}}(unit parquet.TimeUnit, utc bool) *{{statsName .}} {
+	return &{{statsName .}}{
+		unit: unit,
+		utc:  utc,
+		min:  math.MaxInt64,
+		max:  math.MinInt64,
+	}
+}
+
+func (s *{{statsName .}}) add(t time.Time) {
+	val := s.unit.Int64(t, s.utc)
+	if val < s.min {
+		s.min = val
+	}
+	if val > s.max {
+		s.max = val
+	}
+}
+
+func (s *{{statsName .}}) bytes(val int64) []byte {
+	bs := make([]byte, {{byteSize .}})
+	binary.LittleEndian.{{putFunc .}}(bs, {{timeUint .}}(val))
+	return bs
+}
+
+func (s *{{statsName .}}) NullCount() *int64 {
+	return nil
+}
+
+func (s *{{statsName .}}) DistinctCount() *int64 {
+	return nil
+}
+
+func (s *{{statsName .}}) Min() []byte {
+	return s.bytes(s.min)
+}
+
+func (s *{{statsName .}}) Max() []byte {
+	return s.bytes(s.max)
+}
+{{end}}`
+
+var timeOptionalStatsTpl = `{{define "timeOptionalStats"}}
+type {{statsName .}} struct {
+	unit    parquet.TimeUnit
+	utc     bool
+	min     int64
+	max     int64
+	nils    int64
+	nonNils int64
+	maxDef  uint8
+}
+
+func new{{camelCase (statsName .)}}(d uint8, unit parquet.TimeUnit, utc bool) *{{statsName .}} {
+	return &{{statsName .}}{
+		unit:   unit,
+		utc:    utc,
+		min:    math.MaxInt64,
+		max:    math.MinInt64,
+		maxDef: d,
+	}
+}
+
+func (s *{{statsName .}}) add(vals []time.Time, defs []uint8) {
+	var i int
+	for _, def := range defs {
+		if def < s.maxDef {
+			s.nils++
+		} else {
+			val := s.unit.Int64(vals[i], s.utc)
+			i++
+
+			s.nonNils++
+			if val < s.min {
+				s.min = val
+			}
+			if val > s.max {
+				s.max = val
+			}
+		}
+	}
+}
+
+func (s *{{statsName .}}) bytes(val int64) []byte {
+	bs := make([]byte, {{byteSize .}})
+	binary.LittleEndian.{{putFunc .}}(bs, {{timeUint .}}(val))
+	return bs
+}
+
+func (s *{{statsName .}}) NullCount() *int64 {
+	return &s.nils
+}
+
+func (s *{{statsName .}}) DistinctCount() *int64 {
+	return nil
+}
+
+func (s *{{statsName .}}) Min() []byte {
+	if s.nonNils == 0 {
+		return nil
+	}
+	return s.bytes(s.min)
+}
+
+func (s *{{statsName .}}) Max() []byte {
+	if s.nonNils == 0 {
+		return nil
+	}
+	return s.bytes(s.max)
+}
+{{end}}`
@@ -23,6 +23,7 @@ var (
 	ignore       = flag.Bool("ignore", true, "ignore unsupported fields in -type, otherwise log.Fatal is called when an unsupported type is encountered")
 	parq         = flag.String("parquet", "", "path to a parquet file (if you are generating code based on an existing parquet file or printing the file metadata or page headers)")
 	structOutPth = flag.String("struct-output", "generated_struct.go", "name of the file that is produced, defaults to parquet.go")
+	legacyLists  = flag.Bool("legacy-lists", false, "write slices as repeated fields instead of LIST groups that hold a repeated group named list, which is what readers generated by older versions of parquetgen expect")
 )
 
 func main() {
@@ -38,9 +39,9 @@ func main() {
 	} else if *pageheaders {
 		readPageHeaders()
 	} else if *parq == "" {
-		err = gen.FromStruct(*pth, *outPth, *typ, *pkg, *imp, *ignore)
+		err = gen.FromStruct(*pth, *outPth, *typ, *pkg, *imp, *ignore, *legacyLists)
 	} else {
-		err = gen.FromParquet(*parq, *structOutPth, *outPth, *typ, *pkg, *imp, *ignore)
+		err = gen.FromParquet(*parq, *structOutPth, *outPth, *typ, *pkg, *imp, *ignore, *legacyLists)
 	}
 
 	if err != nil {
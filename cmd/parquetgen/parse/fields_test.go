@@ -138,7 +138,7 @@ func TestFields(t *testing.T) {
 		{
 			name:   "unsupported fields",
 			typ:    "Unsupported",
-			errors: []error{fmt.Errorf("unsupported type &{time Time}")},
+			errors: []error{fmt.Errorf("unsupported type time.Duration")},
 			expected: fields.Field{
 				Children: []fields.Field{
 					{Type: "int32", Name: "ID", ColumnName: "ID", RepetitionType: fields.Required},
@@ -158,8 +158,8 @@ func TestFields(t *testing.T) {
 				},
 			},
 			errors: []error{
-				fmt.Errorf("unsupported type &{time Time}"),
-				fmt.Errorf("unsupported type &{time Time}"),
+				fmt.Errorf("unsupported type time.Duration"),
+				fmt.Errorf("unsupported type time.Duration"),
 			},
 		},
 		{
@@ -208,6 +208,78 @@ func TestFields(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "encoding tag",
+			typ:  "Encoded",
+			expected: fields.Field{
+				Children: []fields.Field{
+					{Type: "int32", Name: "ID", ColumnName: "id", RepetitionType: fields.Required, Encoding: "delta_binary_packed"},
+					{Type: "int64", Name: "Age", ColumnName: "age", RepetitionType: fields.Optional, Encoding: "delta_binary_packed"},
+					{Type: "string", Name: "Name", ColumnName: "name", RepetitionType: fields.Required},
+					{Type: "string", Name: "URL", ColumnName: "url", RepetitionType: fields.Required, Encoding: "delta_byte_array"},
+					{Type: "float32", Name: "Temp", ColumnName: "temp", RepetitionType: fields.Required, Encoding: "byte_stream_split"},
+					{Type: "string", Name: "Note", ColumnName: "note", RepetitionType: fields.Required, Encoding: "delta_length_byte_array", Codec: "zstd"},
+				},
+			},
+		},
+		{
+			name: "time tags",
+			typ:  "Times",
+			expected: fields.Field{
+				Children: []fields.Field{
+					{Type: "time.Time", Name: "Created", ColumnName: "created", RepetitionType: fields.Required},
+					{Type: "time.Time", Name: "Updated", ColumnName: "updated", RepetitionType: fields.Optional, TimeUnit: "nanos", UTC: true},
+					{Type: "time.Time", Name: "Born", ColumnName: "born", RepetitionType: fields.Required, Date: true},
+				},
+			},
+		},
+		{
+			name: "decimal tags",
+			typ:  "Decimals",
+			expected: fields.Field{
+				Children: []fields.Field{
+					{Type: "int64", Name: "Price", ColumnName: "price", RepetitionType: fields.Required, Decimal: "18:2"},
+					{Type: "int32", Name: "Discount", ColumnName: "discount", RepetitionType: fields.Optional, Decimal: "9:9"},
+					{Type: "big.Int", Name: "Total", ColumnName: "total", RepetitionType: fields.Required, Decimal: "38:4"},
+					{Type: "big.Int", Name: "Debt", ColumnName: "debt", RepetitionType: fields.Optional, Decimal: "20:0"},
+				},
+			},
+		},
+		{
+			name: "byte arrays",
+			typ:  "Bytes",
+			expected: fields.Field{
+				Children: []fields.Field{
+					{Type: "[]byte", Name: "Avatar", ColumnName: "avatar", RepetitionType: fields.Required},
+					{Type: "[]byte", Name: "Token", ColumnName: "token", RepetitionType: fields.Optional},
+					{Type: "[32]byte", Name: "Hash", ColumnName: "hash", RepetitionType: fields.Required},
+					{Type: "[16]byte", Name: "ID", ColumnName: "id", RepetitionType: fields.Optional, UUID: true},
+					{Type: "[4]byte", Name: "Checksums", ColumnName: "checksums", RepetitionType: fields.Repeated},
+					{Type: "[]byte", Name: "Blobs", ColumnName: "blobs", RepetitionType: fields.Repeated},
+				},
+			},
+		},
+		{
+			name: "maps",
+			typ:  "Maps",
+			expected: fields.Field{
+				Children: []fields.Field{
+					{Type: "int32", Name: "ID", ColumnName: "id", RepetitionType: fields.Required},
+					{Type: "map[string]string", Name: "Attrs", ColumnName: "attrs", RepetitionType: fields.Optional, Children: []fields.Field{
+						{ColumnName: "key_value", RepetitionType: fields.Repeated, Children: []fields.Field{
+							{Type: "string", Name: "Key", ColumnName: "key", RepetitionType: fields.Required},
+							{Type: "string", Name: "Value", ColumnName: "value", RepetitionType: fields.Required},
+						}},
+					}},
+					{Type: "map[int64]*float64", Name: "Scores", ColumnName: "Scores", RepetitionType: fields.Optional, Children: []fields.Field{
+						{ColumnName: "key_value", RepetitionType: fields.Repeated, Children: []fields.Field{
+							{Type: "int64", Name: "Key", ColumnName: "key", RepetitionType: fields.Required},
+							{Type: "float64", Name: "Value", ColumnName: "value", RepetitionType: fields.Optional},
+						}},
+					}},
+				},
+			},
+		},
 		{
 			name: "omit tag",
 			typ:  "IgnoreMe",
@@ -350,6 +422,70 @@ func TestFields(t *testing.T) {
 	}
 }
 
+func TestFieldsUnsupportedEncoding(t *testing.T) {
+	_, err := parse.Fields("BadEncoding", "./parse_test.go")
+	assert.EqualError(t, err, "encoding delta_binary_packed is not supported for field Name of type string")
+}
+
+func TestFieldsUnknownCodec(t *testing.T) {
+	_, err := parse.Fields("BadCodec", "./parse_test.go")
+	assert.EqualError(t, err, "unknown codec lzo for field Name")
+}
+
+func TestFieldsBadTimeOptions(t *testing.T) {
+	_, err := parse.Fields("BadUnit", "./parse_test.go")
+	assert.EqualError(t, err, "unknown unit seconds for field Created")
+
+	_, err = parse.Fields("BadDate", "./parse_test.go")
+	assert.EqualError(t, err, "field Born is a date, so it can't have a unit or be utc")
+
+	_, err = parse.Fields("BadTimeOption", "./parse_test.go")
+	assert.EqualError(t, err, "the unit, utc and date options are not supported for field Created of type int64")
+}
+
+func TestFieldsBadDecimals(t *testing.T) {
+	testCases := []struct {
+		typ string
+		err string
+	}{
+		{typ: "BadDecimal", err: "invalid decimal 2.5 for field Price, it must be precision:scale (like 18:2)"},
+		{typ: "BadPrecision", err: "invalid precision 10 for field Price of type int32"},
+		{typ: "BadScale", err: "invalid scale 5 for field Price, it must be between 0 and the precision"},
+		{typ: "BadDecimalType", err: "the decimal option is not supported for field Price of type float64"},
+		{typ: "MissingDecimal", err: "field Price of type big.Int needs the decimal option"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.typ, func(t *testing.T) {
+			_, err := parse.Fields(tc.typ, "./parse_test.go")
+			assert.EqualError(t, err, tc.err)
+		})
+	}
+}
+
+func TestFieldsBadMaps(t *testing.T) {
+	testCases := []struct {
+		typ string
+		err string
+	}{
+		{typ: "BadMapKey", err: "unsupported key type bool for map field Attrs"},
+		{typ: "BadMapValue", err: "unsupported value type for map field Attrs of type map[string][]string"},
+		{typ: "RepeatedMap", err: "map field Attrs can't be inside of a repeated field"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.typ, func(t *testing.T) {
+			_, err := parse.Fields(tc.typ, "./parse_test.go")
+			assert.EqualError(t, err, tc.err)
+		})
+	}
+}
+
+func TestFieldsBadUUID(t *testing.T) {
+	_, err := parse.Fields("BadUUID", "./parse_test.go")
+	assert.EqualError(t, err, "the uuid option is not supported for field ID of type [8]byte")
+}
+
 func pint32(i int32) *int32 {
 	return &i
 }
@@ -454,3 +590,47 @@ func TestDefIndex(t *testing.T) {
 		})
 	}
 }
+
+func TestListColumns(t *testing.T) {
+	root := &fields.Field{}
+	hobby := &fields.Field{Name: "Hobby", ColumnName: "hobby", RepetitionType: fields.Optional, Parent: root}
+	skills := &fields.Field{Name: "Skills", ColumnName: "skills", RepetitionType: fields.Repeated, List: true, Parent: hobby}
+
+	testCases := []struct {
+		name   string
+		field  fields.Field
+		names  []string
+		types  fields.RepetitionTypes
+		groups string
+	}{
+		{
+			name:   "list of primitives",
+			field:  fields.Field{Type: "int32", Name: "IDs", ColumnName: "ids", RepetitionType: fields.Repeated, List: true, Parent: root},
+			names:  []string{"ids", "list", "element"},
+			types:  fields.RepetitionTypes{fields.Required, fields.Repeated, fields.Required},
+			groups: "parquet.ListType",
+		},
+		{
+			name:   "legacy list of primitives",
+			field:  fields.Field{Type: "int32", Name: "IDs", ColumnName: "ids", RepetitionType: fields.Repeated, Parent: root},
+			names:  []string{"ids"},
+			types:  fields.RepetitionTypes{fields.Repeated},
+			groups: "",
+		},
+		{
+			name:   "list of structs",
+			field:  fields.Field{Type: "string", Name: "Name", ColumnName: "name", RepetitionType: fields.Optional, Parent: skills},
+			names:  []string{"hobby", "skills", "list", "element", "name"},
+			types:  fields.RepetitionTypes{fields.Optional, fields.Required, fields.Repeated, fields.Required, fields.Optional},
+			groups: "nil, parquet.ListType",
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("%02d %s", i, tc.name), func(t *testing.T) {
+			assert.Equal(t, tc.names, tc.field.ColumnNames())
+			assert.Equal(t, tc.types, tc.field.ColumnTypes())
+			assert.Equal(t, tc.groups, tc.field.Groups())
+		})
+	}
+}
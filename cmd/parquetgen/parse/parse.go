@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"go/parser"
 	"go/token"
+	gotypes "go/types"
 	"log"
 	"strings"
 
@@ -68,6 +69,32 @@ func Fields(typ, pth string) (*Result, error) {
 
 	errs := getChildren(&parent, fields)
 
+	for _, f := range parent.Fields() {
+		if err := f.CheckEncoding(); err != nil {
+			return nil, err
+		}
+
+		if err := f.CheckCodec(); err != nil {
+			return nil, err
+		}
+
+		if err := f.CheckTime(); err != nil {
+			return nil, err
+		}
+
+		if err := f.CheckDecimal(); err != nil {
+			return nil, err
+		}
+
+		if err := f.CheckUUID(); err != nil {
+			return nil, err
+		}
+
+		if err := f.CheckMap(); err != nil {
+			return nil, err
+		}
+	}
+
 	return &Result{
 		Parent: flds.Field{Type: typ, Children: parent.Children},
 		Errors: errs,
@@ -83,7 +110,7 @@ func getChildren(parent *flds.Field, fields map[string]flds.Field) []error {
 	}
 
 	for _, child := range p.Children {
-		if child.Primitive() {
+		if child.Primitive() || child.IsMap() {
 			children = append(children, child)
 			continue
 		}
@@ -174,23 +201,37 @@ func getType(typ string) string {
 }
 
 func getField(name string, x ast.Node, parent *flds.Field) (flds.Field, bool) {
-	var typ, tag string
+	var typ string
+	var tg tag
 	var optional, repeated bool
+	var mp *ast.MapType
 	ast.Inspect(x, func(n ast.Node) bool {
 		switch t := n.(type) {
+		case *ast.MapType:
+			mp = t
+			return false
 		case *ast.Field:
 			if t.Tag != nil {
-				tag = parseTag(t.Tag.Value)
+				tg = parseTag(t.Tag.Value)
 			}
 			typ = fmt.Sprintf("%s", t.Type)
 		case *ast.ArrayType:
 			at := n.(*ast.ArrayType)
+			if s, ok := byteArray(at); ok {
+				// a []byte or [N]byte is a single value, not a repeated byte
+				typ = s
+				return false
+			}
 			s := fmt.Sprintf("%v", at.Elt)
 			typ = s
 			repeated = true
 		case *ast.StarExpr:
 			optional = true
 			typ = fmt.Sprintf("%s", t.X)
+		case *ast.SelectorExpr:
+			if x, ok := t.X.(*ast.Ident); ok {
+				typ = fmt.Sprintf("%s.%s", x.Name, t.Sel.Name)
+			}
 		case ast.Expr:
 			s := fmt.Sprintf("%v", t)
 			_, ok := types[s]
@@ -201,8 +242,12 @@ func getField(name string, x ast.Node, parent *flds.Field) (flds.Field, bool) {
 		return true
 	})
 
-	if tag == "" {
-		tag = name
+	if tg.name == "" {
+		tg.name = name
+	}
+
+	if mp != nil {
+		return mapField(name, tg.name, mp), tg.name == "-"
 	}
 
 	rt := fields.Required
@@ -215,18 +260,100 @@ func getField(name string, x ast.Node, parent *flds.Field) (flds.Field, bool) {
 	return flds.Field{
 		Type:           typ,
 		Name:           name,
-		ColumnName:     tag,
+		ColumnName:     tg.name,
 		RepetitionType: rt,
-	}, tag == "-"
+		Encoding:       tg.encoding,
+		Codec:          tg.codec,
+		TimeUnit:       tg.unit,
+		UTC:            tg.utc,
+		Date:           tg.date,
+		Decimal:        tg.decimal,
+		UUID:           tg.uuid,
+	}, tg.name == "-"
+}
+
+// mapField returns the field of a map, which is an optional group
+// that holds a repeated key_value group with a key and a value.
+func mapField(name, column string, mp *ast.MapType) flds.Field {
+	key, _ := getField("Key", mp.Key, nil)
+	key.ColumnName = "key"
+	value, _ := getField("Value", mp.Value, nil)
+	value.ColumnName = "value"
+
+	return flds.Field{
+		Type:           gotypes.ExprString(mp),
+		Name:           name,
+		ColumnName:     column,
+		RepetitionType: fields.Optional,
+		Children: []flds.Field{
+			{ColumnName: "key_value", RepetitionType: fields.Repeated, Children: []flds.Field{key, value}},
+		},
+	}
+}
+
+// byteArray returns the type of a []byte or [N]byte.
+func byteArray(at *ast.ArrayType) (string, bool) {
+	elt, ok := at.Elt.(*ast.Ident)
+	if !ok || elt.Name != "byte" {
+		return "", false
+	}
+
+	if at.Len == nil {
+		return "[]byte", true
+	}
+
+	l, ok := at.Len.(*ast.BasicLit)
+	if !ok || l.Kind != token.INT {
+		return "", false
+	}
+	return fmt.Sprintf("[%s]byte", l.Value), true
 }
 
-func parseTag(t string) string {
+// tag holds the parts of a parquet struct tag, for
+// example `parquet:"id,encoding=delta_binary_packed,codec=zstd"`
+// or `parquet:"ts,unit=micros,utc"` or `parquet:"id,uuid"`
+type tag struct {
+	name     string
+	encoding string
+	codec    string
+	unit     string
+	utc      bool
+	date     bool
+	decimal  string
+	uuid     bool
+}
+
+// parseTag ignores any options it doesn't know about,
+// like encoding/json does.
+func parseTag(t string) tag {
 	i := strings.Index(t, `parquet:"`)
 	if i == -1 {
-		return ""
+		return tag{}
 	}
 	t = t[i+9:]
-	return t[:strings.Index(t, `"`)]
+	t = t[:strings.Index(t, `"`)]
+
+	parts := strings.Split(t, ",")
+	tg := tag{name: parts[0]}
+	for _, opt := range parts[1:] {
+		switch {
+		case strings.HasPrefix(opt, "encoding="):
+			tg.encoding = strings.TrimPrefix(opt, "encoding=")
+		case strings.HasPrefix(opt, "codec="):
+			tg.codec = strings.TrimPrefix(opt, "codec=")
+		case strings.HasPrefix(opt, "unit="):
+			tg.unit = strings.TrimPrefix(opt, "unit=")
+		case opt == "utc":
+			tg.utc = true
+		case opt == "date":
+			tg.date = true
+		case strings.HasPrefix(opt, "decimal="):
+			tg.decimal = strings.TrimPrefix(opt, "decimal=")
+		case opt == "uuid":
+			tg.uuid = true
+		}
+	}
+	return tg
 }
 
 type visitorFunc func(n ast.Node) ast.Visitor
@@ -260,12 +387,14 @@ func (f *finder) findTypes(n ast.Node) ast.Visitor {
 }
 
 var types = map[string]bool{
-	"int32":   true,
-	"uint32":  true,
-	"int64":   true,
-	"uint64":  true,
-	"float32": true,
-	"float64": true,
-	"bool":    true,
-	"string":  true,
+	"int32":     true,
+	"uint32":    true,
+	"int64":     true,
+	"uint64":    true,
+	"float32":   true,
+	"float64":   true,
+	"bool":      true,
+	"string":    true,
+	"time.Time": true,
+	"big.Int":   true,
 }
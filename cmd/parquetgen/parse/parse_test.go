@@ -1,6 +1,9 @@
 package parse_test
 
-import "time"
+import (
+	"math/big"
+	"time"
+)
 
 type Being struct {
 	ID  int32
@@ -82,16 +85,16 @@ type Unsupported struct {
 	Being
 	// This field will be ignored because it's not one of the
 	// supported types.
-	Time time.Time
+	Time time.Duration
 }
 
 type SupportedAndUnsupported struct {
 	Happiness int64
 	x         int
-	T1        time.Time
+	T1        time.Duration
 	Being
 	y           int
-	T2          time.Time
+	T2          time.Duration
 	Anniversary *uint64
 }
 
@@ -177,3 +180,100 @@ type A struct {
 	B
 	Name string
 }
+
+type Encoded struct {
+	ID   int32   `parquet:"id,encoding=delta_binary_packed"`
+	Age  *int64  `parquet:"age,encoding=delta_binary_packed"`
+	Name string  `parquet:"name"`
+	URL  string  `parquet:"url,encoding=delta_byte_array"`
+	Temp float32 `parquet:"temp,encoding=byte_stream_split"`
+	Note string  `parquet:"note,codec=zstd,encoding=delta_length_byte_array"`
+}
+
+type BadEncoding struct {
+	Name string `parquet:"name,encoding=delta_binary_packed"`
+}
+
+type BadCodec struct {
+	Name string `parquet:"name,codec=lzo"`
+}
+
+type Times struct {
+	Created time.Time  `parquet:"created"`
+	Updated *time.Time `parquet:"updated,unit=nanos,utc"`
+	Born    time.Time  `parquet:"born,date"`
+}
+
+type BadUnit struct {
+	Created time.Time `parquet:"created,unit=seconds"`
+}
+
+type BadDate struct {
+	Born time.Time `parquet:"born,date,utc"`
+}
+
+type BadTimeOption struct {
+	Created int64 `parquet:"created,unit=millis"`
+}
+
+type Decimals struct {
+	Price    int64    `parquet:"price,decimal=18:2"`
+	Discount *int32   `parquet:"discount,decimal=9:9"`
+	Total    big.Int  `parquet:"total,decimal=38:4"`
+	Debt     *big.Int `parquet:"debt,decimal=20:0"`
+}
+
+type BadDecimal struct {
+	Price int64 `parquet:"price,decimal=2.5"`
+}
+
+type BadPrecision struct {
+	Price int32 `parquet:"price,decimal=10:2"`
+}
+
+type BadScale struct {
+	Price int64 `parquet:"price,decimal=4:5"`
+}
+
+type BadDecimalType struct {
+	Price float64 `parquet:"price,decimal=18:2"`
+}
+
+type MissingDecimal struct {
+	Price big.Int `parquet:"price"`
+}
+
+type Bytes struct {
+	Avatar    []byte    `parquet:"avatar"`
+	Token     *[]byte   `parquet:"token"`
+	Hash      [32]byte  `parquet:"hash"`
+	ID        *[16]byte `parquet:"id,uuid"`
+	Checksums [][4]byte `parquet:"checksums"`
+	Blobs     [][]byte  `parquet:"blobs"`
+}
+
+type BadUUID struct {
+	ID [8]byte `parquet:"id,uuid"`
+}
+
+type Maps struct {
+	ID     int32             `parquet:"id"`
+	Attrs  map[string]string `parquet:"attrs"`
+	Scores map[int64]*float64
+}
+
+type BadMapKey struct {
+	Attrs map[bool]string `parquet:"attrs"`
+}
+
+type BadMapValue struct {
+	Attrs map[string][]string `parquet:"attrs"`
+}
+
+type RepeatedMap struct {
+	Items []MapItem `parquet:"items"`
+}
+
+type MapItem struct {
+	Attrs map[string]string `parquet:"attrs"`
+}
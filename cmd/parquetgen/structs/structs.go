@@ -31,6 +31,28 @@ func getStruct(parent *sch.SchemaElement, children []*sch.SchemaElement) (int, s
 	var fields string
 	for i < int(*parent.NumChildren) {
 		ch := children[i+j]
+		if m, ok := mapType(ch, children[i+j+1:]); ok {
+			fields = fmt.Sprintf("%s\n%s %s `parquet:\"%s\"`", fields, strings.Title(ch.Name), m, ch.Name)
+			j += 3
+			i++
+			continue
+		}
+		if elem, n, ok := listElem(ch, children[i+j+1:]); ok {
+			// the list's field and struct are named after the list
+			// rather than its element
+			el := *elem
+			el.Name = ch.Name
+			el.RepetitionType = sch.FieldRepetitionTypePtr(sch.FieldRepetitionType_REPEATED)
+			fields = fmt.Sprintf("%s\n%s", fields, field(&el))
+			j += n
+			if el.GetNumChildren() > 0 {
+				n, s := getStruct(&el, children[i+j+1:])
+				j += n
+				str += fmt.Sprintf("\n\n%s", s)
+			}
+			i++
+			continue
+		}
 		fields = fmt.Sprintf("%s\n%s", fields, field(ch))
 		if ch.NumChildren != nil && int(*ch.NumChildren) > 0 {
 			n, s := getStruct(ch, children[i+j+1:])
@@ -46,14 +68,112 @@ func getStruct(parent *sch.SchemaElement, children []*sch.SchemaElement) (int, s
 func field(elem *sch.SchemaElement) string {
 	n := strings.Title(elem.Name)
 	t := n
+	var opts string
 	if elem.Type != nil {
 		t = getType(elem.Type.String())
+		if d, p, s, ok := decimal(elem); ok {
+			t = d
+			opts = fmt.Sprintf(",decimal=%d:%d", p, s)
+		} else if *elem.Type == sch.Type_FIXED_LEN_BYTE_ARRAY {
+			t = fmt.Sprintf("[%d]byte", elem.GetTypeLength())
+			if elem.LogicalType != nil && elem.LogicalType.UUID != nil {
+				opts = ",uuid"
+			}
+		}
 	}
 	var ptr string
-	if elem.RepetitionType != nil && *elem.RepetitionType == sch.FieldRepetitionType_OPTIONAL {
+	switch elem.GetRepetitionType() {
+	case sch.FieldRepetitionType_OPTIONAL:
 		ptr = "*"
+	case sch.FieldRepetitionType_REPEATED:
+		ptr = "[]"
 	}
-	return fmt.Sprintf("%s %s%s `parquet:\"%s\"`", n, ptr, t, elem.Name)
+	return fmt.Sprintf("%s %s%s `parquet:\"%s%s\"`", n, ptr, t, elem.Name, opts)
+}
+
+// listElem returns the element of a LIST group and the number of
+// schema elements between the group and its element.  The element is
+// the group's repeated child in the legacy two-level layouts (where
+// the repeated child isn't a group with a single child, or is named
+// array or <list>_tuple) and the repeated child's only child
+// otherwise.
+func listElem(elem *sch.SchemaElement, children []*sch.SchemaElement) (*sch.SchemaElement, int, bool) {
+	isList := elem.LogicalType != nil && elem.LogicalType.LIST != nil
+	if elem.GetConvertedType() == sch.ConvertedType_LIST {
+		isList = true
+	}
+	if !isList || elem.GetNumChildren() != 1 || len(children) < 1 || children[0].GetRepetitionType() != sch.FieldRepetitionType_REPEATED {
+		return nil, 0, false
+	}
+
+	r := children[0]
+	if r.GetNumChildren() != 1 || r.Name == "array" || r.Name == elem.Name+"_tuple" {
+		return r, 1, true
+	}
+	if len(children) < 2 {
+		return nil, 0, false
+	}
+	return children[1], 2, true
+}
+
+// mapType returns the type of a MAP group, which holds a repeated
+// group with a key and a value column.
+func mapType(elem *sch.SchemaElement, children []*sch.SchemaElement) (string, bool) {
+	isMap := elem.LogicalType != nil && elem.LogicalType.MAP != nil
+	if elem.ConvertedType != nil && (*elem.ConvertedType == sch.ConvertedType_MAP || *elem.ConvertedType == sch.ConvertedType_MAP_KEY_VALUE) {
+		isMap = true
+	}
+	if !isMap || elem.GetNumChildren() != 1 || len(children) < 3 || children[0].GetNumChildren() != 2 {
+		return "", false
+	}
+
+	key, val := children[1], children[2]
+	k, ok := mapElemType(key)
+	if !ok {
+		return "", false
+	}
+	v, ok := mapElemType(val)
+	if !ok {
+		return "", false
+	}
+
+	var ptr string
+	if val.GetRepetitionType() == sch.FieldRepetitionType_OPTIONAL {
+		ptr = "*"
+	}
+	return fmt.Sprintf("map[%s]%s%s", k, ptr, v), true
+}
+
+// mapElemType returns the type of a map's key or value (decimals
+// aren't supported since their struct tag options can't be set).
+func mapElemType(elem *sch.SchemaElement) (string, bool) {
+	if elem.Type == nil {
+		return "", false
+	}
+	if _, _, _, ok := decimal(elem); ok {
+		return "", false
+	}
+	if *elem.Type == sch.Type_FIXED_LEN_BYTE_ARRAY {
+		return fmt.Sprintf("[%d]byte", elem.GetTypeLength()), true
+	}
+	t := getType(elem.Type.String())
+	return t, t != ""
+}
+
+// decimal returns the type, precision and scale of a DECIMAL column.
+func decimal(elem *sch.SchemaElement) (string, int32, int32, bool) {
+	var p, s int32
+	switch {
+	case elem.LogicalType != nil && elem.LogicalType.DECIMAL != nil:
+		p, s = elem.LogicalType.DECIMAL.Precision, elem.LogicalType.DECIMAL.Scale
+	case elem.ConvertedType != nil && *elem.ConvertedType == sch.ConvertedType_DECIMAL:
+		p, s = elem.GetPrecision(), elem.GetScale()
+	default:
+		return "", 0, 0, false
+	}
+
+	t, ok := decimalTypes[elem.Type.String()]
+	return t, p, s, ok
 }
 
 func getType(t string) string {
@@ -69,6 +189,13 @@ var parquetTypes = map[string]string{
 	"BYTE_ARRAY": "string",
 }
 
+var decimalTypes = map[string]string{
+	"INT32":                "int32",
+	"INT64":                "int64",
+	"BYTE_ARRAY":           "big.Int",
+	"FIXED_LEN_BYTE_ARRAY": "big.Int",
+}
+
 var primitiveTypes = map[string]bool{
 	"bool":    true,
 	"int32":   true,
@@ -3,8 +3,10 @@ package structs_test
 import (
 	"fmt"
 	"go/format"
+	"os"
 	"testing"
 
+	"github.com/parsyl/parquet"
 	"github.com/parsyl/parquet/cmd/parquetgen/structs"
 	sch "github.com/parsyl/parquet/schema"
 	"github.com/stretchr/testify/assert"
@@ -61,6 +63,61 @@ func TestStruct(t *testing.T) {
 			},
 			expected: "type Root struct {\n	Hobby Hobby  `parquet:\"hobby\"`\n	Id    *int32 `parquet:\"id\"`\n}\n\ntype Hobby struct {\n	Name       *Name `parquet:\"name\"`\n	Difficulty int32 `parquet:\"difficulty\"`\n}\n\ntype Name struct {\n	First *string `parquet:\"first\"`\n	Last  string  `parquet:\"last\"`\n}",
 		},
+		{
+			name: "decimals",
+			schema: []*sch.SchemaElement{
+				{Name: "root", NumChildren: pint32(3)},
+				{Name: "price", Type: pt(sch.Type_INT64), RepetitionType: prt(sch.FieldRepetitionType_REQUIRED), ConvertedType: pct(sch.ConvertedType_DECIMAL), Precision: pint32(18), Scale: pint32(2)},
+				{Name: "rate", Type: pt(sch.Type_INT32), RepetitionType: prt(sch.FieldRepetitionType_OPTIONAL), LogicalType: &sch.LogicalType{DECIMAL: &sch.DecimalType{Precision: 9, Scale: 4}}},
+				{Name: "total", Type: pt(sch.Type_FIXED_LEN_BYTE_ARRAY), RepetitionType: prt(sch.FieldRepetitionType_OPTIONAL), TypeLength: pint32(16), ConvertedType: pct(sch.ConvertedType_DECIMAL), LogicalType: &sch.LogicalType{DECIMAL: &sch.DecimalType{Precision: 38, Scale: 0}}},
+			},
+			expected: "type Root struct {\n	Price int64    `parquet:\"price,decimal=18:2\"`\n	Rate  *int32   `parquet:\"rate,decimal=9:4\"`\n	Total *big.Int `parquet:\"total,decimal=38:0\"`\n}",
+		},
+		{
+			name: "fixed length byte arrays",
+			schema: []*sch.SchemaElement{
+				{Name: "root", NumChildren: pint32(2)},
+				{Name: "hash", Type: pt(sch.Type_FIXED_LEN_BYTE_ARRAY), RepetitionType: prt(sch.FieldRepetitionType_REQUIRED), TypeLength: pint32(32)},
+				{Name: "id", Type: pt(sch.Type_FIXED_LEN_BYTE_ARRAY), RepetitionType: prt(sch.FieldRepetitionType_OPTIONAL), TypeLength: pint32(16), LogicalType: &sch.LogicalType{UUID: sch.NewUUIDType()}},
+			},
+			expected: "type Root struct {\n	Hash [32]byte  `parquet:\"hash\"`\n	Id   *[16]byte `parquet:\"id,uuid\"`\n}",
+		},
+		{
+			name: "maps",
+			schema: []*sch.SchemaElement{
+				{Name: "root", NumChildren: pint32(3)},
+				{Name: "attrs", RepetitionType: prt(sch.FieldRepetitionType_OPTIONAL), NumChildren: pint32(1), ConvertedType: pct(sch.ConvertedType_MAP), LogicalType: &sch.LogicalType{MAP: sch.NewMapType()}},
+				{Name: "key_value", RepetitionType: prt(sch.FieldRepetitionType_REPEATED), NumChildren: pint32(2)},
+				{Name: "key", Type: pt(sch.Type_BYTE_ARRAY), RepetitionType: prt(sch.FieldRepetitionType_REQUIRED)},
+				{Name: "value", Type: pt(sch.Type_BYTE_ARRAY), RepetitionType: prt(sch.FieldRepetitionType_REQUIRED)},
+				{Name: "scores", RepetitionType: prt(sch.FieldRepetitionType_OPTIONAL), NumChildren: pint32(1), ConvertedType: pct(sch.ConvertedType_MAP)},
+				{Name: "map", RepetitionType: prt(sch.FieldRepetitionType_REPEATED), NumChildren: pint32(2), ConvertedType: pct(sch.ConvertedType_MAP_KEY_VALUE)},
+				{Name: "key", Type: pt(sch.Type_INT32), RepetitionType: prt(sch.FieldRepetitionType_REQUIRED)},
+				{Name: "value", Type: pt(sch.Type_DOUBLE), RepetitionType: prt(sch.FieldRepetitionType_OPTIONAL)},
+				{Name: "id", Type: pt(sch.Type_INT64), RepetitionType: prt(sch.FieldRepetitionType_REQUIRED)},
+			},
+			expected: "type Root struct {\n	Attrs  map[string]string  `parquet:\"attrs\"`\n	Scores map[int32]*float64 `parquet:\"scores\"`\n	Id     int64              `parquet:\"id\"`\n}",
+		},
+		{
+			name: "lists",
+			schema: []*sch.SchemaElement{
+				{Name: "root", NumChildren: pint32(5)},
+				{Name: "friends", RepetitionType: prt(sch.FieldRepetitionType_REQUIRED), NumChildren: pint32(1), ConvertedType: pct(sch.ConvertedType_LIST), LogicalType: &sch.LogicalType{LIST: sch.NewListType()}},
+				{Name: "list", RepetitionType: prt(sch.FieldRepetitionType_REPEATED), NumChildren: pint32(1)},
+				{Name: "element", RepetitionType: prt(sch.FieldRepetitionType_REQUIRED), NumChildren: pint32(2)},
+				{Name: "id", Type: pt(sch.Type_INT32), RepetitionType: prt(sch.FieldRepetitionType_REQUIRED)},
+				{Name: "age", Type: pt(sch.Type_INT32), RepetitionType: prt(sch.FieldRepetitionType_OPTIONAL)},
+				{Name: "scores", RepetitionType: prt(sch.FieldRepetitionType_OPTIONAL), NumChildren: pint32(1), ConvertedType: pct(sch.ConvertedType_LIST)},
+				{Name: "list", RepetitionType: prt(sch.FieldRepetitionType_REPEATED), NumChildren: pint32(1)},
+				{Name: "element", Type: pt(sch.Type_INT64), RepetitionType: prt(sch.FieldRepetitionType_OPTIONAL), ConvertedType: pct(sch.ConvertedType_DECIMAL), Precision: pint32(18), Scale: pint32(2)},
+				{Name: "tags", RepetitionType: prt(sch.FieldRepetitionType_OPTIONAL), NumChildren: pint32(1), ConvertedType: pct(sch.ConvertedType_LIST)},
+				{Name: "array", Type: pt(sch.Type_BYTE_ARRAY), RepetitionType: prt(sch.FieldRepetitionType_REPEATED)},
+				{Name: "ids", Type: pt(sch.Type_INT64), RepetitionType: prt(sch.FieldRepetitionType_REPEATED)},
+				{Name: "links", RepetitionType: prt(sch.FieldRepetitionType_REPEATED), NumChildren: pint32(1)},
+				{Name: "url", Type: pt(sch.Type_BYTE_ARRAY), RepetitionType: prt(sch.FieldRepetitionType_REQUIRED)},
+			},
+			expected: "type Root struct {\n	Friends []Friends `parquet:\"friends\"`\n	Scores  []int64   `parquet:\"scores,decimal=18:2\"`\n	Tags    []string  `parquet:\"tags\"`\n	Ids     []int64   `parquet:\"ids\"`\n	Links   []Links   `parquet:\"links\"`\n}\n\ntype Friends struct {\n	Id  int32  `parquet:\"id\"`\n	Age *int32 `parquet:\"age\"`\n}\n\ntype Links struct {\n	Url string `parquet:\"url\"`\n}",
+		},
 	}
 
 	for i, tc := range testCases {
@@ -75,6 +132,25 @@ func TestStruct(t *testing.T) {
 	}
 }
 
+// TestForeignDecimals generates a struct for decimals that were written by
+// arrow, whose wealth is a BYTE_ARRAY and whose balance is 16 bytes long.
+func TestForeignDecimals(t *testing.T) {
+	f, err := os.Open("../../../testdata/decimals.parquet")
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer f.Close()
+
+	footer, err := parquet.ReadMetaData(f)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	gocode, err := format.Source([]byte(structs.Struct("Root", footer.Schema)))
+	assert.NoError(t, err)
+	assert.Equal(t, "type Root struct {\n	Id       int32    `parquet:\"id\"`\n	Wealth   *big.Int `parquet:\"wealth,decimal=38:2\"`\n	Price    int64    `parquet:\"price,decimal=18:2\"`\n	Discount *int32   `parquet:\"discount,decimal=9:3\"`\n	Balance  big.Int  `parquet:\"balance,decimal=30:4\"`\n}", string(gocode))
+}
+
 func pint32(i int32) *int32 {
 	return &i
 }
@@ -86,3 +162,7 @@ func prt(rt sch.FieldRepetitionType) *sch.FieldRepetitionType {
 func pt(t sch.Type) *sch.Type {
 	return &t
 }
+
+func pct(t sch.ConvertedType) *sch.ConvertedType {
+	return &t
+}
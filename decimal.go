@@ -0,0 +1,134 @@
+package parquet
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math/big"
+	"strings"
+
+	sch "github.com/parsyl/parquet/schema"
+)
+
+// DecimalType returns the FieldFunc of a DECIMAL column with the given
+// precision and scale whose values are stored as typ (INT32, INT64 or
+// FIXED_LEN_BYTE_ARRAY).  A FIXED_LEN_BYTE_ARRAY column's values are
+// DecimalSize(precision) bytes long.
+func DecimalType(typ sch.Type, precision, scale int32) FieldFunc {
+	return func(se *sch.SchemaElement) {
+		ct := sch.ConvertedType_DECIMAL
+		se.Type = &typ
+		se.ConvertedType = &ct
+		se.Precision = &precision
+		se.Scale = &scale
+		se.LogicalType = &sch.LogicalType{DECIMAL: &sch.DecimalType{Scale: scale, Precision: precision}}
+		if typ == sch.Type_FIXED_LEN_BYTE_ARRAY {
+			l := int32(DecimalSize(precision))
+			se.TypeLength = &l
+		}
+	}
+}
+
+// DecimalSize returns the number of bytes it takes to store an unscaled
+// value with the given precision as a two's complement integer.
+func DecimalSize(precision int32) int {
+	max := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(precision)), nil)
+	return max.BitLen()/8 + 1
+}
+
+// PutDecimal writes v into b as a big-endian two's complement integer
+// that is len(b) bytes long.  It returns an error if v doesn't fit.
+func PutDecimal(b []byte, v *big.Int) error {
+	if v.Sign() >= 0 {
+		if v.BitLen() >= 8*len(b) {
+			return fmt.Errorf("decimal value %s doesn't fit in %d bytes", v, len(b))
+		}
+		v.FillBytes(b)
+		return nil
+	}
+
+	// the two's complement of v is 2^(8*len(b)) + v
+	x := new(big.Int).Lsh(big.NewInt(1), uint(8*len(b)))
+	x.Add(x, v)
+	if x.Sign() <= 0 || x.BitLen() != 8*len(b) {
+		return fmt.Errorf("decimal value %s doesn't fit in %d bytes", v, len(b))
+	}
+	x.FillBytes(b)
+	return nil
+}
+
+// Decimal is the reverse of PutDecimal.  It reads the big-endian two's
+// complement integer in b.
+func Decimal(b []byte) *big.Int {
+	v := new(big.Int).SetBytes(b)
+	if v.Sign() == 0 {
+		// the same as big.Int's zero value
+		return new(big.Int)
+	}
+
+	if b[0]&0x80 != 0 {
+		v.Sub(v, new(big.Int).Lsh(big.NewInt(1), uint(8*len(b))))
+	}
+	return v
+}
+
+// Decimal checks that the page's column in the file is a DECIMAL with the
+// precision and scale of the field that reads it, whose values are stored
+// as typ.  A field whose values are FIXED_LEN_BYTE_ARRAYs can also read a
+// BYTE_ARRAY column.  It returns the length of the column's values, which
+// is 0 for a BYTE_ARRAY column (see ReadDecimal).
+func (pg Page) Decimal(typ sch.Type, precision, scale int32) (int, error) {
+	if pg.element == nil {
+		return pg.TypeLength, nil
+	}
+
+	name := strings.Join(pg.chunk.MetaData.PathInSchema, ".")
+	se := *pg.element
+	if pg.Type != typ && !(typ == sch.Type_FIXED_LEN_BYTE_ARRAY && pg.Type == sch.Type_BYTE_ARRAY) {
+		return 0, fmt.Errorf("column %s is %s, but its field is %s", name, pg.Type, typ)
+	}
+
+	if !decimal(se) {
+		return 0, fmt.Errorf("column %s isn't a DECIMAL", name)
+	}
+
+	p, s := se.GetPrecision(), se.GetScale()
+	if se.LogicalType != nil && se.LogicalType.DECIMAL != nil {
+		p, s = se.LogicalType.DECIMAL.Precision, se.LogicalType.DECIMAL.Scale
+	}
+	if p != precision || s != scale {
+		return 0, fmt.Errorf("column %s is a DECIMAL(%d,%d), but its field is a DECIMAL(%d,%d)", name, p, s, precision, scale)
+	}
+
+	if pg.Type == sch.Type_BYTE_ARRAY {
+		return 0, nil
+	}
+	return pg.TypeLength, nil
+}
+
+// ReadDecimal reads a value of a FIXED_LEN_BYTE_ARRAY DECIMAL column whose
+// values are size bytes long, or of a BYTE_ARRAY one (whose values start
+// with their length) if size is 0.
+func ReadDecimal(r io.Reader, size int) (*big.Int, error) {
+	if size == 0 {
+		var n uint32
+		if err := binary.Read(r, binary.LittleEndian, &n); err != nil {
+			return nil, err
+		}
+		size = int(n)
+	}
+
+	bs := make([]byte, size)
+	if _, err := io.ReadFull(r, bs); err != nil {
+		return nil, err
+	}
+	return Decimal(bs), nil
+}
+
+// decimal reports whether se is a DECIMAL column.
+func decimal(se sch.SchemaElement) bool {
+	if se.LogicalType != nil && se.LogicalType.DECIMAL != nil {
+		return true
+	}
+	return se.ConvertedType != nil && *se.ConvertedType == sch.ConvertedType_DECIMAL
+}
@@ -0,0 +1,173 @@
+package parquet
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"math/bits"
+
+	"github.com/parsyl/parquet/internal/rle"
+	sch "github.com/parsyl/parquet/schema"
+)
+
+// MaxDictionarySize is the size in bytes that a column chunk's
+// dictionary page can grow to before the rest of the column chunk
+// falls back to PLAIN encoding.
+const MaxDictionarySize = 1 << 20
+
+// dictionary keeps track of the distinct values of a column chunk
+// so that its data pages can be written as indices into a dictionary
+// page.  Since the dictionary page has to be written before the data
+// pages, the data pages are buffered until the column chunk is flushed.
+type dictionary struct {
+	typ    sch.Type
+	length int
+	codec  sch.CompressionCodec
+	level  int
+	lookup map[string]uint32
+	vals   []byte
+	n      int
+	full   bool
+	pages  bytes.Buffer
+
+	// size is the size of the dictionary page (including its header)
+	// once it's been written.
+	size int64
+}
+
+func newDictionary(typ sch.Type, length int, codec sch.CompressionCodec, level int) *dictionary {
+	return &dictionary{
+		typ:    typ,
+		length: length,
+		codec:  codec,
+		level:  level,
+		lookup: map[string]uint32{},
+	}
+}
+
+// Write makes dictionary an io.Writer so the pages of the column chunk
+// can be buffered until the dictionary page has been written.
+func (d *dictionary) Write(p []byte) (int, error) {
+	return d.pages.Write(p)
+}
+
+// encode adds the PLAIN encoded vals to the dictionary and returns them as
+// RLE/bit-packed indices, prefixed by their bit width.  It returns false
+// if the values should be written as PLAIN instead, which happens once
+// the dictionary would grow larger than MaxDictionarySize.
+func (d *dictionary) encode(vals []byte) ([]byte, bool, error) {
+	if d.full || len(vals) == 0 {
+		return nil, false, nil
+	}
+
+	n, l := d.n, len(d.vals)
+	var indices []uint32
+	err := plainValues(d.typ, d.length, vals, func(v []byte) {
+		i, ok := d.lookup[string(v)]
+		if !ok {
+			i = uint32(d.n)
+			d.lookup[string(v)] = i
+			d.vals = append(d.vals, v...)
+			d.n++
+		}
+		indices = append(indices, i)
+	})
+	if err != nil {
+		return nil, false, err
+	}
+
+	if len(d.vals) > MaxDictionarySize {
+		for k, i := range d.lookup {
+			if int(i) >= n {
+				delete(d.lookup, k)
+			}
+		}
+		d.vals = d.vals[:l]
+		d.n = n
+		d.full = true
+		return nil, false, nil
+	}
+
+	width := bits.Len32(uint32(d.n - 1))
+	return append([]byte{byte(width)}, rle.Encode(indices, width)...), true, nil
+}
+
+// plainValues splits PLAIN encoded data into its individual values.
+// length is the length of a FIXED_LEN_BYTE_ARRAY column's values.
+func plainValues(typ sch.Type, length int, data []byte, f func([]byte)) error {
+	var width int
+	switch typ {
+	case sch.Type_INT32, sch.Type_FLOAT:
+		width = 4
+	case sch.Type_INT64, sch.Type_DOUBLE:
+		width = 8
+	case sch.Type_FIXED_LEN_BYTE_ARRAY:
+		if length <= 0 {
+			return fmt.Errorf("invalid FIXED_LEN_BYTE_ARRAY length %d", length)
+		}
+		width = length
+	case sch.Type_BYTE_ARRAY:
+		for len(data) > 0 {
+			if len(data) < 4 {
+				return fmt.Errorf("invalid PLAIN byte array data")
+			}
+			l := 4 + int(binary.LittleEndian.Uint32(data))
+			if len(data) < l {
+				return fmt.Errorf("invalid PLAIN byte array data")
+			}
+			f(data[:l])
+			data = data[l:]
+		}
+		return nil
+	default:
+		return fmt.Errorf("unsupported dictionary type: %s", typ)
+	}
+
+	for ; len(data) >= width; data = data[width:] {
+		f(data[:width])
+	}
+	return nil
+}
+
+// readDictionary splits the PLAIN encoded values of a dictionary page.
+func readDictionary(typ sch.Type, length int, data []byte, n int) ([][]byte, error) {
+	out := make([][]byte, 0, n)
+	err := plainValues(typ, length, data, func(v []byte) {
+		out = append(out, v)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(out) < n {
+		return nil, fmt.Errorf("dictionary page has %d values, expected %d", len(out), n)
+	}
+	return out[:n], nil
+}
+
+// expand turns the bit width prefixed, RLE/bit-packed indices of a
+// dictionary encoded data page back into the n PLAIN encoded values
+// they refer to.
+func expand(dict [][]byte, data []byte, n int) ([]byte, error) {
+	if n == 0 {
+		return nil, nil
+	}
+
+	if len(data) == 0 {
+		return nil, fmt.Errorf("missing dictionary indices")
+	}
+
+	indices, err := rle.Decode(data[1:], int(data[0]), n)
+	if err != nil {
+		return nil, err
+	}
+
+	var out []byte
+	for _, i := range indices {
+		if int(i) >= len(dict) {
+			return nil, fmt.Errorf("dictionary index %d out of range (dictionary size %d)", i, len(dict))
+		}
+		out = append(out, dict[i]...)
+	}
+	return out, nil
+}
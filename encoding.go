@@ -0,0 +1,183 @@
+package parquet
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"github.com/parsyl/parquet/internal/delta"
+	sch "github.com/parsyl/parquet/schema"
+)
+
+// encodeValues re-encodes the PLAIN encoded vals of a column of type typ
+// with enc.
+func encodeValues(typ sch.Type, enc sch.Encoding, vals []byte) ([]byte, error) {
+	switch enc {
+	case sch.Encoding_DELTA_BINARY_PACKED:
+		switch typ {
+		case sch.Type_INT32:
+			v := make([]int32, len(vals)/4)
+			for i := range v {
+				v[i] = int32(binary.LittleEndian.Uint32(vals[4*i:]))
+			}
+			return delta.EncodeInt32(v), nil
+		case sch.Type_INT64:
+			v := make([]int64, len(vals)/8)
+			for i := range v {
+				v[i] = int64(binary.LittleEndian.Uint64(vals[8*i:]))
+			}
+			return delta.EncodeInt64(v), nil
+		}
+	case sch.Encoding_DELTA_LENGTH_BYTE_ARRAY:
+		if typ == sch.Type_BYTE_ARRAY {
+			v, err := byteArrays(vals)
+			if err != nil {
+				return nil, err
+			}
+			return delta.EncodeLengthByteArray(v), nil
+		}
+	case sch.Encoding_DELTA_BYTE_ARRAY:
+		if typ == sch.Type_BYTE_ARRAY {
+			v, err := byteArrays(vals)
+			if err != nil {
+				return nil, err
+			}
+			return delta.EncodeByteArray(v), nil
+		}
+	case sch.Encoding_BYTE_STREAM_SPLIT:
+		switch typ {
+		case sch.Type_FLOAT:
+			return splitStreams(vals, 4), nil
+		case sch.Type_DOUBLE:
+			return splitStreams(vals, 8), nil
+		}
+	}
+	return nil, fmt.Errorf("encoding %s is not supported for %s columns", enc, typ)
+}
+
+// decodeValues turns the n values in data, which are encoded with enc,
+// back into PLAIN encoded values.
+func decodeValues(typ sch.Type, enc sch.Encoding, data []byte, n int) ([]byte, error) {
+	switch enc {
+	case sch.Encoding_DELTA_BINARY_PACKED:
+		switch typ {
+		case sch.Type_INT32:
+			vals, _, err := delta.DecodeInt32(data)
+			if err != nil {
+				return nil, err
+			}
+
+			if len(vals) < n {
+				return nil, fmt.Errorf("page has %d values, expected %d", len(vals), n)
+			}
+
+			out := make([]byte, 4*n)
+			for i, v := range vals[:n] {
+				binary.LittleEndian.PutUint32(out[4*i:], uint32(v))
+			}
+			return out, nil
+		case sch.Type_INT64:
+			vals, _, err := delta.DecodeInt64(data)
+			if err != nil {
+				return nil, err
+			}
+
+			if len(vals) < n {
+				return nil, fmt.Errorf("page has %d values, expected %d", len(vals), n)
+			}
+
+			out := make([]byte, 8*n)
+			for i, v := range vals[:n] {
+				binary.LittleEndian.PutUint64(out[8*i:], uint64(v))
+			}
+			return out, nil
+		}
+		return nil, fmt.Errorf("encoding %s is not supported for %s columns", enc, typ)
+	case sch.Encoding_DELTA_LENGTH_BYTE_ARRAY, sch.Encoding_DELTA_BYTE_ARRAY:
+		if typ != sch.Type_BYTE_ARRAY {
+			return nil, fmt.Errorf("encoding %s is not supported for %s columns", enc, typ)
+		}
+
+		decode := delta.DecodeLengthByteArray
+		if enc == sch.Encoding_DELTA_BYTE_ARRAY {
+			decode = delta.DecodeByteArray
+		}
+
+		vals, _, err := decode(data)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(vals) < n {
+			return nil, fmt.Errorf("page has %d values, expected %d", len(vals), n)
+		}
+		return plainByteArrays(vals[:n]), nil
+	case sch.Encoding_BYTE_STREAM_SPLIT:
+		var width int
+		switch typ {
+		case sch.Type_INT32, sch.Type_FLOAT:
+			width = 4
+		case sch.Type_INT64, sch.Type_DOUBLE:
+			width = 8
+		default:
+			return nil, fmt.Errorf("encoding %s is not supported for %s columns", enc, typ)
+		}
+
+		if len(data) < width*n {
+			return nil, fmt.Errorf("page has %d values, expected %d", len(data)/width, n)
+		}
+		return joinStreams(data[:width*n], width), nil
+	default:
+		return nil, fmt.Errorf("unsupported encoding: %s", enc)
+	}
+}
+
+// byteArrays splits PLAIN encoded byte arrays into their values.
+func byteArrays(data []byte) ([][]byte, error) {
+	var out [][]byte
+	err := plainValues(sch.Type_BYTE_ARRAY, 0, data, func(v []byte) {
+		out = append(out, v[4:])
+	})
+	return out, err
+}
+
+// plainByteArrays PLAIN encodes vals.
+func plainByteArrays(vals [][]byte) []byte {
+	var l int
+	for _, v := range vals {
+		l += 4 + len(v)
+	}
+
+	out := make([]byte, 0, l)
+	for _, v := range vals {
+		out = binary.LittleEndian.AppendUint32(out, uint32(len(v)))
+		out = append(out, v...)
+	}
+	return out
+}
+
+// splitStreams BYTE_STREAM_SPLIT encodes the PLAIN encoded vals, each of
+// which is width bytes long: the first byte of every value is written,
+// then the second byte of every value and so on.
+func splitStreams(vals []byte, width int) []byte {
+	n := len(vals) / width
+	out := make([]byte, n*width)
+	for i := 0; i < n; i++ {
+		for j := 0; j < width; j++ {
+			out[j*n+i] = vals[i*width+j]
+		}
+	}
+	return out
+}
+
+// joinStreams turns BYTE_STREAM_SPLIT encoded data back into PLAIN
+// encoded values.
+func joinStreams(data []byte, width int) []byte {
+	n := len(data) / width
+	out := make([]byte, n*width)
+	for i := 0; i < n; i++ {
+		for j := 0; j < width; j++ {
+			out[i*width+j] = data[j*n+i]
+		}
+	}
+	return out
+}
@@ -5,6 +5,7 @@ import (
 	"compress/gzip"
 	"math/bits"
 	"strings"
+	"sync"
 
 	"github.com/valyala/bytebufferpool"
 
@@ -12,9 +13,12 @@ import (
 
 	"io"
 
+	"github.com/andybalholm/brotli"
 	"github.com/golang/snappy"
+	"github.com/klauspost/compress/zstd"
 	"github.com/parsyl/parquet/internal/rle"
 	sch "github.com/parsyl/parquet/schema"
+	"github.com/pierrec/lz4/v4"
 )
 
 // RepetitionType is an enum of the possible
@@ -30,6 +34,14 @@ const (
 
 var (
 	buffpool = bytebufferpool.Pool{}
+
+	// The zstd encoders and decoder are safe for concurrent use as long
+	// as only EncodeAll and DecodeAll are called.
+	zstdEncoders   = map[zstd.EncoderLevel]*zstd.Encoder{}
+	zstdLock       sync.Mutex
+	zstdDecoder, _ = zstd.NewReader(nil, zstd.WithDecoderConcurrency(0))
+
+	lz4Compressors = sync.Pool{New: func() interface{} { return &lz4.Compressor{} }}
 )
 
 type RepetitionTypes []RepetitionType
@@ -60,6 +72,9 @@ func (r RepetitionTypes) MaxRep() uint8 {
 type RequiredField struct {
 	pth         []string
 	compression sch.CompressionCodec
+	level       int
+	dictionary  bool
+	encoding    sch.Encoding
 }
 
 // NewRequiredField creates a required field.
@@ -86,23 +101,100 @@ func RequiredFieldGzip(r *RequiredField) {
 	r.compression = sch.CompressionCodec_GZIP
 }
 
+// RequiredFieldZstd sets the compression for a column to zstd
+// It is an optional arg to NewRequiredField
+func RequiredFieldZstd(r *RequiredField) {
+	r.compression = sch.CompressionCodec_ZSTD
+}
+
+// RequiredFieldLz4 sets the compression for a column to lz4 (LZ4_RAW)
+// It is an optional arg to NewRequiredField
+func RequiredFieldLz4(r *RequiredField) {
+	r.compression = sch.CompressionCodec_LZ4_RAW
+}
+
+// RequiredFieldBrotli sets the compression for a column to brotli
+// It is an optional arg to NewRequiredField
+func RequiredFieldBrotli(r *RequiredField) {
+	r.compression = sch.CompressionCodec_BROTLI
+}
+
 // RequiredFieldUncompressed sets the compression to none
 // It is an optional arg to NewRequiredField
 func RequiredFieldUncompressed(r *RequiredField) {
 	r.compression = sch.CompressionCodec_UNCOMPRESSED
 }
 
+// RequiredFieldCompressionLevel sets the level of the column's gzip,
+// zstd or brotli compression.  0 means the codec's default level.
+// It is an optional arg to NewRequiredField
+func RequiredFieldCompressionLevel(level int) func(*RequiredField) {
+	return func(r *RequiredField) {
+		r.level = level
+	}
+}
+
+// RequiredFieldDictionary turns on dictionary encoding for a column.
+// It is an optional arg to NewRequiredField
+func RequiredFieldDictionary(r *RequiredField) {
+	r.dictionary = true
+}
+
+// RequiredFieldDeltaBinaryPacked sets the encoding of an int32 or int64
+// column to DELTA_BINARY_PACKED, which takes precedence over dictionary
+// encoding.
+// It is an optional arg to NewRequiredField
+func RequiredFieldDeltaBinaryPacked(r *RequiredField) {
+	r.encoding = sch.Encoding_DELTA_BINARY_PACKED
+}
+
+// RequiredFieldDeltaLengthByteArray sets the encoding of a string column
+// to DELTA_LENGTH_BYTE_ARRAY, which takes precedence over dictionary
+// encoding.
+// It is an optional arg to NewRequiredField
+func RequiredFieldDeltaLengthByteArray(r *RequiredField) {
+	r.encoding = sch.Encoding_DELTA_LENGTH_BYTE_ARRAY
+}
+
+// RequiredFieldDeltaByteArray sets the encoding of a string column to
+// DELTA_BYTE_ARRAY, which stores each value as the length of the prefix
+// it shares with the previous value plus the rest of the value.  It takes
+// precedence over dictionary encoding.
+// It is an optional arg to NewRequiredField
+func RequiredFieldDeltaByteArray(r *RequiredField) {
+	r.encoding = sch.Encoding_DELTA_BYTE_ARRAY
+}
+
+// RequiredFieldByteStreamSplit sets the encoding of a float32 or float64
+// column to BYTE_STREAM_SPLIT, which groups the bytes of the values by
+// their position so the data compresses better.  It takes precedence
+// over dictionary encoding.
+// It is an optional arg to NewRequiredField
+func RequiredFieldByteStreamSplit(r *RequiredField) {
+	r.encoding = sch.Encoding_BYTE_STREAM_SPLIT
+}
+
 // DoWrite writes the actual raw data.
 func (f *RequiredField) DoWrite(w io.Writer, meta *Metadata, vals []byte, count int, stats Stats) error {
 	buff := buffpool.Get()
 	defer buffpool.Put(buff)
 
-	l, cl, vals, err := compress(f.compression, buff, vals)
+	w, enc, vals, err := encode(w, meta, f.pth, f.compression, f.level, f.dictionary, f.encoding, vals)
+	if err != nil {
+		return err
+	}
+
+	l, cl, vals, err := compress(f.compression, f.level, buff, vals)
 	if err != nil {
 		return err
 	}
 
-	if err := meta.WritePageHeader(w, f.pth, l, cl, count, count, 0, 0, f.compression, stats); err != nil {
+	if meta.dataPageV2 {
+		err = meta.WritePageHeaderV2(w, f.pth, l, cl, count, 0, count, 0, 0, f.compression, enc, stats)
+	} else {
+		err = meta.WritePageHeader(w, f.pth, l, cl, count, count, count, 0, 0, f.compression, enc, stats)
+	}
+	if err != nil {
 		return err
 	}
 
@@ -115,21 +207,34 @@ func (f *RequiredField) DoRead(r io.ReadSeeker, pg Page) (io.Reader, []int, erro
 	var nRead int
 	var out []byte
 	var sizes []int
-	for nRead < pg.N {
-		ph, err := PageHeader(r)
+	var dict [][]byte
+
+	if pg.rows != nil {
+		pages, err := readRows(r, pg, MaxLevel{})
 		if err != nil {
 			return nil, nil, err
 		}
 
-		sizes = append(sizes, int(ph.DataPageHeader.NumValues))
+		for _, p := range pages {
+			sizes = append(sizes, p.n)
+			out = append(out, p.vals...)
+		}
+		return bytes.NewBuffer(out), sizes, nil
+	}
+
+	if _, err := r.Seek(pg.Offset, io.SeekStart); err != nil {
+		return nil, nil, err
+	}
 
-		data, err := pageData(r, ph, pg)
+	for nRead < pg.N {
+		p, err := readPage(r, pg, MaxLevel{}, &dict)
 		if err != nil {
 			return nil, nil, err
 		}
 
-		out = append(out, data...)
-		nRead += int(ph.DataPageHeader.NumValues)
+		sizes = append(sizes, p.n)
+		out = append(out, p.vals...)
+		nRead += p.n
 	}
 	return bytes.NewBuffer(out), sizes, nil
 }
@@ -159,9 +264,15 @@ type OptionalField struct {
 	pth            []string
 	MaxLevels      MaxLevel
 	compression    sch.CompressionCodec
+	level          int
+	dictionary     bool
+	encoding       sch.Encoding
 	RepetitionType FieldFunc
 	Types          []int
-	repeated       bool
+	// Groups are the FieldFuncs of the groups in the field's path
+	// (see Field.Groups).
+	Groups   []FieldFunc
+	repeated bool
 }
 
 func getRepetitionTypes(in []int) RepetitionTypes {
@@ -193,6 +304,15 @@ func NewOptionalField(pth []string, types []int, opts ...func(*OptionalField)) O
 	return f
 }
 
+// OptionalFieldGroups sets the FieldFuncs of the groups in the
+// field's path, like MapType for a map's key and value columns.
+// It is an optional arg to NewOptionalField
+func OptionalFieldGroups(groups ...FieldFunc) func(*OptionalField) {
+	return func(o *OptionalField) {
+		o.Groups = groups
+	}
+}
+
 // OptionalFieldSnappy sets the compression for a column to snappy
 // It is an optional arg to NewOptionalField
 func OptionalFieldSnappy(r *OptionalField) {
@@ -205,18 +325,95 @@ func OptionalFieldGzip(r *OptionalField) {
 	r.compression = sch.CompressionCodec_GZIP
 }
 
+// OptionalFieldZstd sets the compression for a column to zstd
+// It is an optional arg to NewOptionalField
+func OptionalFieldZstd(r *OptionalField) {
+	r.compression = sch.CompressionCodec_ZSTD
+}
+
+// OptionalFieldLz4 sets the compression for a column to lz4 (LZ4_RAW)
+// It is an optional arg to NewOptionalField
+func OptionalFieldLz4(r *OptionalField) {
+	r.compression = sch.CompressionCodec_LZ4_RAW
+}
+
+// OptionalFieldBrotli sets the compression for a column to brotli
+// It is an optional arg to NewOptionalField
+func OptionalFieldBrotli(r *OptionalField) {
+	r.compression = sch.CompressionCodec_BROTLI
+}
+
 // OptionalFieldUncompressed sets the compression to none
 // It is an optional arg to NewOptionalField
 func OptionalFieldUncompressed(o *OptionalField) {
 	o.compression = sch.CompressionCodec_UNCOMPRESSED
 }
 
+// OptionalFieldCompressionLevel sets the level of the column's gzip,
+// zstd or brotli compression.  0 means the codec's default level.
+// It is an optional arg to NewOptionalField
+func OptionalFieldCompressionLevel(level int) func(*OptionalField) {
+	return func(o *OptionalField) {
+		o.level = level
+	}
+}
+
+// OptionalFieldDictionary turns on dictionary encoding for a column.
+// It is an optional arg to NewOptionalField
+func OptionalFieldDictionary(o *OptionalField) {
+	o.dictionary = true
+}
+
+// OptionalFieldDeltaBinaryPacked sets the encoding of an int32 or int64
+// column to DELTA_BINARY_PACKED, which takes precedence over dictionary
+// encoding.
+// It is an optional arg to NewOptionalField
+func OptionalFieldDeltaBinaryPacked(o *OptionalField) {
+	o.encoding = sch.Encoding_DELTA_BINARY_PACKED
+}
+
+// OptionalFieldDeltaLengthByteArray sets the encoding of a string column
+// to DELTA_LENGTH_BYTE_ARRAY, which takes precedence over dictionary
+// encoding.
+// It is an optional arg to NewOptionalField
+func OptionalFieldDeltaLengthByteArray(o *OptionalField) {
+	o.encoding = sch.Encoding_DELTA_LENGTH_BYTE_ARRAY
+}
+
+// OptionalFieldDeltaByteArray sets the encoding of a string column to
+// DELTA_BYTE_ARRAY, which stores each value as the length of the prefix
+// it shares with the previous value plus the rest of the value.  It takes
+// precedence over dictionary encoding.
+// It is an optional arg to NewOptionalField
+func OptionalFieldDeltaByteArray(o *OptionalField) {
+	o.encoding = sch.Encoding_DELTA_BYTE_ARRAY
+}
+
+// OptionalFieldByteStreamSplit sets the encoding of a float32 or float64
+// column to BYTE_STREAM_SPLIT, which groups the bytes of the values by
+// their position so the data compresses better.  It takes precedence
+// over dictionary encoding.
+// It is an optional arg to NewOptionalField
+func OptionalFieldByteStreamSplit(o *OptionalField) {
+	o.encoding = sch.Encoding_BYTE_STREAM_SPLIT
+}
+
 // Values reads the definition levels and uses them
 // to return the values from the page data.
 func (f *OptionalField) Values() int {
 	return f.valsFromDefs(f.Defs, uint8(f.MaxLevels.Def))
 }
 
+// LevelsSize returns an estimate of the number of bytes that the
+// definition and repetition levels take up once they are encoded.
+func (f *OptionalField) LevelsSize() int {
+	n := len(f.Defs) * bits.Len(uint(f.MaxLevels.Def))
+	if f.repeated {
+		n += len(f.Reps) * bits.Len(uint(f.MaxLevels.Rep))
+	}
+	return (n + 7) / 8
+}
+
 func (f *OptionalField) valsFromDefs(defs []uint8, max uint8) int {
 	var out int
 	for _, d := range defs {
@@ -230,6 +427,15 @@ func (f *OptionalField) valsFromDefs(defs []uint8, max uint8) int {
 // DoWrite is called by all optional field types to write the definition levels
 // and raw data to the io.Writer
 func (f *OptionalField) DoWrite(w io.Writer, meta *Metadata, vals []byte, count int, stats Stats) error {
+	w, enc, vals, err := encode(w, meta, f.pth, f.compression, f.level, f.dictionary, f.encoding, vals)
+	if err != nil {
+		return err
+	}
+
+	if meta.dataPageV2 {
+		return f.doWriteV2(w, meta, vals, count, enc, stats)
+	}
+
 	buf := buffpool.Get()
 	defer buffpool.Put(buf)
 	wc := &writeCounter{w: buf}
@@ -244,7 +450,7 @@ func (f *OptionalField) DoWrite(w io.Writer, meta *Metadata, vals []byte, count
 		repLen = wc.n
 	}
 
-	err := writeLevels(wc, f.Defs, int32(bits.Len(uint(f.MaxLevels.Def))))
+	err = writeLevels(wc, f.Defs, int32(bits.Len(uint(f.MaxLevels.Def))))
 	if err != nil {
 		return err
 	}
@@ -258,59 +464,111 @@ func (f *OptionalField) DoWrite(w io.Writer, meta *Metadata, vals []byte, count
 	compressed := buffpool.Get()
 	defer buffpool.Put(compressed)
 
-	l, cl, vals, err := compress(f.compression, compressed, buf.Bytes())
+	l, cl, vals, err := compress(f.compression, f.level, compressed, buf.Bytes())
 	if err != nil {
 		return err
 	}
 
-	if err := meta.WritePageHeader(w, f.pth, l, cl, len(f.Defs), count, defLen, repLen, f.compression, stats); err != nil {
+	if err := meta.WritePageHeader(w, f.pth, l, cl, len(f.Defs), count, f.rows(), defLen, repLen, f.compression, enc, stats); err != nil {
 		return err
 	}
 	_, err = w.Write(vals)
 	return err
 }
 
+// rows returns the number of rows that the levels of the page belong to.
+func (f *OptionalField) rows() int {
+	if !f.repeated {
+		return len(f.Defs)
+	}
+
+	var rows int
+	for _, r := range f.Reps {
+		if r == 0 {
+			rows++
+		}
+	}
+	return rows
+}
+
+// doWriteV2 writes a DATA_PAGE_V2 page, which keeps the levels
+// out of the compressed values.
+func (f *OptionalField) doWriteV2(w io.Writer, meta *Metadata, vals []byte, count int, enc sch.Encoding, stats Stats) error {
+	var reps []byte
+	if f.repeated {
+		reps = levelsV2(f.Reps, int32(bits.Len(uint(f.MaxLevels.Rep))))
+	}
+
+	defs := levelsV2(f.Defs, int32(bits.Len(uint(f.MaxLevels.Def))))
+	nulls := len(f.Defs) - f.valsFromDefs(f.Defs, f.MaxLevels.Def)
+
+	compressed := buffpool.Get()
+	defer buffpool.Put(compressed)
+
+	l, cl, vals, err := compress(f.compression, f.level, compressed, vals)
+	if err != nil {
+		return err
+	}
+
+	levels := len(reps) + len(defs)
+	if err := meta.WritePageHeaderV2(w, f.pth, l+levels, cl+levels, count, nulls, f.rows(), int64(len(defs)), int64(len(reps)), f.compression, enc, stats); err != nil {
+		return err
+	}
+
+	for _, b := range [][]byte{reps, defs, vals} {
+		if _, err := w.Write(b); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // DoRead is called by all optional fields.  It reads the definition levels and uses
 // them to interpret the raw data.
 func (f *OptionalField) DoRead(r io.ReadSeeker, pg Page) (io.Reader, []int, error) {
 	var nRead int
 	var out []byte
 	var sizes []int
+	var dict [][]byte
 	var rc *readCounter
 
-	for nRead < pg.Size {
-		rc = &readCounter{r: r}
-		ph, err := PageHeader(rc)
-		if err != nil {
-			return nil, nil, err
-		}
-
-		data, err := pageData(rc, ph, pg)
+	max := pg.maxLevels(f.MaxLevels)
+	if pg.rows != nil {
+		pages, err := readRows(r, pg, max)
 		if err != nil {
 			return nil, nil, err
 		}
 
-		var l int
-
-		if f.repeated {
-			reps, l2, err := readLevels(bytes.NewBuffer(data[l:]), int32(bits.Len(uint(f.MaxLevels.Rep))))
-			if err != nil {
+		for _, p := range pages {
+			if err := pg.fieldDefs(p.defs); err != nil {
 				return nil, nil, err
 			}
-			f.Reps = append(f.Reps, reps[:int(ph.DataPageHeader.NumValues)]...)
-			l += l2
+			f.Reps = append(f.Reps, p.reps...)
+			f.Defs = append(f.Defs, p.defs...)
+			sizes = append(sizes, f.valsFromDefs(p.defs, f.MaxLevels.Def))
+			out = append(out, p.vals...)
 		}
+		return bytes.NewBuffer(out), sizes, nil
+	}
 
-		defs, l2, err := readLevels(bytes.NewBuffer(data[l:]), int32(bits.Len(uint(f.MaxLevels.Def))))
+	if _, err := r.Seek(pg.Offset, io.SeekStart); err != nil {
+		return nil, nil, err
+	}
+
+	for nRead < pg.Size {
+		rc = &readCounter{r: r}
+		p, err := readPage(rc, pg, max, &dict)
 		if err != nil {
 			return nil, nil, err
 		}
-		f.Defs = append(f.Defs, defs[:int(ph.DataPageHeader.NumValues)]...)
-		l += l2
+		if err := pg.fieldDefs(p.defs); err != nil {
+			return nil, nil, err
+		}
 
-		n := f.valsFromDefs(defs, uint8(f.MaxLevels.Def))
-		sizes = append(sizes, n)
-		out = append(out, data[l:]...)
+		f.Reps = append(f.Reps, p.reps...)
+		f.Defs = append(f.Defs, p.defs...)
+		sizes = append(sizes, f.valsFromDefs(p.defs, f.MaxLevels.Def))
+		out = append(out, p.vals...)
 		nRead += int(rc.n)
 	}
 	return bytes.NewBuffer(out), sizes, nil
@@ -355,53 +613,247 @@ func (r *readCounter) Read(p []byte) (int, error) {
 	return n, err
 }
 
-func pageData(r io.Reader, ph *sch.PageHeader, pg Page) ([]byte, error) {
-	var data []byte
-	switch pg.Codec {
-	case sch.CompressionCodec_SNAPPY:
-		compressed := make([]byte, ph.CompressedPageSize)
-		if _, err := r.Read(compressed); err != nil {
+// page holds the levels and PLAIN encoded values of a data page.
+type page struct {
+	// n is the number of values in the page, including nulls.
+	n    int
+	reps []uint8
+	defs []uint8
+	vals []byte
+}
+
+// readPage reads page headers until it finds a data page and returns its
+// levels and values.  Dictionary pages on the way are decoded into dict
+// and any other kind of page is skipped.
+func readPage(r io.Reader, pg Page, max MaxLevel, dict *[][]byte) (*page, error) {
+	for {
+		ph, err := PageHeader(r)
+		if err != nil {
 			return nil, err
 		}
 
-		var err error
-		data, err = snappy.Decode(nil, compressed)
+		switch ph.Type {
+		case sch.PageType_DATA_PAGE:
+			data, err := pageData(r, ph, pg)
+			if err != nil {
+				return nil, err
+			}
+			return dataPage(ph.DataPageHeader, data, pg, max, *dict)
+		case sch.PageType_DATA_PAGE_V2:
+			return dataPageV2(r, ph, pg, max, *dict)
+		case sch.PageType_DICTIONARY_PAGE:
+			data, err := pageData(r, ph, pg)
+			if err != nil {
+				return nil, err
+			}
+
+			*dict, err = readDictionary(pg.Type, pg.TypeLength, data, int(ph.DictionaryPageHeader.NumValues))
+			if err != nil {
+				return nil, err
+			}
+		default:
+			if _, err := io.CopyN(io.Discard, r, int64(ph.CompressedPageSize)); err != nil {
+				return nil, err
+			}
+		}
+	}
+}
+
+// dataPage reads the levels and values of a DATA_PAGE page, where
+// both are compressed together and the levels are prefixed by their
+// length.
+func dataPage(ph *sch.DataPageHeader, data []byte, pg Page, max MaxLevel, dict [][]byte) (*page, error) {
+	p := &page{n: int(ph.NumValues)}
+
+	var l int
+	if max.Rep > 0 {
+		reps, l2, err := readLevels(bytes.NewBuffer(data[l:]), int32(bits.Len(uint(max.Rep))))
 		if err != nil {
 			return nil, err
 		}
-	case sch.CompressionCodec_GZIP:
-		var buf bytes.Buffer
-		_, err := io.CopyN(&buf, r, int64(ph.CompressedPageSize))
+		if len(reps) < p.n {
+			return nil, fmt.Errorf("page has %d repetition levels, expected %d", len(reps), p.n)
+		}
+		p.reps = reps[:p.n]
+		l += l2
+	}
+
+	if max.Def > 0 {
+		defs, l2, err := readLevels(bytes.NewBuffer(data[l:]), int32(bits.Len(uint(max.Def))))
 		if err != nil {
 			return nil, err
 		}
+		if len(defs) < p.n {
+			return nil, fmt.Errorf("page has %d definition levels, expected %d", len(defs), p.n)
+		}
+		p.defs = defs[:p.n]
+		l += l2
+	}
+
+	var err error
+	p.vals, err = decode(pg.Type, ph.Encoding, dict, data[l:], p.values(max.Def))
+	return p, err
+}
+
+// dataPageV2 reads a DATA_PAGE_V2 page.  Its levels are never compressed
+// and, unlike DATA_PAGE, the header holds their lengths.
+func dataPageV2(r io.Reader, ph *sch.PageHeader, pg Page, max MaxLevel, dict [][]byte) (*page, error) {
+	h := ph.DataPageHeaderV2
+	data := make([]byte, ph.CompressedPageSize)
+	if _, err := io.ReadFull(r, data); err != nil {
+		return nil, err
+	}
+
+	repLen, defLen := int(h.RepetitionLevelsByteLength), int(h.DefinitionLevelsByteLength)
+	if repLen < 0 || defLen < 0 || repLen+defLen > len(data) {
+		return nil, fmt.Errorf("invalid level lengths %d and %d for a page of %d bytes", repLen, defLen, len(data))
+	}
+
+	p := &page{n: int(h.NumValues)}
+
+	var err error
+	if max.Rep > 0 {
+		if p.reps, err = readLevelsV2(data[:repLen], max.Rep, p.n); err != nil {
+			return nil, err
+		}
+	}
+
+	if max.Def > 0 {
+		if p.defs, err = readLevelsV2(data[repLen:repLen+defLen], max.Def, p.n); err != nil {
+			return nil, err
+		}
+	}
 
-		zr, err := gzip.NewReader(&buf)
+	vals := data[repLen+defLen:]
+	if h.IsCompressed {
+		vals, err = decompress(pg.Codec, vals, int(ph.UncompressedPageSize)-repLen-defLen)
 		if err != nil {
 			return nil, err
 		}
+	}
+
+	p.vals, err = decode(pg.Type, h.Encoding, dict, vals, p.values(max.Def))
+	return p, err
+}
+
+// values returns the number of non-null values in the page.
+func (p *page) values(max uint8) int {
+	if max == 0 {
+		return p.n
+	}
+
+	var out int
+	for _, d := range p.defs {
+		if d == max {
+			out++
+		}
+	}
+	return out
+}
+
+func pageData(r io.Reader, ph *sch.PageHeader, pg Page) ([]byte, error) {
+	compressed := make([]byte, ph.CompressedPageSize)
+	if _, err := io.ReadFull(r, compressed); err != nil {
+		return nil, err
+	}
+	return decompress(pg.Codec, compressed, int(ph.UncompressedPageSize))
+}
 
-		data, err = io.ReadAll(zr)
+// decompress returns the uncompressed data, which is size bytes long.
+func decompress(codec sch.CompressionCodec, data []byte, size int) ([]byte, error) {
+	switch codec {
+	case sch.CompressionCodec_SNAPPY:
+		return snappy.Decode(nil, data)
+	case sch.CompressionCodec_GZIP:
+		zr, err := gzip.NewReader(bytes.NewReader(data))
 		if err != nil {
 			return nil, err
 		}
 
+		out := bytes.NewBuffer(make([]byte, 0, size))
+		if _, err := io.Copy(out, zr); err != nil {
+			return nil, err
+		}
+
 		if err := zr.Close(); err != nil {
 			return nil, err
 		}
-	case sch.CompressionCodec_UNCOMPRESSED:
-		data = make([]byte, ph.UncompressedPageSize)
-		if _, err := r.Read(data); err != nil {
+		return out.Bytes(), nil
+	case sch.CompressionCodec_ZSTD:
+		return zstdDecoder.DecodeAll(data, make([]byte, 0, size))
+	case sch.CompressionCodec_LZ4_RAW:
+		out := make([]byte, size)
+		if size == 0 {
+			return out, nil
+		}
+
+		n, err := lz4.UncompressBlock(data, out)
+		if err != nil {
+			return nil, err
+		}
+		return out[:n], nil
+	case sch.CompressionCodec_BROTLI:
+		out := bytes.NewBuffer(make([]byte, 0, size))
+		if _, err := io.Copy(out, brotli.NewReader(bytes.NewReader(data))); err != nil {
 			return nil, err
 		}
+		return out.Bytes(), nil
+	case sch.CompressionCodec_UNCOMPRESSED:
+		return data, nil
 	default:
-		return nil, fmt.Errorf("unsupported column chunk codec: %s", pg.Codec)
+		return nil, fmt.Errorf("unsupported column chunk codec: %s", codec)
+	}
+}
+
+// encode encodes vals with enc, or dictionary encodes them if dict is
+// true and the column's dictionary hasn't grown too large.  The pages of
+// a dictionary encoded column chunk are buffered until
+// Metadata.FlushColumn is called, so the returned io.Writer is where the
+// page should be written.
+func encode(w io.Writer, meta *Metadata, pth []string, codec sch.CompressionCodec, level int, dict bool, enc sch.Encoding, vals []byte) (io.Writer, sch.Encoding, []byte, error) {
+	if enc != sch.Encoding_PLAIN {
+		typ, err := columnType(strings.Join(pth, "."), meta.schema)
+		if err != nil {
+			return w, enc, vals, err
+		}
+
+		vals, err = encodeValues(typ, enc, vals)
+		return w, enc, vals, err
 	}
 
-	return data, nil
+	if !dict {
+		return w, sch.Encoding_PLAIN, vals, nil
+	}
+
+	d, err := meta.dictionary(pth, codec, level)
+	if err != nil || d == nil {
+		return w, sch.Encoding_PLAIN, vals, err
+	}
+
+	out, ok, err := d.encode(vals)
+	if err != nil || !ok {
+		return d, sch.Encoding_PLAIN, vals, err
+	}
+
+	return d, sch.Encoding_RLE_DICTIONARY, out, nil
+}
+
+// decode turns the n values of a data page back into PLAIN encoded
+// values.  dict holds the values of the column chunk's dictionary page.
+func decode(typ sch.Type, enc sch.Encoding, dict [][]byte, data []byte, n int) ([]byte, error) {
+	switch enc {
+	case sch.Encoding_PLAIN:
+		return data, nil
+	case sch.Encoding_PLAIN_DICTIONARY, sch.Encoding_RLE_DICTIONARY:
+		return expand(dict, data, n)
+	default:
+		return decodeValues(typ, enc, data, n)
+	}
 }
 
-func compress(codec sch.CompressionCodec, buf *bytebufferpool.ByteBuffer, vals []byte) (int, int, []byte, error) {
+// compress compresses vals with codec.  A level of 0 means the codec's
+// default level, which is the fastest one for gzip.
+func compress(codec sch.CompressionCodec, level int, buf *bytebufferpool.ByteBuffer, vals []byte) (int, int, []byte, error) {
 	var err error
 	l := len(vals)
 	switch codec {
@@ -414,7 +866,11 @@ func compress(codec sch.CompressionCodec, buf *bytebufferpool.ByteBuffer, vals [
 
 		vals = snappy.Encode(buf.B, vals)
 	case sch.CompressionCodec_GZIP:
-		zw, err := gzip.NewWriterLevel(buf, gzip.BestSpeed)
+		if level == 0 {
+			level = gzip.BestSpeed
+		}
+
+		zw, err := gzip.NewWriterLevel(buf, level)
 		if err != nil {
 			return l, 0, vals, err
 		}
@@ -429,11 +885,65 @@ func compress(codec sch.CompressionCodec, buf *bytebufferpool.ByteBuffer, vals [
 			return l, 0, vals, err
 		}
 
+		vals = buf.Bytes()
+	case sch.CompressionCodec_ZSTD:
+		vals = zstdEncoder(level).EncodeAll(vals, buf.B[:0])
+	case sch.CompressionCodec_LZ4_RAW:
+		if v := lz4.CompressBlockBound(len(vals)); v > cap(buf.B) {
+			buf.B = make([]byte, v)
+		} else {
+			buf.B = buf.B[:v]
+		}
+
+		c := lz4Compressors.Get().(*lz4.Compressor)
+		n, err := c.CompressBlock(vals, buf.B)
+		lz4Compressors.Put(c)
+		if err != nil {
+			return l, 0, vals, err
+		}
+
+		// an empty block is still a single token
+		if n == 0 {
+			buf.B[0] = 0
+			n = 1
+		}
+		vals = buf.B[:n]
+	case sch.CompressionCodec_BROTLI:
+		if level == 0 {
+			level = brotli.DefaultCompression
+		}
+
+		bw := brotli.NewWriterLevel(buf, level)
+		if _, err := bw.Write(vals); err != nil {
+			return l, 0, vals, err
+		}
+
+		if err := bw.Close(); err != nil {
+			return l, 0, vals, err
+		}
+
 		vals = buf.Bytes()
 	}
 	return l, len(vals), vals, err
 }
 
+// zstdEncoder returns the encoder for a zstd compression level (1 to 22).
+func zstdEncoder(level int) *zstd.Encoder {
+	l := zstd.SpeedDefault
+	if level != 0 {
+		l = zstd.EncoderLevelFromZstd(level)
+	}
+
+	zstdLock.Lock()
+	defer zstdLock.Unlock()
+	enc, ok := zstdEncoders[l]
+	if !ok {
+		enc, _ = zstd.NewWriter(nil, zstd.WithEncoderLevel(l), zstd.WithEncoderConcurrency(1))
+		zstdEncoders[l] = enc
+	}
+	return enc
+}
+
 // writeLevels writes vals to w as RLE/bitpack encoded data
 func writeLevels(w io.Writer, levels []uint8, width int32) error {
 	enc, _ := rle.New(width, len(levels)) //TODO: len(levels) is probably too big.  Chop it down a bit?
@@ -444,6 +954,30 @@ func writeLevels(w io.Writer, levels []uint8, width int32) error {
 	return err
 }
 
+// levelsV2 RLE/bitpack encodes levels for a DATA_PAGE_V2 page, which
+// doesn't prefix them with their length.
+func levelsV2(levels []uint8, width int32) []byte {
+	enc, _ := rle.New(width, len(levels))
+	for _, l := range levels {
+		enc.Write(l)
+	}
+	return enc.Bytes()[4:]
+}
+
+// readLevelsV2 reads the n levels of a DATA_PAGE_V2 page.
+func readLevelsV2(data []byte, max uint8, n int) ([]uint8, error) {
+	levels, err := rle.Decode(data, bits.Len(uint(max)), n)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]uint8, len(levels))
+	for i, l := range levels {
+		out[i] = uint8(l)
+	}
+	return out, nil
+}
+
 // readLevels reads the RLE/bitpack encoded definition and repetition levels
 func readLevels(in io.Reader, width int32) ([]uint8, int, error) {
 	var out []uint8
@@ -0,0 +1,599 @@
+package parquet
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"math/big"
+	"sort"
+	"strings"
+
+	sch "github.com/parsyl/parquet/schema"
+)
+
+type op int
+
+const (
+	opEq op = iota
+	opGt
+	opGte
+	opLt
+	opLte
+)
+
+// Predicate is compared to the values of a column in order to decide which
+// rows to read.  The value of a Predicate must be the same type as the
+// column's field, so Gt(int32(30)) works with an int32 or *int32 field
+// (a big.Int field's Predicate needs a *big.Int).
+type Predicate struct {
+	op  op
+	val interface{}
+}
+
+// Eq matches the values that are equal to v.
+func Eq(v interface{}) Predicate { return Predicate{op: opEq, val: v} }
+
+// Gt matches the values that are greater than v.
+func Gt(v interface{}) Predicate { return Predicate{op: opGt, val: v} }
+
+// Gte matches the values that are greater than or equal to v.
+func Gte(v interface{}) Predicate { return Predicate{op: opGte, val: v} }
+
+// Lt matches the values that are less than v.
+func Lt(v interface{}) Predicate { return Predicate{op: opLt, val: v} }
+
+// Lte matches the values that are less than or equal to v.
+func Lte(v interface{}) Predicate { return Predicate{op: opLte, val: v} }
+
+// Filter is a Predicate on one column.  A row matches a Filter if one of
+// its (non-null) values in the column matches the Predicate.
+type Filter struct {
+	Column    string
+	Predicate Predicate
+}
+
+// plain returns the Predicate's value PLAIN encoded (without the length
+// prefix of byte arrays) so it can be compared to the values of the
+// column described by se.
+func (p Predicate) plain(col string, se sch.SchemaElement) ([]byte, error) {
+	var typ sch.Type
+	if se.Type != nil {
+		typ = *se.Type
+	}
+
+	var ok bool
+	var out []byte
+	switch v := p.val.(type) {
+	case int32:
+		ok = typ == sch.Type_INT32 && !unsigned(se)
+		out = binary.LittleEndian.AppendUint32(nil, uint32(v))
+	case uint32:
+		ok = typ == sch.Type_INT32 && unsigned(se)
+		out = binary.LittleEndian.AppendUint32(nil, v)
+	case int64:
+		ok = typ == sch.Type_INT64 && !unsigned(se)
+		out = binary.LittleEndian.AppendUint64(nil, uint64(v))
+	case uint64:
+		ok = typ == sch.Type_INT64 && unsigned(se)
+		out = binary.LittleEndian.AppendUint64(nil, v)
+	case float32:
+		ok = typ == sch.Type_FLOAT
+		out = binary.LittleEndian.AppendUint32(nil, math.Float32bits(v))
+	case float64:
+		ok = typ == sch.Type_DOUBLE
+		out = binary.LittleEndian.AppendUint64(nil, math.Float64bits(v))
+	case bool:
+		ok = typ == sch.Type_BOOLEAN
+		out = []byte{0}
+		if v {
+			out[0] = 1
+		}
+	case string:
+		ok = typ == sch.Type_BYTE_ARRAY || typ == sch.Type_FIXED_LEN_BYTE_ARRAY
+		out = []byte(v)
+	case []byte:
+		ok = typ == sch.Type_BYTE_ARRAY || typ == sch.Type_FIXED_LEN_BYTE_ARRAY
+		out = v
+	case *big.Int:
+		ok = (typ == sch.Type_BYTE_ARRAY || typ == sch.Type_FIXED_LEN_BYTE_ARRAY) && decimal(se)
+		out = make([]byte, v.BitLen()/8+1)
+		if err := PutDecimal(out, v); err != nil {
+			return nil, err
+		}
+	}
+
+	if !ok {
+		return nil, fmt.Errorf("can't compare a value of type %T to column %s of type %s", p.val, col, typ)
+	}
+	return out, nil
+}
+
+// match reports whether the value v matches the Predicate, whose PLAIN
+// encoded value is val.
+func (p Predicate) match(se sch.SchemaElement, val, v []byte) bool {
+	c := compare(se, v, val)
+	switch p.op {
+	case opEq:
+		return c == 0
+	case opGt:
+		return c > 0
+	case opGte:
+		return c >= 0
+	case opLt:
+		return c < 0
+	default:
+		return c <= 0
+	}
+}
+
+// overlaps reports whether a value between min and max (inclusive) might
+// match the Predicate.
+func (p Predicate) overlaps(se sch.SchemaElement, val, min, max []byte) bool {
+	switch p.op {
+	case opEq:
+		return compare(se, min, val) <= 0 && compare(se, max, val) >= 0
+	case opGt:
+		return compare(se, max, val) > 0
+	case opGte:
+		return compare(se, max, val) >= 0
+	case opLt:
+		return compare(se, min, val) < 0
+	default:
+		return compare(se, min, val) <= 0
+	}
+}
+
+// rowRange is the range of rows [start, end) of a row group.
+type rowRange struct {
+	start int64
+	end   int64
+}
+
+// rowRanges are sorted rowRanges that don't overlap.
+type rowRanges []rowRange
+
+func (r rowRanges) count() int64 {
+	var n int64
+	for _, rr := range r {
+		n += rr.end - rr.start
+	}
+	return n
+}
+
+func (r rowRanges) contains(row int64) bool {
+	i := sort.Search(len(r), func(i int) bool { return r[i].end > row })
+	return i < len(r) && r[i].start <= row
+}
+
+// overlaps reports whether any of the rows [start, end) are in r.
+func (r rowRanges) overlaps(start, end int64) bool {
+	i := sort.Search(len(r), func(i int) bool { return r[i].end > start })
+	return i < len(r) && r[i].start < end
+}
+
+func (r rowRanges) intersect(o rowRanges) rowRanges {
+	var out rowRanges
+	for i, j := 0, 0; i < len(r) && j < len(o); {
+		start, end := r[i].start, r[i].end
+		if o[j].start > start {
+			start = o[j].start
+		}
+		if o[j].end < end {
+			end = o[j].end
+		}
+		if start < end {
+			out = out.add(start, end)
+		}
+
+		if r[i].end < o[j].end {
+			i++
+		} else {
+			j++
+		}
+	}
+	return out
+}
+
+// add adds the rows [start, end), which must come after the rows that
+// are already in r.
+func (r rowRanges) add(start, end int64) rowRanges {
+	if len(r) > 0 && r[len(r)-1].end == start {
+		r[len(r)-1].end = end
+		return r
+	}
+	return append(r, rowRange{start: start, end: end})
+}
+
+// Filter makes the Metadata's RowGroups, Pages and Rows only include the
+// rows that match all of the filters.  The statistics of each column chunk
+// and, if the file has them, the ColumnIndex and OffsetIndex of each
+// filtered column chunk are used to skip row groups and pages that can't
+// have any matching rows.  The rest of the pages of the filtered columns
+// are read in order to find the rows that match.  The OffsetIndexes of
+// the other columns are read when their column chunks are.  Filter must
+// be called after ReadFooter.
+func (m *Metadata) Filter(r io.ReadSeeker, filters ...Filter) error {
+	if len(filters) == 0 {
+		return nil
+	}
+
+	filters = append([]Filter(nil), filters...)
+	vals := make([][]byte, len(filters))
+	for i, f := range filters {
+		col, ok := m.Column(f.Column)
+		if !ok {
+			return fmt.Errorf("unknown column %s", f.Column)
+		}
+		filters[i].Column = col
+		se := m.schema.lookup[col]
+
+		var err error
+		if vals[i], err = f.Predicate.plain(f.Column, se); err != nil {
+			return err
+		}
+	}
+
+	m.selected = make([]rowRanges, len(m.metadata.RowGroups))
+	m.locations = make([]map[string][]*sch.PageLocation, len(m.metadata.RowGroups))
+	for i, rg := range m.metadata.RowGroups {
+		skip, err := m.skipRowGroup(rg, filters, vals)
+		if err != nil {
+			return err
+		}
+		if skip {
+			continue
+		}
+
+		sel := rowRanges{{start: 0, end: rg.NumRows}}
+		locations := map[string][]*sch.PageLocation{}
+		for _, f := range filters {
+			oi, err := ReadOffsetIndex(r, columnChunk(rg, f.Column))
+			if err != nil {
+				return err
+			}
+			if oi != nil {
+				locations[f.Column] = oi.PageLocations
+			}
+		}
+
+		for j, f := range filters {
+			sel, err = m.filterChunk(r, rg, columnChunk(rg, f.Column), locations[f.Column], f, vals[j], sel)
+			if err != nil {
+				return err
+			}
+
+			if len(sel) == 0 {
+				break
+			}
+		}
+
+		m.selected[i] = sel
+		m.locations[i] = locations
+	}
+
+	return nil
+}
+
+// skipRowGroup uses the statistics of the row group's column chunks to
+// decide if the row group can be skipped because none of its rows can
+// match the filters.
+func (m *Metadata) skipRowGroup(rg *sch.RowGroup, filters []Filter, vals [][]byte) (bool, error) {
+	for i, f := range filters {
+		ch := columnChunk(rg, f.Column)
+		if ch == nil {
+			return false, fmt.Errorf("row group doesn't have column %s", f.Column)
+		}
+
+		st := ch.MetaData.Statistics
+		if st == nil {
+			continue
+		}
+
+		if st.MinValue != nil && st.MaxValue != nil && !f.Predicate.overlaps(m.schema.lookup[f.Column], vals[i], st.MinValue, st.MaxValue) {
+			return true, nil
+		}
+
+		if st.MinValue == nil && st.MaxValue == nil && st.NullCount != nil && *st.NullCount == ch.MetaData.NumValues {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// filterChunk returns the rows of sel whose values in the column chunk ch
+// match the filter.
+func (m *Metadata) filterChunk(r io.ReadSeeker, rg *sch.RowGroup, ch *sch.ColumnChunk, locations []*sch.PageLocation, f Filter, val []byte, sel rowRanges) (rowRanges, error) {
+	se := m.schema.lookup[f.Column]
+	if locations != nil {
+		ci, err := ReadColumnIndex(r, ch)
+		if err != nil {
+			return nil, err
+		}
+
+		if ci != nil && len(ci.NullPages) == len(locations) {
+			var pages rowRanges
+			for i, loc := range locations {
+				if ci.NullPages[i] || !f.Predicate.overlaps(se, val, ci.MinValues[i], ci.MaxValues[i]) {
+					continue
+				}
+				pages = pages.add(loc.FirstRowIndex, pageEnd(locations, i, rg.NumRows))
+			}
+
+			if sel = sel.intersect(pages); len(sel) == 0 {
+				return nil, nil
+			}
+		}
+	}
+
+	pg := m.page(ch)
+	max := pg.maxLevels(m.maxLevels(f.Column))
+	pg.locations = locations
+	pg.rows = sel
+
+	var out rowRanges
+	err := eachPage(r, pg, max, func(p *page, first int64) error {
+		return p.each(pg.Type, pg.TypeLength, max, first, func(_ int, row int64, v []byte) {
+			// a row of a repeated column only needs to be added once
+			if v == nil || !sel.contains(row) || (len(out) > 0 && out[len(out)-1].end > row) {
+				return
+			}
+
+			if se.Type != nil && *se.Type == sch.Type_BYTE_ARRAY {
+				v = v[4:]
+			}
+
+			if f.Predicate.match(se, val, v) {
+				out = out.add(row, row+1)
+			}
+		})
+	})
+	return out, err
+}
+
+func columnChunk(rg *sch.RowGroup, col string) *sch.ColumnChunk {
+	for _, ch := range rg.Columns {
+		if strings.Join(ch.MetaData.PathInSchema, ".") == col {
+			return ch
+		}
+	}
+	return nil
+}
+
+// pageEnd returns the index of the row after the last row of page i.
+func pageEnd(locations []*sch.PageLocation, i int, rows int64) int64 {
+	if i+1 < len(locations) {
+		return locations[i+1].FirstRowIndex
+	}
+	return rows
+}
+
+// maxLevels returns the max definition and repetition levels of a column.
+func (m *Metadata) maxLevels(col string) MaxLevel {
+	var max MaxLevel
+	for _, f := range m.schema.fields {
+		if strings.Join(f.Path, ".") != col {
+			continue
+		}
+
+		for _, t := range f.Types {
+			if sch.FieldRepetitionType(t) != sch.FieldRepetitionType_REQUIRED {
+				max.Def++
+			}
+			if sch.FieldRepetitionType(t) == sch.FieldRepetitionType_REPEATED {
+				max.Rep++
+			}
+		}
+	}
+	return max
+}
+
+// eachPage calls fn with each data page of the column chunk that has rows
+// in pg.rows, along with the index of the page's first row.  If the column
+// chunk has an OffsetIndex the other pages are skipped without being read.
+func eachPage(r io.ReadSeeker, pg Page, max MaxLevel, fn func(p *page, first int64) error) error {
+	var dict [][]byte
+	if pg.locations == nil {
+		if _, err := r.Seek(pg.Offset, io.SeekStart); err != nil {
+			return err
+		}
+
+		var first int64
+		for nRead := 0; nRead < pg.Size; {
+			rc := &readCounter{r: r}
+			p, err := readPage(rc, pg, max, &dict)
+			if err != nil {
+				return err
+			}
+			nRead += int(rc.n)
+
+			if err := fn(p, first); err != nil {
+				return err
+			}
+			first += p.rows(max)
+		}
+		return nil
+	}
+
+	if len(pg.locations) > 0 && pg.Offset < pg.locations[0].Offset {
+		if err := readDictionaryPage(r, pg, &dict); err != nil {
+			return err
+		}
+	}
+
+	for i, loc := range pg.locations {
+		if !pg.rows.overlaps(loc.FirstRowIndex, pageEnd(pg.locations, i, math.MaxInt64)) {
+			continue
+		}
+
+		if _, err := r.Seek(loc.Offset, io.SeekStart); err != nil {
+			return err
+		}
+
+		p, err := readPage(r, pg, max, &dict)
+		if err != nil {
+			return err
+		}
+
+		if err := fn(p, loc.FirstRowIndex); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// readDictionaryPage reads the dictionary page at the start of a column
+// chunk.
+func readDictionaryPage(r io.ReadSeeker, pg Page, dict *[][]byte) error {
+	if _, err := r.Seek(pg.Offset, io.SeekStart); err != nil {
+		return err
+	}
+
+	ph, err := PageHeader(r)
+	if err != nil {
+		return err
+	}
+
+	if ph.Type != sch.PageType_DICTIONARY_PAGE {
+		return nil
+	}
+
+	data, err := pageData(r, ph, pg)
+	if err != nil {
+		return err
+	}
+
+	*dict, err = readDictionary(pg.Type, pg.TypeLength, data, int(ph.DictionaryPageHeader.NumValues))
+	return err
+}
+
+// readRows reads the pages of the column chunk that have rows in pg.rows
+// and drops the rest of their rows.  The column chunk's OffsetIndex is
+// read unless pg already has its locations.
+func readRows(r io.ReadSeeker, pg Page, max MaxLevel) ([]*page, error) {
+	if pg.locations == nil {
+		oi, err := ReadOffsetIndex(r, pg.chunk)
+		if err != nil {
+			return nil, err
+		}
+		if oi != nil {
+			pg.locations = oi.PageLocations
+		}
+	}
+
+	var out []*page
+	err := eachPage(r, pg, max, func(p *page, first int64) error {
+		p, err := p.selectRows(pg.Type, pg.TypeLength, max, first, pg.rows)
+		if err != nil || p.n == 0 {
+			return err
+		}
+
+		out = append(out, p)
+		return nil
+	})
+	return out, err
+}
+
+// rows returns the number of rows that the page's values belong to.
+func (p *page) rows(max MaxLevel) int64 {
+	if max.Rep == 0 {
+		return int64(p.n)
+	}
+
+	var n int64
+	for _, r := range p.reps {
+		if r == 0 {
+			n++
+		}
+	}
+	return n
+}
+
+// each calls fn with the index of each of the page's levels, the index
+// of the row it belongs to and its PLAIN encoded value (nil for a null
+// value).  Boolean values are passed as a single byte.
+func (p *page) each(typ sch.Type, length int, max MaxLevel, first int64, fn func(i int, row int64, v []byte)) error {
+	vals, err := p.split(typ, length, max)
+	if err != nil {
+		return err
+	}
+
+	row := first - 1
+	var j int
+	for i := 0; i < p.n; i++ {
+		if max.Rep == 0 || p.reps[i] == 0 {
+			row++
+		}
+
+		if max.Def > 0 && p.defs[i] < max.Def {
+			fn(i, row, nil)
+			continue
+		}
+
+		if j >= len(vals) {
+			return fmt.Errorf("page has %d values, expected more", len(vals))
+		}
+		fn(i, row, vals[j])
+		j++
+	}
+	return nil
+}
+
+// split splits the page's non-null PLAIN encoded values.  Boolean
+// values are unpacked into a single byte each.
+func (p *page) split(typ sch.Type, length int, max MaxLevel) ([][]byte, error) {
+	var out [][]byte
+	if typ != sch.Type_BOOLEAN {
+		err := plainValues(typ, length, p.vals, func(v []byte) {
+			out = append(out, v)
+		})
+		return out, err
+	}
+
+	n := p.values(max.Def)
+	for i := 0; i < n; i++ {
+		if i/8 >= len(p.vals) {
+			return nil, fmt.Errorf("page has %d boolean values, expected %d", len(p.vals)*8, n)
+		}
+		out = append(out, []byte{(p.vals[i/8] >> uint(i%8)) & 1})
+	}
+	return out, nil
+}
+
+// selectRows returns a page with only the levels and values of the rows
+// in sel.
+func (p *page) selectRows(typ sch.Type, length int, max MaxLevel, first int64, sel rowRanges) (*page, error) {
+	n := p.rows(max)
+	if sel.intersect(rowRanges{{start: first, end: first + n}}).count() == n {
+		return p, nil
+	}
+
+	out := &page{}
+	var bools int
+	err := p.each(typ, length, max, first, func(i int, row int64, v []byte) {
+		if !sel.contains(row) {
+			return
+		}
+
+		out.n++
+		if max.Rep > 0 {
+			out.reps = append(out.reps, p.reps[i])
+		}
+		if max.Def > 0 {
+			out.defs = append(out.defs, p.defs[i])
+		}
+
+		switch {
+		case v == nil:
+		case typ == sch.Type_BOOLEAN:
+			if bools%8 == 0 {
+				out.vals = append(out.vals, 0)
+			}
+			out.vals[bools/8] |= v[0] << uint(bools%8)
+			bools++
+		default:
+			out.vals = append(out.vals, v...)
+		}
+	})
+	return out, err
+}
@@ -0,0 +1,22 @@
+package parquet
+
+import (
+	sch "github.com/parsyl/parquet/schema"
+)
+
+// FixedType returns the FieldFunc of a FIXED_LEN_BYTE_ARRAY column
+// whose values are length bytes long.
+func FixedType(length int32) FieldFunc {
+	return func(se *sch.SchemaElement) {
+		t := sch.Type_FIXED_LEN_BYTE_ARRAY
+		se.Type = &t
+		se.TypeLength = &length
+	}
+}
+
+// UUIDType is the FieldFunc of a UUID column, which is a 16 byte
+// FIXED_LEN_BYTE_ARRAY.
+func UUIDType(se *sch.SchemaElement) {
+	FixedType(16)(se)
+	se.LogicalType = &sch.LogicalType{UUID: sch.NewUUIDType()}
+}
@@ -3,9 +3,12 @@ module github.com/parsyl/parquet
 go 1.20
 
 require (
+	github.com/andybalholm/brotli v1.1.0
 	github.com/apache/thrift v0.18.1
 	github.com/bxcodec/faker/v3 v3.6.0
 	github.com/golang/snappy v0.0.2
+	github.com/klauspost/compress v1.17.9
+	github.com/pierrec/lz4/v4 v4.1.21
 	github.com/stretchr/testify v1.7.0
 	github.com/valyala/bytebufferpool v1.0.0
 )
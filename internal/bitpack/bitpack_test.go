@@ -64,3 +64,41 @@ func getBytes(vals ...string) []byte {
 	}
 	return out
 }
+
+func TestPackAndUnpackUint64(t *testing.T) {
+	testCases := []struct {
+		name  string
+		width int
+		ints  []uint64
+		bytes []byte
+	}{
+		{
+			name:  "width 3 from apache documentation",
+			width: 3,
+			ints:  []uint64{0, 1, 2, 3, 4, 5, 6, 7},
+			bytes: getBytes("10001000", "11000110", "11111010"),
+		},
+		{
+			name:  "width 13",
+			width: 13,
+			ints:  []uint64{0, 8191, 4096, 1, 2, 3, 77, 1000},
+		},
+		{
+			name:  "width 64",
+			width: 64,
+			ints:  []uint64{1<<64 - 1, 0, 1 << 63, 12345},
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("%d %s", i, tc.name), func(t *testing.T) {
+			b := bitpack.PackUint64(nil, tc.width, tc.ints)
+			if len(tc.bytes) > 0 {
+				assert.Equal(t, tc.bytes, b)
+			}
+			n, ok := bitpack.UnpackUint64(nil, tc.width, b, len(tc.ints))
+			assert.True(t, ok)
+			assert.Equal(t, tc.ints, n)
+		})
+	}
+}
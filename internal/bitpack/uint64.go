@@ -0,0 +1,56 @@
+package bitpack
+
+// PackUint64 appends vals to b, each packed into width bits, starting
+// with the least significant bit.  Unlike Pack it handles any width up
+// to 64 bits, but it is considerably slower.
+func PackUint64(b []byte, width int, vals []uint64) []byte {
+	var cur byte
+	var n int
+	for _, v := range vals {
+		for w := width; w > 0; {
+			take := min(8-n, w)
+			cur |= byte(v&(1<<uint(take)-1)) << uint(n)
+			v >>= uint(take)
+			w -= take
+			n += take
+			if n == 8 {
+				b = append(b, cur)
+				cur, n = 0, 0
+			}
+		}
+	}
+
+	if n > 0 {
+		b = append(b, cur)
+	}
+	return b
+}
+
+// UnpackUint64 reads count values of width bits each from b and
+// appends them to out.  It returns false if b is too short.
+func UnpackUint64(out []uint64, width int, b []byte, count int) ([]uint64, bool) {
+	if len(b)*8 < width*count {
+		return out, false
+	}
+
+	var pos int
+	for i := 0; i < count; i++ {
+		var v uint64
+		for got := 0; got < width; {
+			off := pos % 8
+			take := min(8-off, width-got)
+			v |= uint64((b[pos/8]>>uint(off))&(1<<uint(take)-1)) << uint(got)
+			got += take
+			pos += take
+		}
+		out = append(out, v)
+	}
+	return out, true
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
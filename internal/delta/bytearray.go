@@ -0,0 +1,93 @@
+package delta
+
+import "fmt"
+
+// EncodeLengthByteArray DELTA_LENGTH_BYTE_ARRAY encodes vals: the lengths
+// are DELTA_BINARY_PACKED and are followed by all of the values.
+func EncodeLengthByteArray(vals [][]byte) []byte {
+	lengths := make([]int32, len(vals))
+	for i, v := range vals {
+		lengths[i] = int32(len(v))
+	}
+
+	out := EncodeInt32(lengths)
+	for _, v := range vals {
+		out = append(out, v...)
+	}
+	return out
+}
+
+// DecodeLengthByteArray decodes DELTA_LENGTH_BYTE_ARRAY data and returns
+// the values along with the number of bytes of data that they took up.
+// The values point into data.
+func DecodeLengthByteArray(data []byte) ([][]byte, int, error) {
+	lengths, n, err := DecodeInt32(data)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	out := make([][]byte, len(lengths))
+	for i, l := range lengths {
+		if l < 0 || int(l) > len(data)-n {
+			return nil, 0, fmt.Errorf("invalid DELTA_LENGTH_BYTE_ARRAY length %d", l)
+		}
+		out[i] = data[n : n+int(l) : n+int(l)]
+		n += int(l)
+	}
+	return out, n, nil
+}
+
+// EncodeByteArray DELTA_BYTE_ARRAY encodes vals: the length of the prefix
+// each value shares with the one before it is DELTA_BINARY_PACKED and is
+// followed by the rest of each value, DELTA_LENGTH_BYTE_ARRAY encoded.
+func EncodeByteArray(vals [][]byte) []byte {
+	prefixes := make([]int32, len(vals))
+	suffixes := make([][]byte, len(vals))
+	var prev []byte
+	for i, v := range vals {
+		p := prefixLen(prev, v)
+		prefixes[i] = int32(p)
+		suffixes[i] = v[p:]
+		prev = v
+	}
+	return append(EncodeInt32(prefixes), EncodeLengthByteArray(suffixes)...)
+}
+
+// DecodeByteArray decodes DELTA_BYTE_ARRAY data and returns the values
+// along with the number of bytes of data that they took up.
+func DecodeByteArray(data []byte) ([][]byte, int, error) {
+	prefixes, n, err := DecodeInt32(data)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	suffixes, m, err := DecodeLengthByteArray(data[n:])
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if len(prefixes) != len(suffixes) {
+		return nil, 0, fmt.Errorf("DELTA_BYTE_ARRAY has %d prefixes and %d suffixes", len(prefixes), len(suffixes))
+	}
+
+	out := make([][]byte, len(prefixes))
+	var prev []byte
+	for i, p := range prefixes {
+		if p < 0 || int(p) > len(prev) {
+			return nil, 0, fmt.Errorf("invalid DELTA_BYTE_ARRAY prefix length %d", p)
+		}
+		v := make([]byte, 0, int(p)+len(suffixes[i]))
+		v = append(append(v, prev[:p]...), suffixes[i]...)
+		out[i] = v
+		prev = v
+	}
+	return out, n + m, nil
+}
+
+func prefixLen(a, b []byte) int {
+	var i int
+	for i < len(a) && i < len(b) && a[i] == b[i] {
+		i++
+	}
+	return i
+}
@@ -0,0 +1,235 @@
+// Package delta implements the DELTA_BINARY_PACKED encoding along with the
+// DELTA_LENGTH_BYTE_ARRAY and DELTA_BYTE_ARRAY encodings that are built
+// on top of it.
+package delta
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"math/bits"
+
+	"github.com/parsyl/parquet/internal/bitpack"
+)
+
+const (
+	blockSize     = 128
+	miniBlocks    = 4
+	miniBlockSize = blockSize / miniBlocks
+)
+
+// EncodeInt32 DELTA_BINARY_PACKED encodes vals.  The deltas wrap around
+// like int32 arithmetic so the bit widths never go above 32.
+func EncodeInt32(vals []int32) []byte {
+	out := make([]int64, len(vals))
+	for i, v := range vals {
+		out[i] = int64(v)
+	}
+	return encode(out, 32)
+}
+
+// EncodeInt64 DELTA_BINARY_PACKED encodes vals.
+func EncodeInt64(vals []int64) []byte {
+	return encode(vals, 64)
+}
+
+// DecodeInt32 decodes DELTA_BINARY_PACKED data and returns the values
+// along with the number of bytes of data that they took up.
+func DecodeInt32(data []byte) ([]int32, int, error) {
+	vals, n, err := decode(data, 32)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	out := make([]int32, len(vals))
+	for i, v := range vals {
+		out[i] = int32(v)
+	}
+	return out, n, nil
+}
+
+// DecodeInt64 decodes DELTA_BINARY_PACKED data and returns the values
+// along with the number of bytes of data that they took up.
+func DecodeInt64(data []byte) ([]int64, int, error) {
+	return decode(data, 64)
+}
+
+func encode(vals []int64, width int) []byte {
+	var out []byte
+	out = appendUvarint(out, blockSize)
+	out = appendUvarint(out, miniBlocks)
+	out = appendUvarint(out, uint64(len(vals)))
+	if len(vals) == 0 {
+		return appendUvarint(out, 0)
+	}
+	out = appendUvarint(out, zigzag(vals[0]))
+
+	deltas := make([]int64, 0, blockSize)
+	packed := make([]uint64, miniBlockSize)
+	for i := 1; i < len(vals); i += blockSize {
+		deltas = deltas[:0]
+		for j := i; j < len(vals) && j < i+blockSize; j++ {
+			deltas = append(deltas, wrap(vals[j]-vals[j-1], width))
+		}
+
+		min := deltas[0]
+		for _, d := range deltas {
+			if d < min {
+				min = d
+			}
+		}
+		out = appendUvarint(out, zigzag(min))
+
+		widths := make([]byte, miniBlocks)
+		for m := 0; m*miniBlockSize < len(deltas); m++ {
+			for _, d := range deltas[m*miniBlockSize : minInt((m+1)*miniBlockSize, len(deltas))] {
+				if w := byte(bits.Len64(unsigned(d-min, width))); w > widths[m] {
+					widths[m] = w
+				}
+			}
+		}
+		out = append(out, widths...)
+
+		for m := 0; m*miniBlockSize < len(deltas); m++ {
+			for k := range packed {
+				packed[k] = 0
+				if x := m*miniBlockSize + k; x < len(deltas) {
+					packed[k] = unsigned(deltas[x]-min, width)
+				}
+			}
+			out = bitpack.PackUint64(out, int(widths[m]), packed)
+		}
+	}
+	return out
+}
+
+func decode(data []byte, width int) ([]int64, int, error) {
+	r := bytes.NewReader(data)
+	size, err := readUvarint(r)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	nMini, err := readUvarint(r)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if size == 0 || size%128 != 0 || nMini == 0 || size%nMini != 0 || (size/nMini)%32 != 0 {
+		return nil, 0, fmt.Errorf("invalid DELTA_BINARY_PACKED block size %d with %d miniblocks", size, nMini)
+	}
+
+	total, err := readUvarint(r)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	first, err := readUvarint(r)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var out []int64
+	if total == 0 {
+		return out, len(data) - r.Len(), nil
+	}
+
+	out = append(out, unzigzag(first))
+	perMini := int(size / nMini)
+	widths := make([]byte, nMini)
+	var unpacked []uint64
+	for uint64(len(out)) < total {
+		z, err := readUvarint(r)
+		if err != nil {
+			return nil, 0, err
+		}
+		min := unzigzag(z)
+
+		if _, err := io.ReadFull(r, widths); err != nil {
+			return nil, 0, err
+		}
+
+		for _, w := range widths {
+			if uint64(len(out)) == total {
+				break
+			}
+
+			if int(w) > width {
+				return nil, 0, fmt.Errorf("invalid DELTA_BINARY_PACKED bit width %d", w)
+			}
+
+			packed := make([]byte, perMini*int(w)/8)
+			if _, err := io.ReadFull(r, packed); err != nil {
+				return nil, 0, err
+			}
+
+			var ok bool
+			unpacked, ok = bitpack.UnpackUint64(unpacked[:0], int(w), packed, perMini)
+			if !ok {
+				return nil, 0, fmt.Errorf("not enough DELTA_BINARY_PACKED data")
+			}
+
+			for _, u := range unpacked {
+				if uint64(len(out)) == total {
+					break
+				}
+				out = append(out, wrap(out[len(out)-1]+min+int64(u), width))
+			}
+		}
+	}
+	return out, len(data) - r.Len(), nil
+}
+
+// wrap truncates v to a signed integer of the given width
+func wrap(v int64, width int) int64 {
+	if width == 32 {
+		return int64(int32(v))
+	}
+	return v
+}
+
+// unsigned truncates v to an unsigned integer of the given width
+func unsigned(v int64, width int) uint64 {
+	if width == 32 {
+		return uint64(uint32(v))
+	}
+	return uint64(v)
+}
+
+func zigzag(v int64) uint64 {
+	return uint64(v<<1) ^ uint64(v>>63)
+}
+
+func unzigzag(v uint64) int64 {
+	return int64(v>>1) ^ -int64(v&1)
+}
+
+func appendUvarint(b []byte, v uint64) []byte {
+	for v >= 0x80 {
+		b = append(b, byte(v)|0x80)
+		v >>= 7
+	}
+	return append(b, byte(v))
+}
+
+func readUvarint(r io.ByteReader) (uint64, error) {
+	var out uint64
+	for shift := uint(0); shift < 64; shift += 7 {
+		b, err := r.ReadByte()
+		if err != nil {
+			return 0, err
+		}
+		out |= uint64(b&0x7f) << shift
+		if b < 0x80 {
+			return out, nil
+		}
+	}
+	return 0, fmt.Errorf("uvarint overflows a 64-bit integer")
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
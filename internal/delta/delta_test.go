@@ -0,0 +1,170 @@
+package delta_test
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/parsyl/parquet/internal/delta"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInt64(t *testing.T) {
+	testCases := []struct {
+		name string
+		in   []int64
+		out  []byte
+	}{
+		{name: "empty", in: []int64{}},
+		{name: "single value", in: []int64{-7}},
+		{
+			name: "constant deltas",
+			in:   []int64{1, 2, 3, 4, 5},
+			out:  []byte{0x80, 0x01, 0x04, 0x05, 0x02, 0x02, 0x00, 0x00, 0x00, 0x00},
+		},
+		{name: "increasing", in: sequence(1000, 1600000000, 60)},
+		{name: "exactly one block", in: sequence(129, -5, 3)},
+		{name: "decreasing", in: sequence(300, 1000, -13)},
+		{name: "random", in: pseudoRandom(1000)},
+		{name: "overflow", in: []int64{math.MaxInt64, math.MinInt64, 0, math.MaxInt64, -1}},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("%02d-%s", i, tc.name), func(t *testing.T) {
+			b := delta.EncodeInt64(tc.in)
+			if tc.out != nil {
+				assert.Equal(t, tc.out, b)
+			}
+
+			vals, n, err := delta.DecodeInt64(append(b, 0xff, 0xff))
+			if assert.NoError(t, err) {
+				assert.Equal(t, len(tc.in), len(vals))
+				if len(tc.in) > 0 {
+					assert.Equal(t, tc.in, vals)
+				}
+				assert.Equal(t, len(b), n)
+			}
+		})
+	}
+}
+
+func TestInt32(t *testing.T) {
+	testCases := []struct {
+		name string
+		in   []int32
+	}{
+		{name: "single value", in: []int32{math.MaxInt32}},
+		{name: "increasing", in: []int32{1, 5, 9, 100, 1000, 1001, 1002}},
+		{name: "overflow", in: []int32{math.MaxInt32, math.MinInt32, 0, math.MaxInt32, -1}},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("%02d-%s", i, tc.name), func(t *testing.T) {
+			b := delta.EncodeInt32(tc.in)
+			vals, n, err := delta.DecodeInt32(b)
+			if assert.NoError(t, err) {
+				assert.Equal(t, tc.in, vals)
+				assert.Equal(t, len(b), n)
+			}
+		})
+	}
+}
+
+func TestDecodeErrors(t *testing.T) {
+	_, _, err := delta.DecodeInt64([]byte{0x80, 0x01, 0x04, 0x05, 0x02, 0x02, 0x00, 0x01})
+	assert.Error(t, err)
+
+	_, _, err = delta.DecodeInt64([]byte{0x03, 0x04, 0x05})
+	assert.Error(t, err)
+}
+
+func sequence(n int, start, step int64) []int64 {
+	out := make([]int64, n)
+	for i := range out {
+		out[i] = start + int64(i)*step
+	}
+	return out
+}
+
+func pseudoRandom(n int) []int64 {
+	out := make([]int64, n)
+	x := int64(17)
+	for i := range out {
+		x = (x*1103515245 + 12345) % 2147483648
+		out[i] = x - 1073741824
+	}
+	return out
+}
+
+func TestByteArray(t *testing.T) {
+	testCases := []struct {
+		name string
+		in   []string
+	}{
+		{name: "empty", in: []string{}},
+		{name: "spec example", in: []string{"Hello", "World", "Foobar", "ABCDEF"}},
+		{name: "shared prefixes", in: []string{"axis", "axle", "babble", "babby", "babby", ""}},
+		{name: "urls", in: urls(500)},
+	}
+
+	for i, tc := range testCases {
+		in := make([][]byte, len(tc.in))
+		for j, s := range tc.in {
+			in[j] = []byte(s)
+		}
+
+		t.Run(fmt.Sprintf("%02d-%s-length", i, tc.name), func(t *testing.T) {
+			b := delta.EncodeLengthByteArray(in)
+			vals, n, err := delta.DecodeLengthByteArray(append(b, 0xff))
+			if assert.NoError(t, err) {
+				assert.Equal(t, tc.in, strs(vals))
+				assert.Equal(t, len(b), n)
+			}
+		})
+
+		t.Run(fmt.Sprintf("%02d-%s-incremental", i, tc.name), func(t *testing.T) {
+			b := delta.EncodeByteArray(in)
+			vals, n, err := delta.DecodeByteArray(append(b, 0xff))
+			if assert.NoError(t, err) {
+				assert.Equal(t, tc.in, strs(vals))
+				assert.Equal(t, len(b), n)
+			}
+		})
+	}
+}
+
+func TestByteArraySize(t *testing.T) {
+	in := make([][]byte, 0, 500)
+	var plain int
+	for _, s := range urls(500) {
+		in = append(in, []byte(s))
+		plain += 4 + len(s)
+	}
+	assert.True(t, len(delta.EncodeByteArray(in)) < plain/4)
+}
+
+func TestDecodeByteArrayErrors(t *testing.T) {
+	b := delta.EncodeLengthByteArray([][]byte{[]byte("Hello"), []byte("World")})
+	_, _, err := delta.DecodeLengthByteArray(b[:len(b)-1])
+	assert.Error(t, err)
+
+	b = delta.EncodeByteArray([][]byte{[]byte("Hello"), []byte("Help")})
+	_, _, err = delta.DecodeByteArray(b[:len(b)-1])
+	assert.Error(t, err)
+}
+
+func urls(n int) []string {
+	out := make([]string, n)
+	for i := range out {
+		out[i] = fmt.Sprintf("https://example.com/users/%05d/profile", i)
+	}
+	return out
+}
+
+func strs(vals [][]byte) []string {
+	out := make([]string, len(vals))
+	for i, v := range vals {
+		out[i] = string(v)
+	}
+	return out
+}
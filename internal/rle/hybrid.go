@@ -0,0 +1,138 @@
+package rle
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+
+	"github.com/parsyl/parquet/internal/bitpack"
+)
+
+// Encode run length/bit-pack hybrid encodes vals using width bits for each
+// value.  Unlike RLE it handles widths up to 32 bits and the output is not
+// prefixed by its length, which is how dictionary indices are stored.
+func Encode(vals []uint32, width int) []byte {
+	var out []byte
+	group := make([]uint64, 8)
+	for i := 0; i < len(vals); {
+		if n := runLength(vals, i); n >= 8 {
+			out = appendLEB128(out, uint64(n)<<1)
+			out = appendPadded(out, vals[i], width)
+			i += n
+			continue
+		}
+
+		// bit-packed runs hold groups of 8 values, so they keep going
+		// until the start of a group is also the start of a long run.
+		start := i
+		for i < len(vals) {
+			i += 8
+			if i < len(vals) && runLength(vals, i) >= 8 {
+				break
+			}
+		}
+
+		groups := (min(i, len(vals)) - start + 7) / 8
+		out = appendLEB128(out, uint64(groups)<<1|1)
+		for j := 0; j < groups; j++ {
+			for k := range group {
+				group[k] = 0
+				if x := start + j*8 + k; x < len(vals) {
+					group[k] = uint64(vals[x])
+				}
+			}
+			out = bitpack.PackUint64(out, width, group)
+		}
+		i = min(i, len(vals))
+	}
+	return out
+}
+
+// Decode reads n run length/bit-pack hybrid encoded values of width bits
+// from data.
+func Decode(data []byte, width, n int) ([]uint32, error) {
+	if width > 32 {
+		return nil, fmt.Errorf("bitwidth %d is greater than 32 (highest supported)", width)
+	}
+
+	out := make([]uint32, 0, n)
+	rr := bytes.NewReader(data)
+	var group []uint64
+	for len(out) < n {
+		header, err := readLEB128(rr)
+		if err != nil {
+			return nil, err
+		}
+
+		if header&1 == 0 {
+			count := int(header >> 1)
+			var v uint32
+			for i := 0; i < (width+7)/8; i++ {
+				b, err := rr.ReadByte()
+				if err != nil {
+					return nil, err
+				}
+				v |= uint32(b) << (8 * uint(i))
+			}
+			for i := 0; i < count && len(out) < n; i++ {
+				out = append(out, v)
+			}
+			continue
+		}
+
+		count := int(header>>1) * 8
+		raw := make([]byte, count*width/8)
+		l, err := io.ReadFull(rr, raw)
+		if err != nil && err != io.ErrUnexpectedEOF {
+			return nil, err
+		}
+
+		// some writers don't pad the last bit-packed run
+		if width > 0 && l*8 < count*width {
+			count = l * 8 / width
+		}
+
+		group, _ = bitpack.UnpackUint64(group[:0], width, raw[:l], count)
+		if len(group) == 0 {
+			return nil, fmt.Errorf("not enough bit-packed data for %d values", n)
+		}
+
+		for _, v := range group {
+			if len(out) == n {
+				break
+			}
+			out = append(out, uint32(v))
+		}
+	}
+	return out, nil
+}
+
+func runLength(vals []uint32, i int) int {
+	n := 1
+	for j := i + 1; j < len(vals) && vals[j] == vals[i]; j++ {
+		n++
+	}
+	return n
+}
+
+func appendLEB128(b []byte, v uint64) []byte {
+	for v >= 0x80 {
+		b = append(b, byte(v&mask1|mask2))
+		v >>= 7
+	}
+	return append(b, byte(v))
+}
+
+func appendPadded(b []byte, v uint32, width int) []byte {
+	for i := 0; i < (width+7)/8; i++ {
+		b = append(b, byte(v>>(8*uint(i))))
+	}
+	return b
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
@@ -119,8 +119,8 @@ func (r *RLE) writeIntLittleEndianPaddedOnBitWidth(v uint8, bitWidth int32) ([]b
 		}, nil
 	case 2:
 		return []byte{
-			byte(uint(v>>0) & 0xFF),
-			byte(uint(v>>8) & 0xFF),
+			byte(uint(v)>>0) & 0xFF,
+			byte(uint(v)>>8) & 0xFF,
 		}, nil
 	default:
 		return nil, fmt.Errorf("Encountered value (%d) that requires more than 2 bytes", v)
@@ -268,7 +268,7 @@ func readIntLittleEndianOnTwoBytes(in io.Reader) (uint8, error) {
 	if (b[0] | b[1]) < 0 {
 		return 0, io.EOF
 	}
-	return (uint8(b[1]) << 8) + (uint8(b[0]) << 0), nil
+	return uint8(uint(b[1])<<8 + uint(b[0])), nil
 }
 
 func readLEB128(r io.Reader) (uint64, error) {
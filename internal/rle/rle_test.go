@@ -123,3 +123,44 @@ func repeat(v uint8, c int) []uint8 {
 	}
 	return out
 }
+
+func TestEncodeDecode(t *testing.T) {
+	testCases := []struct {
+		name  string
+		width int
+		in    []uint32
+	}{
+		{name: "single value", width: 1, in: []uint32{1}},
+		{name: "rle only", width: 9, in: append(repeat32(300, 100), repeat32(7, 20)...)},
+		{name: "bitpacking only", width: 10, in: mod32(1000, 1003)},
+		{name: "bitpacking then rle", width: 4, in: append(mod32(10, 13), repeat32(3, 40)...)},
+		{name: "rle then bitpacking", width: 17, in: append(repeat32(70000, 9), mod32(100000, 301)...)},
+		{name: "wide", width: 32, in: []uint32{1<<32 - 1, 0, 1 << 31, 5}},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("%02d-%s", i, tc.name), func(t *testing.T) {
+			b := rle.Encode(tc.in, tc.width)
+			vals, err := rle.Decode(b, tc.width, len(tc.in))
+			if assert.NoError(t, err, tc.name) {
+				assert.Equal(t, tc.in, vals, tc.name)
+			}
+		})
+	}
+}
+
+func mod32(m, c int) []uint32 {
+	out := make([]uint32, c)
+	for i := range out {
+		out[i] = uint32((i * 7) % m)
+	}
+	return out
+}
+
+func repeat32(v uint32, c int) []uint32 {
+	out := make([]uint32, c)
+	for i := range out {
+		out[i] = v
+	}
+	return out
+}
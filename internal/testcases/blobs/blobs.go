@@ -0,0 +1,13 @@
+package blobs
+
+//go:generate parquetgen -input blobs.go -type Person -package blobs -output generated.go
+
+type Person struct {
+	ID        int32     `parquet:"id"`
+	Name      string    `parquet:"name"`
+	Avatar    []byte    `parquet:"avatar"`
+	Token     *[]byte   `parquet:"token,encoding=delta_length_byte_array"`
+	Hash      [16]byte  `parquet:"hash"`
+	Session   *[16]byte `parquet:"session,uuid"`
+	Checksums [][4]byte `parquet:"checksums"`
+}
@@ -0,0 +1,254 @@
+package blobs
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/parsyl/parquet"
+	sch "github.com/parsyl/parquet/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBytes(t *testing.T) {
+	var input []Person
+	for i := 0; i < 25; i++ {
+		input = append(input, newPerson(i))
+	}
+
+	b, out := roundTrip(t, input, MaxPageSize(10))
+	assert.Equal(t, input, out)
+
+	footer, err := parquet.ReadMetaData(bytes.NewReader(b))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	schema := map[string]*sch.SchemaElement{}
+	for _, se := range footer.Schema {
+		schema[se.Name] = se
+	}
+
+	// a string is a STRING but a []byte isn't
+	name := schema["name"]
+	assert.Equal(t, sch.Type_BYTE_ARRAY, name.GetType())
+	assert.Equal(t, sch.ConvertedType_UTF8, name.GetConvertedType())
+	assert.NotNil(t, name.LogicalType.STRING)
+
+	avatar := schema["avatar"]
+	assert.Equal(t, sch.Type_BYTE_ARRAY, avatar.GetType())
+	assert.Nil(t, avatar.ConvertedType)
+	assert.Nil(t, avatar.LogicalType)
+
+	hash := schema["hash"]
+	assert.Equal(t, sch.Type_FIXED_LEN_BYTE_ARRAY, hash.GetType())
+	assert.Equal(t, int32(16), hash.GetTypeLength())
+	assert.Nil(t, hash.LogicalType)
+
+	session := schema["session"]
+	assert.Equal(t, sch.Type_FIXED_LEN_BYTE_ARRAY, session.GetType())
+	assert.Equal(t, int32(16), session.GetTypeLength())
+	assert.NotNil(t, session.LogicalType.UUID)
+
+	checksums := schema["element"]
+	assert.Equal(t, sch.Type_FIXED_LEN_BYTE_ARRAY, checksums.GetType())
+	assert.Equal(t, int32(4), checksums.GetTypeLength())
+	assert.Equal(t, sch.FieldRepetitionType_REQUIRED, checksums.GetRepetitionType())
+
+	min, max := input[0].Hash, input[0].Hash
+	for _, p := range input {
+		if bytes.Compare(p.Hash[:], min[:]) < 0 {
+			min = p.Hash
+		}
+		if bytes.Compare(p.Hash[:], max[:]) > 0 {
+			max = p.Hash
+		}
+	}
+
+	for _, col := range footer.RowGroups[0].Columns {
+		if col.MetaData.PathInSchema[0] == "hash" {
+			assert.Equal(t, min[:], col.MetaData.Statistics.MinValue)
+			assert.Equal(t, max[:], col.MetaData.Statistics.MaxValue)
+		}
+	}
+}
+
+func TestWhere(t *testing.T) {
+	var input []Person
+	for i := 0; i < 350; i++ {
+		input = append(input, newPerson(i))
+	}
+	hash := input[200].Hash
+
+	testCases := []struct {
+		name   string
+		filter func(*ParquetReader)
+		match  func(p Person) bool
+	}{
+		{
+			name:   "bytes",
+			filter: Where("avatar", parquet.Eq([]byte("avatar-42.png"))),
+			match:  func(p Person) bool { return string(p.Avatar) == "avatar-42.png" },
+		},
+		{
+			name:   "fixed",
+			filter: Where("hash", parquet.Gte(hash[:])),
+			match:  func(p Person) bool { return bytes.Compare(p.Hash[:], hash[:]) >= 0 },
+		},
+	}
+
+	b, _ := roundTrip(t, input, MaxPageSize(10))
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("%02d %s", i, tc.name), func(t *testing.T) {
+			var expected []Person
+			for _, p := range input {
+				if tc.match(p) {
+					expected = append(expected, p)
+				}
+			}
+
+			r, err := NewParquetReader(bytes.NewReader(b), tc.filter)
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			var out []Person
+			for r.Next() {
+				var p Person
+				r.Scan(&p)
+				out = append(out, p)
+			}
+			assert.NoError(t, r.Error())
+			assert.Equal(t, expected, out)
+		})
+	}
+}
+
+// TestForeignLists reads a list of fixed length byte arrays that was
+// written by arrow, whose list and elements are optional.
+func TestForeignLists(t *testing.T) {
+	f, err := os.Open("testdata/checksums.parquet")
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer f.Close()
+
+	r, err := NewParquetReader(f, Columns("id", "checksums"))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	var out []Person
+	for r.Next() {
+		var p Person
+		r.Scan(&p)
+		out = append(out, p)
+	}
+	assert.NoError(t, r.Error())
+
+	var expected []Person
+	for i := 0; i < 20; i++ {
+		p := Person{ID: int32(i)}
+		if i%3 == 1 {
+			p.Checksums = [][4]byte{{byte(i), 1, 2, 3}}
+		}
+		expected = append(expected, p)
+	}
+	assert.Equal(t, expected, out)
+
+	f, err = os.Open("testdata/null_elements.parquet")
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer f.Close()
+
+	_, err = NewParquetReader(f, Columns("id", "checksums"))
+	assert.EqualError(t, err, "unable to read field checksums.list.element, err: column checksums.list.element has a null list element, which can't be read into a slice")
+}
+
+func TestRoundTrip(t *testing.T) {
+	var input []Person
+	for i := 0; i < 300; i++ {
+		input = append(input, newPerson(i))
+	}
+
+	opts := []struct {
+		name string
+		opts []func(*ParquetWriter) error
+	}{
+		{name: "plain"},
+		{name: "dictionary", opts: []func(*ParquetWriter) error{Dictionary}},
+		{name: "v2", opts: []func(*ParquetWriter) error{DataPageV2}},
+		{name: "v2 dictionary", opts: []func(*ParquetWriter) error{DataPageV2, Dictionary}},
+	}
+
+	for _, o := range opts {
+		t.Run(o.name, func(t *testing.T) {
+			_, out := roundTrip(t, input, append(o.opts, MaxPageSize(30))...)
+			assert.Equal(t, input, out)
+		})
+	}
+}
+
+func newPerson(i int) Person {
+	p := Person{
+		ID:     int32(i),
+		Name:   fmt.Sprintf("person-%d", i),
+		Avatar: []byte(fmt.Sprintf("avatar-%d.png", i)),
+	}
+
+	if i%3 != 1 {
+		t := []byte{byte(i), byte(i >> 8), 0xff}
+		p.Token = &t
+	}
+
+	binary.BigEndian.PutUint64(p.Hash[8:], uint64(i)*0x9e3779b97f4a7c15)
+
+	if i%2 == 1 {
+		var u [16]byte
+		binary.BigEndian.PutUint32(u[:], uint32(i))
+		u[6] = 0x40 | u[6]&0x0f
+		u[8] = 0x80 | u[8]&0x3f
+		p.Session = &u
+	}
+
+	for j := 0; j < i%3; j++ {
+		var c [4]byte
+		binary.LittleEndian.PutUint32(c[:], uint32(i*31+j))
+		p.Checksums = append(p.Checksums, c)
+	}
+	return p
+}
+
+// roundTrip writes people in row groups of 100 and reads them back.
+func roundTrip(t *testing.T, people []Person, opts ...func(*ParquetWriter) error) ([]byte, []Person) {
+	var buf bytes.Buffer
+	w, err := NewParquetWriter(&buf, opts...)
+	if !assert.NoError(t, err) {
+		return nil, nil
+	}
+	for i, p := range people {
+		w.Add(p)
+		if i%100 == 99 {
+			assert.NoError(t, w.Write())
+		}
+	}
+	assert.NoError(t, w.Write())
+	assert.NoError(t, w.Close())
+
+	r, err := NewParquetReader(bytes.NewReader(buf.Bytes()))
+	if !assert.NoError(t, err) {
+		return buf.Bytes(), nil
+	}
+
+	var out []Person
+	for r.Next() {
+		var p Person
+		r.Scan(&p)
+		out = append(out, p)
+	}
+	assert.NoError(t, r.Error())
+	return buf.Bytes(), out
+}
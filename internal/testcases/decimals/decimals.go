@@ -0,0 +1,18 @@
+package decimals
+
+import "math/big"
+
+//go:generate parquetgen -input decimals.go -type Person -package decimals -output generated.go
+
+type Being struct {
+	ID     int32    `parquet:"id"`
+	Wealth *big.Int `parquet:"wealth,decimal=38:2"`
+}
+
+type Person struct {
+	Being
+	Price    int64   `parquet:"price,decimal=18:2"`
+	Discount *int32  `parquet:"discount,decimal=9:3"`
+	Balance  big.Int `parquet:"balance,decimal=30:4"`
+	Friends  []Being `parquet:"friends"`
+}
@@ -0,0 +1,282 @@
+package decimals
+
+import (
+	"bytes"
+	"fmt"
+	"math/big"
+	"os"
+	"testing"
+
+	"github.com/parsyl/parquet"
+	sch "github.com/parsyl/parquet/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecimal(t *testing.T) {
+	var input []Person
+	for i := 0; i < 25; i++ {
+		input = append(input, newPerson(i))
+	}
+
+	b, out := roundTrip(t, input, MaxPageSize(10))
+	assert.Equal(t, input, out)
+
+	footer, err := parquet.ReadMetaData(bytes.NewReader(b))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	schema := map[string]*sch.SchemaElement{}
+	for _, se := range footer.Schema {
+		schema[se.Name] = se
+	}
+
+	price := schema["price"]
+	assert.Equal(t, sch.Type_INT64, price.GetType())
+	assert.Equal(t, sch.ConvertedType_DECIMAL, price.GetConvertedType())
+	assert.Equal(t, int32(18), price.GetPrecision())
+	assert.Equal(t, int32(2), price.GetScale())
+	assert.Equal(t, &sch.DecimalType{Precision: 18, Scale: 2}, price.LogicalType.DECIMAL)
+
+	discount := schema["discount"]
+	assert.Equal(t, sch.Type_INT32, discount.GetType())
+	assert.Equal(t, &sch.DecimalType{Precision: 9, Scale: 3}, discount.LogicalType.DECIMAL)
+
+	balance := schema["balance"]
+	assert.Equal(t, sch.Type_FIXED_LEN_BYTE_ARRAY, balance.GetType())
+	assert.Equal(t, int32(13), balance.GetTypeLength())
+	assert.Equal(t, &sch.DecimalType{Precision: 30, Scale: 4}, balance.LogicalType.DECIMAL)
+
+	wealth := schema["wealth"]
+	assert.Equal(t, sch.FieldRepetitionType_OPTIONAL, wealth.GetRepetitionType())
+	assert.Equal(t, int32(16), wealth.GetTypeLength())
+
+	// the statistics of a big decimal are signed
+	min, max := input[0].Balance, input[0].Balance
+	for _, p := range input {
+		if p.Balance.Cmp(&min) < 0 {
+			min = p.Balance
+		}
+		if p.Balance.Cmp(&max) > 0 {
+			max = p.Balance
+		}
+	}
+
+	for _, col := range footer.RowGroups[0].Columns {
+		if col.MetaData.PathInSchema[0] == "balance" {
+			stats := col.MetaData.Statistics
+			assert.Equal(t, 0, parquet.Decimal(stats.MinValue).Cmp(&min), parquet.Decimal(stats.MinValue).String())
+			assert.Equal(t, 0, parquet.Decimal(stats.MaxValue).Cmp(&max), parquet.Decimal(stats.MaxValue).String())
+		}
+	}
+}
+
+func TestDecimalTooBig(t *testing.T) {
+	w, err := NewParquetWriter(&bytes.Buffer{})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	var p Person
+	p.Balance.Exp(big.NewInt(10), big.NewInt(32), nil)
+	w.Add(p)
+	assert.EqualError(t, w.Write(), "can't write 100000000000000000000000000000000 to column balance, it doesn't fit in 13 bytes")
+}
+
+// TestForeignDecimals reads decimals that were written by arrow, whose
+// balance is 16 bytes long instead of 13 and whose wealth is a BYTE_ARRAY.
+func TestForeignDecimals(t *testing.T) {
+	f, err := os.Open("../../../testdata/decimals.parquet")
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer f.Close()
+
+	r, err := NewParquetReader(f, Columns("id", "wealth", "price", "discount", "balance"))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	var out []Person
+	for r.Next() {
+		var p Person
+		r.Scan(&p)
+		out = append(out, p)
+	}
+	assert.NoError(t, r.Error())
+
+	var expected []Person
+	for i := 0; i < 20; i++ {
+		p := Person{Being: Being{ID: int32(i)}, Price: int64(i)*99999 - 5000}
+		if i%5 != 0 {
+			p.Wealth = big.NewInt(int64(i) * 1000003)
+		}
+		if i%3 != 0 {
+			d := int32(i) * -7
+			p.Discount = &d
+		}
+		p.Balance.Exp(big.NewInt(10), big.NewInt(int64(i)), nil)
+		p.Balance.Mul(&p.Balance, big.NewInt(int64(i+1)))
+		if i%2 == 1 {
+			p.Balance.Neg(&p.Balance)
+			if p.Wealth != nil {
+				p.Wealth.Neg(p.Wealth)
+			}
+		}
+		expected = append(expected, p)
+	}
+	assert.Equal(t, expected, out)
+
+	// each of the columns of decimals_mismatch.parquet has a different
+	// precision, scale or type from its field.
+	f, err = os.Open("../../../testdata/decimals_mismatch.parquet")
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer f.Close()
+
+	for col, msg := range map[string]string{
+		"price":    "column price is a DECIMAL(10,2), but its field is a DECIMAL(18,2)",
+		"discount": "column discount is INT64, but its field is INT32",
+		"balance":  "column balance is a DECIMAL(30,2), but its field is a DECIMAL(30,4)",
+	} {
+		_, err := NewParquetReader(f, Columns("id", col))
+		assert.EqualError(t, err, fmt.Sprintf("unable to read field %s, err: %s", col, msg))
+	}
+}
+
+func TestWhere(t *testing.T) {
+	var input []Person
+	for i := 0; i < 350; i++ {
+		input = append(input, newPerson(i))
+	}
+
+	testCases := []struct {
+		name   string
+		filter func(*ParquetReader)
+		match  func(p Person) bool
+	}{
+		{
+			name:   "decimal",
+			filter: Where("price", parquet.Gte(int64(9999900-5000))),
+			match:  func(p Person) bool { return p.Price >= 9999900-5000 },
+		},
+		{
+			name:   "big decimal",
+			filter: Where("balance", parquet.Lt(big.NewInt(-1000))),
+			match:  func(p Person) bool { return p.Balance.Cmp(big.NewInt(-1000)) < 0 },
+		},
+	}
+
+	b, _ := roundTrip(t, input, MaxPageSize(10))
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("%02d %s", i, tc.name), func(t *testing.T) {
+			var expected []Person
+			for _, p := range input {
+				if tc.match(p) {
+					expected = append(expected, p)
+				}
+			}
+
+			r, err := NewParquetReader(bytes.NewReader(b), tc.filter)
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			var out []Person
+			for r.Next() {
+				var p Person
+				r.Scan(&p)
+				out = append(out, p)
+			}
+			assert.NoError(t, r.Error())
+			assert.Equal(t, expected, out)
+		})
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	var input []Person
+	for i := 0; i < 300; i++ {
+		input = append(input, newPerson(i))
+	}
+
+	opts := []struct {
+		name string
+		opts []func(*ParquetWriter) error
+	}{
+		{name: "plain"},
+		{name: "dictionary", opts: []func(*ParquetWriter) error{Dictionary}},
+		{name: "v2", opts: []func(*ParquetWriter) error{DataPageV2}},
+		{name: "v2 dictionary", opts: []func(*ParquetWriter) error{DataPageV2, Dictionary}},
+	}
+
+	for _, o := range opts {
+		t.Run(o.name, func(t *testing.T) {
+			_, out := roundTrip(t, input, append(o.opts, MaxPageSize(30))...)
+			assert.Equal(t, input, out)
+		})
+	}
+}
+
+func newPerson(i int) Person {
+	p := Person{
+		Being: Being{ID: int32(i)},
+		Price: int64(i)*99999 - 5000,
+	}
+
+	if i%3 != 0 {
+		d := int32(i%1000) * -7
+		p.Discount = &d
+	}
+
+	// balance is as big as 30 digits and negative for odd i
+	p.Balance.Exp(big.NewInt(10), big.NewInt(int64(i%27)), nil)
+	p.Balance.Mul(&p.Balance, big.NewInt(int64(i%1000+1)))
+	if i%2 == 1 {
+		p.Balance.Neg(&p.Balance)
+	}
+
+	if i%5 != 0 {
+		p.Wealth = big.NewInt(int64(i) * 1000003)
+	}
+	for j := 0; j < i%3; j++ {
+		f := Being{ID: int32(i + j)}
+		if j == 1 {
+			f.Wealth = big.NewInt(int64(-i))
+		}
+		p.Friends = append(p.Friends, f)
+	}
+	return p
+}
+
+// roundTrip writes people in row groups of 100 and reads them back.
+func roundTrip(t *testing.T, people []Person, opts ...func(*ParquetWriter) error) ([]byte, []Person) {
+	var buf bytes.Buffer
+	w, err := NewParquetWriter(&buf, opts...)
+	if !assert.NoError(t, err) {
+		return nil, nil
+	}
+	for i, p := range people {
+		w.Add(p)
+		if i%100 == 99 {
+			assert.NoError(t, w.Write())
+		}
+	}
+	assert.NoError(t, w.Write())
+	assert.NoError(t, w.Close())
+
+	r, err := NewParquetReader(bytes.NewReader(buf.Bytes()))
+	if !assert.NoError(t, err) {
+		return buf.Bytes(), nil
+	}
+
+	var out []Person
+	for r.Next() {
+		var p Person
+		r.Scan(&p)
+		out = append(out, p)
+	}
+	assert.NoError(t, r.Error())
+	return buf.Bytes(), out
+}
@@ -0,0 +1,14 @@
+package maps
+
+//go:generate parquetgen -input maps.go -type Person -package maps -output generated.go
+
+type Hobby struct {
+	Name   string            `parquet:"name"`
+	Scores map[string]*int64 `parquet:"scores"`
+}
+
+type Person struct {
+	ID    int32             `parquet:"id"`
+	Attrs map[string]string `parquet:"attrs"`
+	Hobby *Hobby            `parquet:"hobby"`
+}
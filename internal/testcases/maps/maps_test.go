@@ -0,0 +1,238 @@
+package maps
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/parsyl/parquet"
+	sch "github.com/parsyl/parquet/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMap(t *testing.T) {
+	var input []Person
+	for i := 0; i < 50; i++ {
+		input = append(input, newPerson(i))
+	}
+
+	b, _ := roundTrip(t, input)
+	footer, err := parquet.ReadMetaData(bytes.NewReader(b))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	schema := schemaPaths(footer.Schema)
+	assert.Equal(t, int32(2), schema["hobby"].GetNumChildren())
+	for _, name := range []string{"attrs", "hobby.scores"} {
+		m := schema[name]
+		assert.Equal(t, sch.FieldRepetitionType_OPTIONAL, m.GetRepetitionType())
+		assert.Equal(t, sch.ConvertedType_MAP, m.GetConvertedType())
+		assert.NotNil(t, m.LogicalType.MAP)
+		assert.Equal(t, int32(1), m.GetNumChildren())
+
+		kv := schema[name+".key_value"]
+		assert.Equal(t, sch.FieldRepetitionType_REPEATED, kv.GetRepetitionType())
+		assert.Nil(t, kv.LogicalType)
+		assert.Equal(t, int32(2), kv.GetNumChildren())
+		assert.Equal(t, sch.FieldRepetitionType_REQUIRED, schema[name+".key_value.key"].GetRepetitionType())
+	}
+	assert.Equal(t, sch.FieldRepetitionType_REQUIRED, schema["attrs.key_value.value"].GetRepetitionType())
+	assert.Equal(t, sch.FieldRepetitionType_OPTIONAL, schema["hobby.scores.key_value.value"].GetRepetitionType())
+}
+
+func TestRoundTrip(t *testing.T) {
+	var input []Person
+	for i := 0; i < 300; i++ {
+		input = append(input, newPerson(i))
+	}
+
+	opts := []struct {
+		name string
+		opts []func(*ParquetWriter) error
+	}{
+		{name: "plain"},
+		{name: "dictionary", opts: []func(*ParquetWriter) error{Dictionary}},
+		{name: "v2", opts: []func(*ParquetWriter) error{DataPageV2}},
+		{name: "v2 dictionary", opts: []func(*ParquetWriter) error{DataPageV2, Dictionary}},
+	}
+
+	for _, o := range opts {
+		t.Run(o.name, func(t *testing.T) {
+			b, out := roundTrip(t, input, append(o.opts, MaxPageSize(30))...)
+			assert.Equal(t, input, out)
+
+			r, err := NewParquetReader(bytes.NewReader(b))
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			batch := make([]Person, 7)
+			out = out[:0]
+			for {
+				n, err := r.ReadBatch(batch)
+				out = append(out, batch[:n]...)
+				if err == io.EOF {
+					break
+				}
+				if !assert.NoError(t, err) {
+					return
+				}
+			}
+			assert.Equal(t, input, out)
+		})
+	}
+}
+
+func TestColumns(t *testing.T) {
+	var input []Person
+	for i := 0; i < 250; i++ {
+		input = append(input, newPerson(i))
+	}
+
+	testCases := []struct {
+		name    string
+		cols    []string
+		project func(Person) Person
+	}{
+		{
+			name: "map",
+			cols: []string{"attrs"},
+			project: func(p Person) Person {
+				return Person{Attrs: p.Attrs}
+			},
+		},
+		{
+			name: "map keys",
+			cols: []string{"attrs.key_value.key"},
+			project: func(p Person) Person {
+				var out Person
+				if p.Attrs != nil {
+					out.Attrs = map[string]string{}
+				}
+				for k := range p.Attrs {
+					out.Attrs[k] = ""
+				}
+				return out
+			},
+		},
+		{
+			name: "map values",
+			cols: []string{"attrs.key_value.value"},
+			project: func(p Person) Person {
+				return Person{Attrs: p.Attrs}
+			},
+		},
+		{
+			name: "nested map",
+			cols: []string{"hobby.scores"},
+			project: func(p Person) Person {
+				if p.Hobby == nil {
+					return Person{}
+				}
+				return Person{Hobby: &Hobby{Scores: p.Hobby.Scores}}
+			},
+		},
+	}
+
+	b, _ := roundTrip(t, input, MaxPageSize(10))
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("%02d %s", i, tc.name), func(t *testing.T) {
+			var expected []Person
+			for _, p := range input {
+				expected = append(expected, tc.project(p))
+			}
+
+			r, err := NewParquetReader(bytes.NewReader(b), Columns(tc.cols...))
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			var out []Person
+			for r.Next() {
+				var p Person
+				r.Scan(&p)
+				out = append(out, p)
+			}
+			assert.NoError(t, r.Error())
+			assert.Equal(t, expected, out)
+		})
+	}
+}
+
+func newPerson(i int) Person {
+	p := Person{ID: int32(i)}
+
+	// attrs is nil, empty or has up to three entries
+	if i%4 != 0 {
+		p.Attrs = map[string]string{}
+		for j := 0; j < i%4-1; j++ {
+			p.Attrs[fmt.Sprintf("attr-%d", j)] = fmt.Sprintf("value-%d", i*j)
+		}
+	}
+
+	switch i % 5 {
+	case 1:
+		p.Hobby = &Hobby{Name: "knitting"}
+	case 2:
+		p.Hobby = &Hobby{Name: "sewing", Scores: map[string]*int64{}}
+	case 3, 4:
+		speed := int64(i)
+		p.Hobby = &Hobby{Name: "kayaking", Scores: map[string]*int64{"speed": &speed, "style": nil}}
+		if i%2 == 0 {
+			endurance := int64(-i)
+			p.Hobby.Scores["endurance"] = &endurance
+		}
+	}
+	return p
+}
+
+// roundTrip writes people in row groups of 100 and reads them back.
+func roundTrip(t *testing.T, people []Person, opts ...func(*ParquetWriter) error) ([]byte, []Person) {
+	var buf bytes.Buffer
+	w, err := NewParquetWriter(&buf, opts...)
+	if !assert.NoError(t, err) {
+		return nil, nil
+	}
+	for i, p := range people {
+		w.Add(p)
+		if i%100 == 99 {
+			assert.NoError(t, w.Write())
+		}
+	}
+	assert.NoError(t, w.Write())
+	assert.NoError(t, w.Close())
+
+	r, err := NewParquetReader(bytes.NewReader(buf.Bytes()))
+	if !assert.NoError(t, err) {
+		return buf.Bytes(), nil
+	}
+
+	var out []Person
+	for r.Next() {
+		var p Person
+		r.Scan(&p)
+		out = append(out, p)
+	}
+	assert.NoError(t, r.Error())
+	return buf.Bytes(), out
+}
+
+// schemaPaths returns the schema elements by their dotted paths.
+func schemaPaths(schema []*sch.SchemaElement) map[string]*sch.SchemaElement {
+	out := map[string]*sch.SchemaElement{}
+	var walk func(i int, prefix string) int
+	walk = func(i int, prefix string) int {
+		se := schema[i]
+		i++
+		for j := int32(0); j < se.GetNumChildren(); j++ {
+			name := prefix + schema[i].Name
+			out[name] = schema[i]
+			i = walk(i, name+".")
+		}
+		return i
+	}
+	walk(0, "")
+	return out
+}
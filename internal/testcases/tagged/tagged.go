@@ -0,0 +1,28 @@
+package tagged
+
+//go:generate parquetgen -input tagged.go -type Person -package tagged -output generated.go
+
+type Being struct {
+	ID  int32  `parquet:"id"`
+	Age *int32 `parquet:"age,encoding=delta_binary_packed"`
+}
+
+type Skill struct {
+	Name       string `parquet:"name,encoding=delta_length_byte_array"`
+	Difficulty string `parquet:"difficulty,codec=zstd"`
+}
+
+type Hobby struct {
+	Name   string  `parquet:"name,encoding=delta_byte_array"`
+	Skills []Skill `parquet:"skills"`
+}
+
+type Person struct {
+	Being
+	Happiness   int64    `parquet:"happiness,encoding=delta_binary_packed"`
+	Anniversary *uint64  `parquet:"anniversary,encoding=delta_binary_packed"`
+	Boldness    float64  `parquet:"boldness,encoding=byte_stream_split"`
+	Lameness    *float32 `parquet:"lameness,encoding=byte_stream_split"`
+	Hobby       *Hobby   `parquet:"hobby"`
+	Friends     []Being  `parquet:"friends"`
+}
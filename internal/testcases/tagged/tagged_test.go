@@ -0,0 +1,261 @@
+package tagged
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/parsyl/parquet"
+	sch "github.com/parsyl/parquet/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEncodings(t *testing.T) {
+	type testCase struct {
+		name      string
+		input     []Person
+		pageSize  int
+		opts      []func(*ParquetWriter) error
+		col       string
+		encodings []sch.Encoding
+	}
+
+	testCases := []testCase{
+		{
+			name:      "delta binary packed int64",
+			col:       "happiness",
+			pageSize:  2,
+			input:     []Person{{Happiness: 1600000000}, {Happiness: 1600000060}, {Happiness: 1600000120}},
+			encodings: []sch.Encoding{sch.Encoding_DELTA_BINARY_PACKED, sch.Encoding_DELTA_BINARY_PACKED},
+		},
+		{
+			name:      "delta binary packed optional int32",
+			col:       "age",
+			input:     []Person{{Being: Being{Age: pint32(10)}}, {}, {Being: Being{Age: pint32(11)}}},
+			encodings: []sch.Encoding{sch.Encoding_DELTA_BINARY_PACKED},
+		},
+		{
+			name:      "delta binary packed takes precedence over dictionary",
+			col:       "anniversary",
+			opts:      []func(*ParquetWriter) error{Dictionary},
+			input:     []Person{{Anniversary: puint64(5)}, {Anniversary: puint64(5)}},
+			encodings: []sch.Encoding{sch.Encoding_DELTA_BINARY_PACKED},
+		},
+		{
+			name:      "delta binary packed repeated",
+			col:       "friends.list.element.age",
+			opts:      []func(*ParquetWriter) error{DataPageV2},
+			input:     []Person{{Friends: []Being{{Age: pint32(3)}, {}}}, {}},
+			encodings: []sch.Encoding{sch.Encoding_DELTA_BINARY_PACKED},
+		},
+		{
+			name:     "delta byte array optional",
+			col:      "hobby.name",
+			pageSize: 2,
+			opts:     []func(*ParquetWriter) error{Dictionary},
+			input: []Person{
+				{Hobby: &Hobby{Name: "https://example.com/hobbies/knitting"}},
+				{},
+				{Hobby: &Hobby{Name: "https://example.com/hobbies/kayaking"}},
+				{Hobby: &Hobby{Name: "https://example.com/hobbies/kayaking"}},
+			},
+			encodings: []sch.Encoding{sch.Encoding_DELTA_BYTE_ARRAY, sch.Encoding_DELTA_BYTE_ARRAY},
+		},
+		{
+			name: "delta length byte array repeated",
+			col:  "hobby.skills.list.element.name",
+			opts: []func(*ParquetWriter) error{DataPageV2},
+			input: []Person{
+				{Hobby: &Hobby{Name: "a", Skills: []Skill{{Name: "knit"}, {Name: ""}, {Name: "purl"}}}},
+				{Hobby: &Hobby{Name: "b"}},
+			},
+			encodings: []sch.Encoding{sch.Encoding_DELTA_LENGTH_BYTE_ARRAY},
+		},
+		{
+			name:      "byte stream split float64",
+			col:       "boldness",
+			pageSize:  2,
+			input:     []Person{{Boldness: 20.125}, {Boldness: 20.25}, {Boldness: -3}},
+			encodings: []sch.Encoding{sch.Encoding_BYTE_STREAM_SPLIT, sch.Encoding_BYTE_STREAM_SPLIT},
+		},
+		{
+			name:      "byte stream split optional float32",
+			col:       "lameness",
+			opts:      []func(*ParquetWriter) error{Dictionary, DataPageV2},
+			input:     []Person{{Lameness: pfloat32(0.5)}, {}, {Lameness: pfloat32(0.5)}},
+			encodings: []sch.Encoding{sch.Encoding_BYTE_STREAM_SPLIT},
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("%02d %s", i, tc.name), func(t *testing.T) {
+			if tc.pageSize == 0 {
+				tc.pageSize = 100
+			}
+			b, out := roundTrip(t, tc.input, append(tc.opts, MaxPageSize(tc.pageSize))...)
+			assert.Equal(t, tc.input, out)
+			assert.Equal(t, tc.encodings, encodings(t, b, tc.col))
+		})
+	}
+}
+
+func TestCodecs(t *testing.T) {
+	testCases := []struct {
+		name  string
+		opts  []func(*ParquetWriter) error
+		codec sch.CompressionCodec
+		// difficulty is the codec of the column with the codec tag
+		difficulty sch.CompressionCodec
+	}{
+		{name: "uncompressed", opts: []func(*ParquetWriter) error{Uncompressed}, codec: sch.CompressionCodec_UNCOMPRESSED, difficulty: sch.CompressionCodec_ZSTD},
+		{name: "snappy", opts: []func(*ParquetWriter) error{Snappy}, codec: sch.CompressionCodec_SNAPPY, difficulty: sch.CompressionCodec_ZSTD},
+		{name: "gzip", opts: []func(*ParquetWriter) error{Gzip}, codec: sch.CompressionCodec_GZIP, difficulty: sch.CompressionCodec_ZSTD},
+		{
+			name:       "column compression",
+			opts:       []func(*ParquetWriter) error{Gzip, ColumnCompression("hobby.skills.difficulty", Snappy)},
+			codec:      sch.CompressionCodec_GZIP,
+			difficulty: sch.CompressionCodec_SNAPPY,
+		},
+	}
+
+	input := []Person{
+		{Hobby: &Hobby{Name: "knitting"}},
+		{Hobby: &Hobby{Name: "kayaking", Skills: []Skill{{Name: "paddling", Difficulty: "hard"}}}},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("%02d %s", i, tc.name), func(t *testing.T) {
+			b, out := roundTrip(t, input, tc.opts...)
+			assert.Equal(t, input, out)
+
+			footer, err := parquet.ReadMetaData(bytes.NewReader(b))
+			if !assert.NoError(t, err) {
+				return
+			}
+			for _, col := range footer.RowGroups[0].Columns {
+				name := strings.Join(col.MetaData.PathInSchema, ".")
+				if name == "hobby.skills.list.element.difficulty" {
+					assert.Equal(t, tc.difficulty, col.MetaData.Codec, name)
+				} else {
+					assert.Equal(t, tc.codec, col.MetaData.Codec, name)
+				}
+			}
+		})
+	}
+}
+
+// TestRoundTrip writes enough people to fill several pages of each
+// column with each of the writer's encodings.
+func TestRoundTrip(t *testing.T) {
+	var input []Person
+	for i := 0; i < 300; i++ {
+		input = append(input, newPerson(i))
+	}
+
+	opts := []struct {
+		name string
+		opts []func(*ParquetWriter) error
+	}{
+		{name: "plain"},
+		{name: "dictionary", opts: []func(*ParquetWriter) error{Dictionary}},
+		{name: "v2", opts: []func(*ParquetWriter) error{DataPageV2}},
+		{name: "v2 dictionary", opts: []func(*ParquetWriter) error{DataPageV2, Dictionary}},
+	}
+
+	for _, o := range opts {
+		t.Run(o.name, func(t *testing.T) {
+			_, out := roundTrip(t, input, append(o.opts, MaxPageSize(30))...)
+			assert.Equal(t, input, out)
+		})
+	}
+}
+
+func newPerson(i int) Person {
+	p := Person{
+		Being:     Being{ID: int32(i)},
+		Happiness: 1600000000 + int64(i*i%7*60),
+		Boldness:  float64(i) / 8,
+	}
+	if i%2 == 0 {
+		p.Age = pint32(int32(20 + i%5))
+	}
+	if i%5 != 0 {
+		p.Lameness = pfloat32(float32(-i) / 3)
+	}
+	if i%3 == 0 {
+		p.Anniversary = puint64(math.MaxUint64 - uint64(i*100))
+	}
+	if i%4 != 0 {
+		p.Hobby = &Hobby{Name: fmt.Sprintf("https://example.com/hobbies/%d", i/10)}
+		for j := 0; j < i%4-1; j++ {
+			p.Hobby.Skills = append(p.Hobby.Skills, Skill{Name: fmt.Sprintf("skill-%d", i*j), Difficulty: []string{"easy", "hard"}[j%2]})
+		}
+	}
+	for j := 0; j < i%3; j++ {
+		p.Friends = append(p.Friends, Being{ID: int32(i + j), Age: pint32(int32(-i))})
+	}
+	return p
+}
+
+// roundTrip writes people in row groups of 100 and reads them back.
+func roundTrip(t *testing.T, people []Person, opts ...func(*ParquetWriter) error) ([]byte, []Person) {
+	var buf bytes.Buffer
+	w, err := NewParquetWriter(&buf, opts...)
+	if !assert.NoError(t, err) {
+		return nil, nil
+	}
+	for i, p := range people {
+		w.Add(p)
+		if i%100 == 99 {
+			assert.NoError(t, w.Write())
+		}
+	}
+	assert.NoError(t, w.Write())
+	assert.NoError(t, w.Close())
+
+	r, err := NewParquetReader(bytes.NewReader(buf.Bytes()))
+	if !assert.NoError(t, err) {
+		return buf.Bytes(), nil
+	}
+
+	var out []Person
+	for r.Next() {
+		var p Person
+		r.Scan(&p)
+		out = append(out, p)
+	}
+	assert.NoError(t, r.Error())
+	return buf.Bytes(), out
+}
+
+// encodings returns the encoding of each data page of a column.
+func encodings(t *testing.T, b []byte, col string) []sch.Encoding {
+	r := bytes.NewReader(b)
+	footer, err := parquet.ReadMetaData(r)
+	if !assert.NoError(t, err) {
+		return nil
+	}
+
+	var out []sch.Encoding
+	for _, rg := range footer.RowGroups {
+		for _, ch := range rg.Columns {
+			if strings.Join(ch.MetaData.PathInSchema, ".") != col {
+				continue
+			}
+			pages, err := parquet.PageHeadersAtOffset(r, ch.MetaData.DataPageOffset, ch.MetaData.NumValues)
+			if !assert.NoError(t, err) {
+				return nil
+			}
+			for _, ph := range pages {
+				if ph.DataPageHeaderV2 != nil {
+					out = append(out, ph.DataPageHeaderV2.Encoding)
+				} else {
+					out = append(out, ph.DataPageHeader.Encoding)
+				}
+			}
+		}
+	}
+	return out
+}
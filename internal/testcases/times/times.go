@@ -0,0 +1,18 @@
+package times
+
+import "time"
+
+//go:generate parquetgen -input times.go -type Person -package times -output generated.go
+
+type Being struct {
+	ID  int32      `parquet:"id"`
+	Met *time.Time `parquet:"met,unit=nanos,utc"`
+}
+
+type Person struct {
+	Being
+	Joined   time.Time  `parquet:"joined,unit=millis,utc"`
+	LastSeen *time.Time `parquet:"last_seen"`
+	Born     *time.Time `parquet:"born,date"`
+	Friends  []Being    `parquet:"friends"`
+}
@@ -0,0 +1,229 @@
+package times
+
+import (
+	"bytes"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/parsyl/parquet"
+	sch "github.com/parsyl/parquet/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSchema(t *testing.T) {
+	b, _ := roundTrip(t, []Person{newPerson(1)})
+	footer, err := parquet.ReadMetaData(bytes.NewReader(b))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	schema := map[string]*sch.SchemaElement{}
+	for _, se := range footer.Schema {
+		schema[se.Name] = se
+	}
+
+	joined := schema["joined"]
+	assert.Equal(t, sch.Type_INT64, joined.GetType())
+	assert.Equal(t, sch.ConvertedType_TIMESTAMP_MILLIS, joined.GetConvertedType())
+	assert.True(t, joined.LogicalType.TIMESTAMP.IsAdjustedToUTC)
+	assert.NotNil(t, joined.LogicalType.TIMESTAMP.Unit.MILLIS)
+
+	// the converted types only exist for times in UTC
+	lastSeen := schema["last_seen"]
+	assert.Equal(t, sch.Type_INT64, lastSeen.GetType())
+	assert.Nil(t, lastSeen.ConvertedType)
+	assert.False(t, lastSeen.LogicalType.TIMESTAMP.IsAdjustedToUTC)
+	assert.NotNil(t, lastSeen.LogicalType.TIMESTAMP.Unit.MICROS)
+
+	met := schema["met"]
+	assert.Nil(t, met.ConvertedType)
+	assert.NotNil(t, met.LogicalType.TIMESTAMP.Unit.NANOS)
+
+	born := schema["born"]
+	assert.Equal(t, sch.Type_INT32, born.GetType())
+	assert.Equal(t, sch.ConvertedType_DATE, born.GetConvertedType())
+	assert.NotNil(t, born.LogicalType.DATE)
+}
+
+func TestRoundTrip(t *testing.T) {
+	var input []Person
+	for i := 0; i < 300; i++ {
+		input = append(input, newPerson(i))
+	}
+
+	opts := []struct {
+		name string
+		opts []func(*ParquetWriter) error
+	}{
+		{name: "plain"},
+		{name: "dictionary", opts: []func(*ParquetWriter) error{Dictionary}},
+		{name: "v2", opts: []func(*ParquetWriter) error{DataPageV2}},
+		{name: "v2 dictionary", opts: []func(*ParquetWriter) error{DataPageV2, Dictionary}},
+	}
+
+	for _, o := range opts {
+		t.Run(o.name, func(t *testing.T) {
+			_, out := roundTrip(t, input, append(o.opts, MaxPageSize(30))...)
+			assert.Equal(t, input, out)
+		})
+	}
+}
+
+// TestWallClock reads a time that isn't in UTC from a column that isn't
+// adjusted to UTC, which holds its wall clock time.
+func TestWallClock(t *testing.T) {
+	loc := time.FixedZone("UTC+5", 5*60*60)
+	seen := time.Date(2021, 3, 4, 5, 6, 7, 8000, loc)
+	born := time.Date(1999, 12, 31, 23, 0, 0, 0, loc)
+
+	_, out := roundTrip(t, []Person{{LastSeen: &seen, Born: &born}})
+	if assert.Len(t, out, 1) {
+		assert.Equal(t, time.Date(2021, 3, 4, 5, 6, 7, 8000, time.UTC), *out[0].LastSeen)
+		assert.Equal(t, time.Date(1999, 12, 31, 0, 0, 0, 0, time.UTC), *out[0].Born)
+	}
+}
+
+func TestOutOfRange(t *testing.T) {
+	testCases := []struct {
+		name string
+		p    Person
+		err  string
+	}{
+		{
+			name: "zero time in nanos",
+			p:    Person{Friends: []Being{{Met: &time.Time{}}}},
+			err:  "column friends.list.element.met: time 0001-01-01T00:00:00Z is out of the range of a column in nanoseconds",
+		},
+		{
+			name: "after 2262 in nanos",
+			p:    Person{Friends: []Being{{Met: ptime(time.Date(2262, 4, 12, 0, 0, 0, 0, time.UTC))}}},
+			err:  "column friends.list.element.met: time 2262-04-12T00:00:00Z is out of the range of a column in nanoseconds",
+		},
+		{
+			name: "before 1678 in nanos",
+			p:    Person{Friends: []Being{{Met: ptime(time.Date(1677, 9, 21, 0, 0, 0, 0, time.UTC))}}},
+			err:  "column friends.list.element.met: time 1677-09-21T00:00:00Z is out of the range of a column in nanoseconds",
+		},
+		{
+			name: "date",
+			p:    Person{Born: ptime(time.Date(6000000, 1, 1, 0, 0, 0, 0, time.UTC))},
+			err:  "column born: time 6000000-01-01T00:00:00Z is out of the range of a column in days",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			w, err := NewParquetWriter(&bytes.Buffer{})
+			if !assert.NoError(t, err) {
+				return
+			}
+			w.Add(tc.p)
+			assert.EqualError(t, w.Write(), tc.err)
+		})
+	}
+
+	// the times at the ends of the range of nanos can be written
+	min := time.Date(1677, 9, 21, 0, 12, 44, 0, time.UTC)
+	max := time.Date(2262, 4, 11, 23, 47, 16, 0, time.UTC)
+	input := []Person{{Friends: []Being{{Met: &min}, {Met: &max}}}}
+	_, out := roundTrip(t, input)
+	assert.Equal(t, input, out)
+}
+
+// TestForeignUnits reads times that were written by arrow in different
+// units from the ones in the struct tags.
+func TestForeignUnits(t *testing.T) {
+	f, err := os.Open("testdata/units.parquet")
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer f.Close()
+
+	r, err := NewParquetReader(f, Columns("id", "joined", "last_seen", "born"))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	var out []Person
+	for r.Next() {
+		var p Person
+		r.Scan(&p)
+		out = append(out, p)
+	}
+	assert.NoError(t, r.Error())
+
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	var expected []Person
+	for i := 0; i < 20; i++ {
+		joined := start.Add(time.Duration(i) * 1001 * time.Microsecond)
+		p := Person{Being: Being{ID: int32(i)}, Joined: joined}
+		if i%4 != 0 {
+			p.LastSeen = ptime(joined.Add(time.Duration(i) * time.Hour).Truncate(time.Millisecond))
+			p.Born = ptime(time.Date(1950+i, time.Month(1+i%12), 1+i, 0, 0, 0, 0, time.UTC))
+		}
+		expected = append(expected, p)
+	}
+	assert.Equal(t, expected, out)
+
+	f, err = os.Open("testdata/born_timestamp.parquet")
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer f.Close()
+
+	_, err = NewParquetReader(f, Columns("id", "born"))
+	assert.EqualError(t, err, "unable to read field born, err: column born is in microseconds, but its field is in days")
+}
+
+func ptime(t time.Time) *time.Time { return &t }
+
+func newPerson(i int) Person {
+	joined := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC).Add(time.Duration(i) * 1001 * time.Millisecond)
+	p := Person{
+		Being:  Being{ID: int32(i)},
+		Joined: joined,
+	}
+	if i%4 != 0 {
+		seen := joined.Add(time.Duration(i) * time.Microsecond)
+		p.LastSeen = &seen
+		born := time.Date(1950+i%100, time.Month(1+i%12), 1+i%28, 0, 0, 0, 0, time.UTC)
+		p.Born = &born
+	}
+	for j := 0; j < i%3; j++ {
+		met := joined.Add(time.Duration(i*j) * time.Nanosecond)
+		p.Friends = append(p.Friends, Being{ID: int32(i + j), Met: &met})
+	}
+	return p
+}
+
+// roundTrip writes people in row groups of 100 and reads them back.
+func roundTrip(t *testing.T, people []Person, opts ...func(*ParquetWriter) error) ([]byte, []Person) {
+	var buf bytes.Buffer
+	w, err := NewParquetWriter(&buf, opts...)
+	if !assert.NoError(t, err) {
+		return nil, nil
+	}
+	for i, p := range people {
+		w.Add(p)
+		if i%100 == 99 {
+			assert.NoError(t, w.Write())
+		}
+	}
+	assert.NoError(t, w.Write())
+	assert.NoError(t, w.Close())
+
+	r, err := NewParquetReader(bytes.NewReader(buf.Bytes()))
+	if !assert.NoError(t, err) {
+		return buf.Bytes(), nil
+	}
+
+	var out []Person
+	for r.Next() {
+		var p Person
+		r.Scan(&p)
+		out = append(out, p)
+	}
+	assert.NoError(t, r.Error())
+	return buf.Bytes(), out
+}
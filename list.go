@@ -0,0 +1,348 @@
+package parquet
+
+import (
+	"fmt"
+	"strings"
+
+	sch "github.com/parsyl/parquet/schema"
+)
+
+// ListType sets the logical type of a group to LIST.  The group holds
+// a repeated group named list whose only child, element, is the
+// value of each of the list's items.
+func ListType(se *sch.SchemaElement) {
+	ct := sch.ConvertedType_LIST
+	se.ConvertedType = &ct
+	se.LogicalType = &sch.LogicalType{LIST: sch.NewListType()}
+}
+
+func isList(se *sch.SchemaElement) bool {
+	return se.GetConvertedType() == sch.ConvertedType_LIST || (se.LogicalType != nil && se.LogicalType.LIST != nil)
+}
+
+// segment is one of the groups (or the column) in a column's path,
+// except that a list's LIST group, repeated group and element are a
+// single segment.  The segments of a list written in one of the legacy
+// layouts (a repeated field or a LIST group whose repeated group is the
+// element) have the same name as the list's standard segments, so a
+// column can be matched to its field no matter how its lists are
+// written.
+type segment struct {
+	name  string
+	types []sch.FieldRepetitionType
+	list  bool
+}
+
+func segmentsName(segs []segment) string {
+	names := make([]string, len(segs))
+	for i, s := range segs {
+		names[i] = s.name
+	}
+	return strings.Join(names, ".")
+}
+
+func segmentTypes(segs []segment) []sch.FieldRepetitionType {
+	var out []sch.FieldRepetitionType
+	for _, s := range segs {
+		out = append(out, s.types...)
+	}
+	return out
+}
+
+// fieldSegments returns the segments of a field's path.
+func fieldSegments(f Field) []segment {
+	typ := func(i int) sch.FieldRepetitionType {
+		if i < len(f.Types) {
+			return sch.FieldRepetitionType(f.Types[i])
+		}
+		return sch.FieldRepetitionType_REQUIRED
+	}
+
+	var out []segment
+	for i := 0; i < len(f.Path); i++ {
+		if i < len(f.Groups) && f.Groups[i] != nil && i+2 < len(f.Path) {
+			se := &sch.SchemaElement{}
+			f.Groups[i](se)
+			if isList(se) {
+				out = append(out, segment{name: f.Path[i], types: []sch.FieldRepetitionType{typ(i), typ(i + 1), typ(i + 2)}, list: true})
+				i += 2
+				continue
+			}
+		}
+
+		rt := typ(i)
+		out = append(out, segment{name: f.Path[i], types: []sch.FieldRepetitionType{rt}, list: rt == sch.FieldRepetitionType_REPEATED})
+	}
+	return out
+}
+
+// Column returns the name (the path in the schema) of the column that col
+// refers to.  col can be the column's name or its name without the
+// repeated groups and elements of its lists (like "friends.name" for
+// "friends.list.element.name"), so a column is named the same way no
+// matter how its lists are written.  It returns false if there isn't a
+// column named col.
+func (m *Metadata) Column(col string) (string, bool) {
+	if _, ok := m.schema.lookup[col]; ok {
+		return col, true
+	}
+
+	for _, f := range m.schema.fields {
+		if segmentsName(fieldSegments(f)) == col {
+			return strings.Join(f.Path, "."), true
+		}
+	}
+	return "", false
+}
+
+// schemaNode is a SchemaElement along with its children.
+type schemaNode struct {
+	*sch.SchemaElement
+	children []*schemaNode
+}
+
+func schemaTree(elems []*sch.SchemaElement) *schemaNode {
+	var i int
+	var build func() *schemaNode
+	build = func() *schemaNode {
+		n := &schemaNode{SchemaElement: elems[i]}
+		i++
+		for j := int32(0); j < n.GetNumChildren() && i < len(elems); j++ {
+			n.children = append(n.children, build())
+		}
+		return n
+	}
+	return build()
+}
+
+// listNodes returns the LIST group, the repeated group and the element
+// of list n, or just the LIST group and the repeated group if the
+// repeated group is the element (one of the legacy layouts).  It
+// returns nil if n isn't a list.
+func (n *schemaNode) listNodes() []*schemaNode {
+	if !isList(n.SchemaElement) || len(n.children) != 1 || n.children[0].GetRepetitionType() != sch.FieldRepetitionType_REPEATED {
+		return nil
+	}
+
+	r := n.children[0]
+	if len(r.children) != 1 || r.Name == "array" || r.Name == n.Name+"_tuple" {
+		return []*schemaNode{n, r}
+	}
+	return []*schemaNode{n, r, r.children[0]}
+}
+
+// columns adds the segments of each of the columns under n to out and
+// the column's SchemaElement to elems.
+func (n *schemaNode) columns(pth []string, segs []segment, out map[string][]segment, elems map[string]*sch.SchemaElement) {
+	for _, ch := range n.children {
+		nodes := ch.listNodes()
+		s := segment{name: ch.Name, list: nodes != nil}
+		if nodes == nil {
+			nodes = []*schemaNode{ch}
+			s.list = ch.GetRepetitionType() == sch.FieldRepetitionType_REPEATED
+		}
+
+		p := pth[:len(pth):len(pth)]
+		for _, nd := range nodes {
+			p = append(p, nd.Name)
+			s.types = append(s.types, nd.GetRepetitionType())
+		}
+
+		ss := append(segs[:len(segs):len(segs)], s)
+		last := nodes[len(nodes)-1]
+		if len(last.children) == 0 {
+			out[strings.Join(p, ".")] = ss
+			elems[strings.Join(p, ".")] = last.SchemaElement
+			continue
+		}
+		last.columns(p, ss, out, elems)
+	}
+}
+
+// columnLevels holds the max levels of a column whose lists have
+// optional groups that its field's lists don't, along with the
+// field's definition level for each of the column's definition levels
+// (-1 for a null element, which a slice can't hold).
+type columnLevels struct {
+	max  MaxLevel
+	defs []int
+}
+
+// levelMap returns the levels of a column with the segments col that is
+// read by a field with the segments fld.  It returns false if they
+// differ by more than the optional groups of their lists.
+func levelMap(col, fld []segment) (*columnLevels, bool) {
+	l := &columnLevels{defs: []int{0}}
+	var def int
+	for i, c := range col {
+		f := fld[i]
+		if !c.list || !f.list {
+			if !equalTypes(c.types, f.types) {
+				return nil, false
+			}
+			for _, t := range c.types {
+				if t != sch.FieldRepetitionType_REQUIRED {
+					def++
+					l.defs = append(l.defs, def)
+				}
+			}
+			continue
+		}
+
+		k := repeatedIndex(c.types)
+		if k < 0 || repeatedIndex(f.types) < 0 {
+			return nil, false
+		}
+		for j, t := range f.types {
+			if t != sch.FieldRepetitionType_REQUIRED && j != repeatedIndex(f.types) {
+				return nil, false
+			}
+		}
+
+		for j, t := range c.types {
+			switch {
+			case t == sch.FieldRepetitionType_REQUIRED:
+			case j < k:
+				// a null list is read as an empty one
+				l.defs = append(l.defs, def)
+			case j == k:
+				def++
+				if j < len(c.types)-1 && c.types[j+1] == sch.FieldRepetitionType_OPTIONAL {
+					l.defs = append(l.defs, -1)
+				} else {
+					l.defs = append(l.defs, def)
+				}
+			default:
+				l.defs = append(l.defs, def)
+			}
+		}
+	}
+
+	for _, t := range segmentTypes(col) {
+		if t != sch.FieldRepetitionType_REQUIRED {
+			l.max.Def++
+		}
+		if t == sch.FieldRepetitionType_REPEATED {
+			l.max.Rep++
+		}
+	}
+	return l, true
+}
+
+// same returns true if the column's definition levels are its field's,
+// like those of a list written in the legacy two-level layout.
+func (l *columnLevels) same() bool {
+	for i, d := range l.defs {
+		if d != i {
+			return false
+		}
+	}
+	return true
+}
+
+func repeatedIndex(types []sch.FieldRepetitionType) int {
+	out := -1
+	for i, t := range types {
+		if t == sch.FieldRepetitionType_REPEATED {
+			if out >= 0 {
+				return -1
+			}
+			out = i
+		}
+	}
+	return out
+}
+
+func equalTypes(a, b []sch.FieldRepetitionType) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// matchColumns matches the columns of the file whose footer was read
+// to the fields, since a list can be written in the standard layout or
+// in one of the legacy layouts.  A column whose path isn't its field's
+// is renamed to the field's name, and the definition levels of a column
+// whose lists have optional groups that its field's lists don't are
+// translated as they are read (see Page.fieldDefs).
+func (m *Metadata) matchColumns() {
+	m.levels = map[string]*columnLevels{}
+	m.elements = map[string]*sch.SchemaElement{}
+	if len(m.metadata.Schema) == 0 {
+		return
+	}
+
+	fields := map[string][]segment{}
+	paths := map[string][]string{}
+	for _, f := range m.schema.fields {
+		segs := fieldSegments(f)
+		fields[segmentsName(segs)] = segs
+		paths[segmentsName(segs)] = f.Path
+	}
+
+	cols := map[string][]segment{}
+	elems := map[string]*sch.SchemaElement{}
+	schemaTree(m.metadata.Schema).columns(nil, nil, cols, elems)
+
+	renamed := map[string][]string{}
+	for name, col := range cols {
+		fld, ok := fields[segmentsName(col)]
+		if !ok {
+			m.elements[name] = elems[name]
+			continue
+		}
+
+		pth := paths[segmentsName(col)]
+		if strings.Join(pth, ".") != name {
+			renamed[name] = pth
+		}
+		m.elements[strings.Join(pth, ".")] = elems[name]
+
+		if equalTypes(segmentTypes(col), segmentTypes(fld)) {
+			continue
+		}
+		if l, ok := levelMap(col, fld); ok && !l.same() {
+			m.levels[strings.Join(pth, ".")] = l
+		}
+	}
+
+	for _, rg := range m.metadata.RowGroups {
+		for _, ch := range rg.Columns {
+			if pth, ok := renamed[strings.Join(ch.MetaData.PathInSchema, ".")]; ok {
+				ch.MetaData.PathInSchema = pth
+			}
+		}
+	}
+}
+
+// maxLevels returns the max levels of the page's column in the file,
+// which are max (the field's max levels) unless the column's lists have
+// optional groups that the field's don't.
+func (pg Page) maxLevels(max MaxLevel) MaxLevel {
+	if pg.levels == nil {
+		return max
+	}
+	return pg.levels.max
+}
+
+// fieldDefs translates the definition levels of a page of the column
+// to the definition levels of the field that reads it.
+func (pg Page) fieldDefs(defs []uint8) error {
+	if pg.levels == nil {
+		return nil
+	}
+
+	for i, d := range defs {
+		if int(d) >= len(pg.levels.defs) || pg.levels.defs[d] < 0 {
+			return fmt.Errorf("column %s has a null list element, which can't be read into a slice", strings.Join(pg.chunk.MetaData.PathInSchema, "."))
+		}
+		defs[i] = uint8(pg.levels.defs[d])
+	}
+	return nil
+}
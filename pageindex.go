@@ -0,0 +1,227 @@
+package parquet
+
+import (
+	"context"
+	"io"
+	"strings"
+
+	"github.com/apache/thrift/lib/go/thrift"
+	sch "github.com/parsyl/parquet/schema"
+)
+
+// pageLocation keeps track of a data page of a column chunk so that the
+// ColumnIndex and OffsetIndex of the column chunk can be written
+// along with the footer.
+type pageLocation struct {
+	// size is the size of the page, including its header.
+	size  int64
+	count int
+	rows  int
+	stats *sch.Statistics
+}
+
+func (r *RowGroup) addPage(pth []string, size int64, count, rows int, stats *sch.Statistics) {
+	col := strings.Join(pth, ".")
+	r.pages[col] = append(r.pages[col], pageLocation{
+		size:  size,
+		count: count,
+		rows:  rows,
+		stats: stats,
+	})
+}
+
+// writePageIndexes writes the ColumnIndex of each column chunk followed
+// by the OffsetIndex of each column chunk.  pos is the position in the
+// file where the page indexes start.  It sets the offsets and lengths of
+// the indexes in the column chunks.
+func (m *Metadata) writePageIndexes(w io.Writer, pos int64, chunks []*sch.ColumnChunk, pages [][]pageLocation) error {
+	ts := thrift.NewTSerializer()
+	ts.Protocol = boolsProtocol{thrift.NewTCompactProtocolFactory().GetProtocol(ts.Transport)}
+
+	for i, ch := range chunks {
+		name := strings.Join(ch.MetaData.PathInSchema, ".")
+		ci := columnIndex(m.schema.lookup[name], pages[i])
+		if ci == nil {
+			continue
+		}
+
+		n, err := writeThrift(w, ts, ci)
+		if err != nil {
+			return err
+		}
+
+		off, l := pos, int32(n)
+		ch.ColumnIndexOffset = &off
+		ch.ColumnIndexLength = &l
+		pos += int64(n)
+	}
+
+	for i, ch := range chunks {
+		n, err := writeThrift(w, m.ts, offsetIndex(ch, pages[i]))
+		if err != nil {
+			return err
+		}
+
+		off, l := pos, int32(n)
+		ch.OffsetIndexOffset = &off
+		ch.OffsetIndexLength = &l
+		pos += int64(n)
+	}
+
+	return nil
+}
+
+func writeThrift(w io.Writer, ts *thrift.TSerializer, s thrift.TStruct) (int, error) {
+	buf, err := ts.Write(context.TODO(), s)
+	if err != nil {
+		return 0, err
+	}
+	return w.Write(buf)
+}
+
+// boolsProtocol writes the elements of a list of bools (like the
+// null_pages of a ColumnIndex) as 1 and 0.  The compact protocol writes
+// false as 2, which some readers (like parquet-go) read as true.  It must
+// only be used for structs that don't have any bool fields.
+type boolsProtocol struct {
+	thrift.TProtocol
+}
+
+func (p boolsProtocol) WriteBool(ctx context.Context, b bool) error {
+	if b {
+		return p.WriteByte(ctx, 1)
+	}
+	return p.WriteByte(ctx, 0)
+}
+
+// offsetIndex returns the location of each data page of the column
+// chunk, along with the index (within the row group) of the page's
+// first row.
+func offsetIndex(ch *sch.ColumnChunk, pages []pageLocation) *sch.OffsetIndex {
+	oi := &sch.OffsetIndex{PageLocations: make([]*sch.PageLocation, len(pages))}
+	off := ch.MetaData.DataPageOffset
+	var row int64
+	for i, p := range pages {
+		oi.PageLocations[i] = &sch.PageLocation{
+			Offset:             off,
+			CompressedPageSize: int32(p.size),
+			FirstRowIndex:      row,
+		}
+		off += p.size
+		row += int64(p.rows)
+	}
+	return oi
+}
+
+// columnIndex returns the min and max values and the null count of each
+// data page of the column chunk.  It returns nil if a page that has
+// non-null values is missing its min or max (like the pages of a boolean
+// column), since a ColumnIndex can't be written for the column chunk.
+func columnIndex(se sch.SchemaElement, pages []pageLocation) *sch.ColumnIndex {
+	ci := &sch.ColumnIndex{
+		NullPages:  make([]bool, len(pages)),
+		MinValues:  make([][]byte, len(pages)),
+		MaxValues:  make([][]byte, len(pages)),
+		NullCounts: make([]int64, len(pages)),
+	}
+
+	nullCounts := true
+	for i, p := range pages {
+		if p.stats == nil {
+			return nil
+		}
+
+		if p.stats.NullCount == nil {
+			nullCounts = false
+		} else {
+			ci.NullCounts[i] = *p.stats.NullCount
+		}
+
+		if p.stats.MinValue == nil && p.stats.MaxValue == nil && p.stats.NullCount != nil && *p.stats.NullCount == int64(p.count) {
+			ci.NullPages[i] = true
+			ci.MinValues[i] = []byte{}
+			ci.MaxValues[i] = []byte{}
+			continue
+		}
+
+		if p.stats.MinValue == nil || p.stats.MaxValue == nil {
+			return nil
+		}
+
+		ci.MinValues[i] = p.stats.MinValue
+		ci.MaxValues[i] = p.stats.MaxValue
+	}
+
+	if !nullCounts {
+		ci.NullCounts = nil
+	}
+
+	ci.BoundaryOrder = boundaryOrder(se, ci)
+	return ci
+}
+
+// boundaryOrder returns ASCENDING if the min and max values of the
+// non-null pages never decrease from one page to the next, DESCENDING if
+// they never increase, and UNORDERED otherwise.
+func boundaryOrder(se sch.SchemaElement, ci *sch.ColumnIndex) sch.BoundaryOrder {
+	asc, desc := true, true
+	prev := -1
+	for i, null := range ci.NullPages {
+		if null {
+			continue
+		}
+
+		if prev >= 0 {
+			mn := compare(se, ci.MinValues[prev], ci.MinValues[i])
+			mx := compare(se, ci.MaxValues[prev], ci.MaxValues[i])
+			if mn > 0 || mx > 0 {
+				asc = false
+			}
+			if mn < 0 || mx < 0 {
+				desc = false
+			}
+		}
+		prev = i
+	}
+
+	switch {
+	case asc:
+		return sch.BoundaryOrder_ASCENDING
+	case desc:
+		return sch.BoundaryOrder_DESCENDING
+	default:
+		return sch.BoundaryOrder_UNORDERED
+	}
+}
+
+// ReadColumnIndex reads the ColumnIndex of a column chunk.  It returns
+// nil if the column chunk doesn't have one.
+func ReadColumnIndex(r io.ReadSeeker, ch *sch.ColumnChunk) (*sch.ColumnIndex, error) {
+	if ch.ColumnIndexOffset == nil {
+		return nil, nil
+	}
+
+	if _, err := r.Seek(*ch.ColumnIndexOffset, io.SeekStart); err != nil {
+		return nil, err
+	}
+
+	ci := sch.NewColumnIndex()
+	p := thrift.NewTCompactProtocol(&thrift.StreamTransport{Reader: r})
+	return ci, ci.Read(context.TODO(), p)
+}
+
+// ReadOffsetIndex reads the OffsetIndex of a column chunk.  It returns
+// nil if the column chunk doesn't have one.
+func ReadOffsetIndex(r io.ReadSeeker, ch *sch.ColumnChunk) (*sch.OffsetIndex, error) {
+	if ch.OffsetIndexOffset == nil {
+		return nil, nil
+	}
+
+	if _, err := r.Seek(*ch.OffsetIndexOffset, io.SeekStart); err != nil {
+		return nil, err
+	}
+
+	oi := sch.NewOffsetIndex()
+	p := thrift.NewTCompactProtocol(&thrift.StreamTransport{Reader: r})
+	return oi, oi.Read(context.TODO(), p)
+}
@@ -10,11 +10,19 @@ import (
 	"io"
 	"io/ioutil"
 	"strings"
+	"sync"
 
 	"github.com/apache/thrift/lib/go/thrift"
 	sch "github.com/parsyl/parquet/schema"
 )
 
+// Version is the version of the code that parquetgen generates.
+const Version = "0.1.0"
+
+// CreatedBy is written to the created_by field of the files that the
+// generated code writes.
+const CreatedBy = "parquet-go-gen version " + Version
+
 // Field holds the type information for a parquet column
 type Field struct {
 	Name           string
@@ -22,6 +30,10 @@ type Field struct {
 	Types          []int
 	Type           FieldFunc
 	RepetitionType FieldFunc
+	// Groups holds the FieldFuncs of the groups in Path that have a
+	// logical type (like MapType), where a nil FieldFunc (or a missing
+	// one at the end) is a plain group.
+	Groups []FieldFunc
 }
 
 // Page keeps track of metadata for each ColumnChunk
@@ -31,6 +43,25 @@ type Page struct {
 	Size   int
 	Offset int64
 	Codec  sch.CompressionCodec
+	Type   sch.Type
+	// TypeLength is the length of the values of a
+	// FIXED_LEN_BYTE_ARRAY column.
+	TypeLength int
+
+	// rows are the rows of the ColumnChunk to read when the reader has
+	// a filter or has seeked to a row (nil means all of them), and
+	// locations are the locations of the ColumnChunk's data pages if it
+	// has an OffsetIndex.
+	rows      rowRanges
+	locations []*sch.PageLocation
+
+	chunk   *sch.ColumnChunk
+	numRows int64
+	// levels are set if the column's lists have optional groups that
+	// its field's lists don't (see Metadata.matchColumns), and element
+	// is the column's SchemaElement in the file.
+	levels  *columnLevels
+	element *sch.SchemaElement
 }
 
 type schema struct {
@@ -44,32 +75,33 @@ func (s schema) schema() (int64, []*sch.SchemaElement) {
 		Name: "root",
 	})
 
-	var children int32
 	var z int32
-	m := map[string]*sch.SchemaElement{}
+	out[0].NumChildren = new(int32)
+	groups := map[string]*sch.SchemaElement{}
 	for _, f := range s.fields {
-		if len(f.Path) > 1 {
-			for i, name := range f.Path[:len(f.Path)-1] {
-				par, ok := m[name]
-				if !ok {
-					children++
-					parts := strings.Split(name, ".")
-					rt := sch.FieldRepetitionType(f.Types[i])
-					par = &sch.SchemaElement{
-						Name:           parts[len(parts)-1],
-						RepetitionType: &rt,
-						NumChildren:    &z,
-					}
-					out = append(out, par)
+		// the fields are in depth first order, so a group's
+		// columns come right after the group
+		par := out[0]
+		for i, name := range f.Path[:len(f.Path)-1] {
+			k := strings.Join(f.Path[:i+1], ".")
+			g, ok := groups[k]
+			if !ok {
+				rt := sch.FieldRepetitionType(f.Types[i])
+				g = &sch.SchemaElement{
+					Name:           name,
+					RepetitionType: &rt,
+					NumChildren:    new(int32),
 				}
-				n := *par.NumChildren
-				n++
-				par.NumChildren = &n
-				m[name] = par
+				if i < len(f.Groups) && f.Groups[i] != nil {
+					f.Groups[i](g)
+				}
+				*par.NumChildren++
+				groups[k] = g
+				out = append(out, g)
 			}
-		} else if len(f.Path) == 1 {
-			children++
+			par = g
 		}
+		*par.NumChildren++
 
 		se := &sch.SchemaElement{
 			Name:       f.Path[len(f.Path)-1],
@@ -84,7 +116,6 @@ func (s schema) schema() (int64, []*sch.SchemaElement) {
 		out = append(out, se)
 	}
 
-	out[0].NumChildren = &children
 	return int64(len(s.fields)), out
 }
 
@@ -92,14 +123,38 @@ func (s schema) schema() (int64, []*sch.SchemaElement) {
 // be kept track of in order to write the FileMetaData
 // at the end of the parquet file.
 type Metadata struct {
+	// mu guards ts and the current row group, since the column chunks
+	// of a row group can be written concurrently.
+	mu           sync.Mutex
 	ts           *thrift.TSerializer
 	schema       schema
 	docs         int64
 	pageDocs     int64
 	rowGroupDocs int64
 	rowGroups    []RowGroup
+	dataPageV2   bool
+	keyValues    []*sch.KeyValue
+
+	// offset is the number of bytes that have been written to the file
+	// (see SetOffset), or 0 if the writer doesn't keep track of it.
+	offset int64
 
 	metadata *sch.FileMetaData
+	// levels holds the levels of the file's columns that are read
+	// by fields with different max levels and elements holds the
+	// SchemaElement in the file of each column (see matchColumns).
+	levels   map[string]*columnLevels
+	elements map[string]*sch.SchemaElement
+
+	// selected holds the rows of each row group that match the reader's
+	// filters and locations holds the data page locations of each row
+	// group's filtered column chunks.  Both are nil if the reader doesn't
+	// have a filter.
+	selected  []rowRanges
+	locations []map[string][]*sch.PageLocation
+
+	// projection is nil if the reader reads all of the columns.
+	projection *projection
 }
 
 // Stats is passed in by each column's call to DoWrite
@@ -124,12 +179,53 @@ func New(fields ...Field) *Metadata {
 	return m
 }
 
+// DataPageV2 makes the fields write DATA_PAGE_V2 pages instead
+// of DATA_PAGE pages.
+func (m *Metadata) DataPageV2() {
+	m.dataPageV2 = true
+}
+
+// SetKeyValue adds key and value to the key/value metadata that Footer
+// writes, replacing the value of key if it was already set.
+func (m *Metadata) SetKeyValue(key, value string) {
+	for _, kv := range m.keyValues {
+		if kv.Key == key {
+			kv.Value = &value
+			return
+		}
+	}
+	m.keyValues = append(m.keyValues, &sch.KeyValue{Key: key, Value: &value})
+}
+
+// KeyValueMetadata returns the key/value metadata of the file whose footer
+// was read by ReadFooter.
+func (m *Metadata) KeyValueMetadata() map[string]string {
+	out := make(map[string]string, len(m.metadata.KeyValueMetadata))
+	for _, kv := range m.metadata.KeyValueMetadata {
+		out[kv.Key] = kv.GetValue()
+	}
+	return out
+}
+
+// SetOffset is called with the number of bytes that have been written
+// to the file before the column chunks of the current row group are
+// written, and before the footer is written.  Footer uses the offsets
+// for the positions of the column chunks and page indexes, since a row
+// group without any rows isn't in the footer but its bytes might still
+// be in the file.
+func (m *Metadata) SetOffset(offset int64) {
+	m.offset = offset
+	m.rowGroups[len(m.rowGroups)-1].offset = offset
+}
+
 // StartRowGroup is called when starting a new row group
 func (m *Metadata) StartRowGroup(fields ...Field) {
 	m.rowGroupDocs = 0
 	m.rowGroups = append(m.rowGroups, RowGroup{
 		fields:  schemaElements(fields),
 		columns: make(map[string]sch.ColumnChunk),
+		dicts:   make(map[string]*dictionary),
+		pages:   make(map[string][]pageLocation),
 	})
 }
 
@@ -144,25 +240,34 @@ func (m *Metadata) NextDoc() {
 
 // RowGroups returns a summary of each schema.RowGroup
 func (m *Metadata) RowGroups() []RowGroup {
-	rgs := make([]RowGroup, len(m.metadata.RowGroups))
+	rgs := make([]RowGroup, 0, len(m.metadata.RowGroups))
 	for i, rg := range m.metadata.RowGroups {
-		rgs[i] = RowGroup{
-			rowGroup: *rg,
-			Rows:     rg.NumRows,
+		rows := rg.NumRows
+		if m.selected != nil {
+			if len(m.selected[i]) == 0 {
+				continue
+			}
+			rows = m.selected[i].count()
 		}
+
+		rgs = append(rgs, RowGroup{
+			rowGroup: *rg,
+			Rows:     rows,
+		})
 	}
 	return rgs
 }
 
 // WritePageHeader is called in order to finish writing to a column chunk.
-func (m *Metadata) WritePageHeader(w io.Writer, pth []string, dataLen, compressedLen, defCount, count int, defLen, repLen int64, comp sch.CompressionCodec, stats Stats) error {
+// rows is the number of rows (records) that the page's values belong to.
+func (m *Metadata) WritePageHeader(w io.Writer, pth []string, dataLen, compressedLen, defCount, count, rows int, defLen, repLen int64, comp sch.CompressionCodec, enc sch.Encoding, stats Stats) error {
 	ph := &sch.PageHeader{
 		Type:                 sch.PageType_DATA_PAGE,
 		UncompressedPageSize: int32(dataLen),
 		CompressedPageSize:   int32(compressedLen),
 		DataPageHeader: &sch.DataPageHeader{
 			NumValues:               int32(count),
-			Encoding:                sch.Encoding_PLAIN,
+			Encoding:                enc,
 			DefinitionLevelEncoding: sch.Encoding_RLE,
 			RepetitionLevelEncoding: sch.Encoding_RLE,
 			Statistics: &sch.Statistics{
@@ -174,6 +279,40 @@ func (m *Metadata) WritePageHeader(w io.Writer, pth []string, dataLen, compresse
 		},
 	}
 
+	return m.writePageHeader(w, pth, ph, count, rows, defLen, repLen, comp, enc)
+}
+
+// WritePageHeaderV2 is the DATA_PAGE_V2 version of WritePageHeader.  The
+// levels of a DATA_PAGE_V2 page are not compressed, so dataLen and
+// compressedLen include defLen and repLen.
+func (m *Metadata) WritePageHeaderV2(w io.Writer, pth []string, dataLen, compressedLen, count, nulls, rows int, defLen, repLen int64, comp sch.CompressionCodec, enc sch.Encoding, stats Stats) error {
+	ph := &sch.PageHeader{
+		Type:                 sch.PageType_DATA_PAGE_V2,
+		UncompressedPageSize: int32(dataLen),
+		CompressedPageSize:   int32(compressedLen),
+		DataPageHeaderV2: &sch.DataPageHeaderV2{
+			NumValues:                  int32(count),
+			NumNulls:                   int32(nulls),
+			NumRows:                    int32(rows),
+			Encoding:                   enc,
+			DefinitionLevelsByteLength: int32(defLen),
+			RepetitionLevelsByteLength: int32(repLen),
+			IsCompressed:               comp != sch.CompressionCodec_UNCOMPRESSED,
+			Statistics: &sch.Statistics{
+				NullCount:     stats.NullCount(),
+				DistinctCount: stats.DistinctCount(),
+				MinValue:      stats.Min(),
+				MaxValue:      stats.Max(),
+			},
+		},
+	}
+
+	return m.writePageHeader(w, pth, ph, count, rows, defLen, repLen, comp, enc)
+}
+
+func (m *Metadata) writePageHeader(w io.Writer, pth []string, ph *sch.PageHeader, count, rows int, defLen, repLen int64, comp sch.CompressionCodec, enc sch.Encoding) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	m.pageDocs = 0
 
 	buf, err := m.ts.Write(context.TODO(), ph)
@@ -181,15 +320,146 @@ func (m *Metadata) WritePageHeader(w io.Writer, pth []string, dataLen, compresse
 		return err
 	}
 
-	if err := m.updateRowGroup(pth, dataLen, compressedLen, len(buf), count, comp); err != nil {
+	encs := []sch.Encoding{enc}
+	if defLen > 0 || repLen > 0 {
+		encs = append(encs, sch.Encoding_RLE)
+	}
+
+	var stats *sch.Statistics
+	if ph.DataPageHeader != nil {
+		stats = ph.DataPageHeader.Statistics
+	} else if ph.DataPageHeaderV2 != nil {
+		stats = ph.DataPageHeaderV2.Statistics
+	}
+
+	if err := m.updateRowGroup(pth, int(ph.UncompressedPageSize), int(ph.CompressedPageSize), len(buf), count, comp, stats, encs...); err != nil {
 		return err
 	}
 
+	m.rowGroups[len(m.rowGroups)-1].addPage(pth, int64(len(buf))+int64(ph.CompressedPageSize), count, rows, stats)
+
 	_, err = w.Write(buf)
 	return err
 }
 
-func (m *Metadata) updateRowGroup(pth []string, dataLen, compressedLen, headerLen, count int, comp sch.CompressionCodec) error {
+// dictionary returns the dictionary of the current row group's column
+// chunk at pth.  It returns nil if the column's type can't be dictionary
+// encoded.
+func (m *Metadata) dictionary(pth []string, comp sch.CompressionCodec, level int) (*dictionary, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	i := len(m.rowGroups)
+	if i == 0 {
+		return nil, fmt.Errorf("no row groups, you must call StartRowGroup at least once")
+	}
+
+	col := strings.Join(pth, ".")
+	rg := m.rowGroups[i-1]
+	d, ok := rg.dicts[col]
+	if ok {
+		return d, nil
+	}
+
+	t, err := columnType(col, m.schema)
+	if err != nil {
+		return nil, err
+	}
+
+	if t == sch.Type_BOOLEAN {
+		return nil, nil
+	}
+
+	d = newDictionary(t, typeLength(m.schema.lookup[col]), comp, level)
+	rg.dicts[col] = d
+	return d, nil
+}
+
+// FlushColumn is called after all the pages of a column chunk have been
+// written.  If the column chunk is dictionary encoded it writes the
+// dictionary page followed by the data pages that were buffered while
+// the dictionary was being built.
+func (m *Metadata) FlushColumn(w io.Writer, col string) error {
+	i := len(m.rowGroups)
+	if i == 0 {
+		return fmt.Errorf("no row groups, you must call StartRowGroup at least once")
+	}
+
+	m.mu.Lock()
+	d, ok := m.rowGroups[i-1].dicts[col]
+	m.mu.Unlock()
+	if !ok {
+		return nil
+	}
+
+	if d.n > 0 {
+		if err := m.writeDictionaryPage(w, strings.Split(col, "."), d); err != nil {
+			return err
+		}
+	}
+
+	_, err := d.pages.WriteTo(w)
+	return err
+}
+
+// DictionaryBytes returns the number of bytes that the dictionaries of
+// the current row group hold, including the pages that are waiting for
+// FlushColumn to write their dictionary page.
+func (m *Metadata) DictionaryBytes() int {
+	i := len(m.rowGroups)
+	if i == 0 {
+		return 0
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var n int
+	for _, d := range m.rowGroups[i-1].dicts {
+		n += len(d.vals) + d.pages.Len()
+	}
+	return n
+}
+
+func (m *Metadata) writeDictionaryPage(w io.Writer, pth []string, d *dictionary) error {
+	buf := buffpool.Get()
+	defer buffpool.Put(buf)
+
+	l, cl, vals, err := compress(d.codec, d.level, buf, d.vals)
+	if err != nil {
+		return err
+	}
+
+	ph := &sch.PageHeader{
+		Type:                 sch.PageType_DICTIONARY_PAGE,
+		UncompressedPageSize: int32(l),
+		CompressedPageSize:   int32(cl),
+		DictionaryPageHeader: &sch.DictionaryPageHeader{
+			NumValues: int32(d.n),
+			Encoding:  sch.Encoding_PLAIN,
+		},
+	}
+
+	m.mu.Lock()
+	hdr, err := m.ts.Write(context.TODO(), ph)
+	if err == nil {
+		err = m.updateRowGroup(pth, l, cl, len(hdr), 0, d.codec, nil, sch.Encoding_PLAIN)
+	}
+	m.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	d.size = int64(len(hdr) + cl)
+	if _, err := w.Write(hdr); err != nil {
+		return err
+	}
+
+	_, err = w.Write(vals)
+	return err
+}
+
+func (m *Metadata) updateRowGroup(pth []string, dataLen, compressedLen, headerLen, count int, comp sch.CompressionCodec, stats *sch.Statistics, encs ...sch.Encoding) error {
 	i := len(m.rowGroups)
 	if i == 0 {
 		return fmt.Errorf("no row groups, you must call StartRowGroup at least once")
@@ -198,11 +468,20 @@ func (m *Metadata) updateRowGroup(pth []string, dataLen, compressedLen, headerLe
 	rg := m.rowGroups[i-1]
 
 	rg.rowGroup.NumRows = m.rowGroupDocs
-	err := rg.updateColumnChunk(pth, dataLen+headerLen, compressedLen+headerLen, count, m.schema, comp)
+	err := rg.updateColumnChunk(pth, dataLen+headerLen, compressedLen+headerLen, count, m.schema, comp, stats, encs)
 	m.rowGroups[i-1] = rg
 	return err
 }
 
+// typeLength returns the TypeLength of se, which is only
+// set for FIXED_LEN_BYTE_ARRAY columns.
+func typeLength(se sch.SchemaElement) int {
+	if se.TypeLength == nil {
+		return 0
+	}
+	return int(*se.TypeLength)
+}
+
 func columnType(col string, fields schema) (sch.Type, error) {
 	f, ok := fields.lookup[col]
 	if !ok {
@@ -212,44 +491,94 @@ func columnType(col string, fields schema) (sch.Type, error) {
 }
 
 // Rows return the total number of rows that are being written
-// in to a parquet file.
+// in to a parquet file (or the number of rows that match the
+// filters when reading).
 func (m *Metadata) Rows() int64 {
-	return m.metadata.NumRows
+	if m.selected == nil {
+		return m.metadata.NumRows
+	}
+
+	var n int64
+	for _, sel := range m.selected {
+		n += sel.count()
+	}
+	return n
 }
 
-// Footer writes the FileMetaData at the end of the file.
+// Footer writes the ColumnIndex and OffsetIndex of each column chunk
+// followed by the FileMetaData at the end of the file.
 func (m *Metadata) Footer(w io.Writer) error {
 	_, s := m.schema.schema()
+	createdBy := CreatedBy
 	fmd := &sch.FileMetaData{
-		Version:   1,
-		Schema:    s,
-		NumRows:   m.docs,
-		RowGroups: make([]*sch.RowGroup, 0, len(m.rowGroups)),
+		Version:          1,
+		Schema:           s,
+		NumRows:          m.docs,
+		RowGroups:        make([]*sch.RowGroup, 0, len(m.rowGroups)),
+		KeyValueMetadata: m.keyValues,
+		CreatedBy:        &createdBy,
+		ColumnOrders:     make([]*sch.ColumnOrder, len(m.schema.fields)),
+	}
+
+	// the min and max statistics are always written using the column's
+	// type defined order.
+	for i := range fmd.ColumnOrders {
+		fmd.ColumnOrders[i] = &sch.ColumnOrder{TYPE_ORDER: sch.NewTypeDefinedOrder()}
 	}
 
 	pos := int64(4)
+	var chunks []*sch.ColumnChunk
+	var pages [][]pageLocation
 	for _, mrg := range m.rowGroups {
 		rg := mrg.rowGroup
 		if rg.NumRows == 0 {
 			continue
 		}
 
+		// readers (like parquet-go) use the ordinal to find the row
+		// group's page indexes.
+		if m.offset > 0 {
+			pos = mrg.offset
+		}
+
+		start, ordinal := pos, int16(len(fmd.RowGroups))
+		rg.FileOffset = &start
+		rg.Ordinal = &ordinal
+
 		for _, col := range mrg.fields.fields {
-			ch, ok := mrg.columns[strings.Join(col.Path, ".")]
+			name := strings.Join(col.Path, ".")
+			ch, ok := mrg.columns[name]
 			if !ok {
 				continue
 			}
 
 			ch.FileOffset = pos
 			ch.MetaData.DataPageOffset = pos
-			rg.TotalByteSize += ch.MetaData.TotalCompressedSize
+			if d, ok := mrg.dicts[name]; ok && d.size > 0 {
+				off := pos
+				ch.MetaData.DictionaryPageOffset = &off
+				ch.MetaData.DataPageOffset = pos + d.size
+			}
+			rg.TotalByteSize += ch.MetaData.TotalUncompressedSize
 			rg.Columns = append(rg.Columns, &ch)
 			pos += ch.MetaData.TotalCompressedSize
+			chunks = append(chunks, &ch)
+			pages = append(pages, mrg.pages[name])
 		}
 
+		size := pos - start
+		rg.TotalCompressedSize = &size
 		fmd.RowGroups = append(fmd.RowGroups, &rg)
 	}
 
+	if m.offset > 0 {
+		pos = m.offset
+	}
+
+	if err := m.writePageIndexes(w, pos, chunks, pages); err != nil {
+		return err
+	}
+
 	buf, err := m.ts.Write(context.TODO(), fmd)
 	if err != nil {
 		return err
@@ -270,7 +599,12 @@ type RowGroup struct {
 	fields   schema
 	rowGroup sch.RowGroup
 	columns  map[string]sch.ColumnChunk
+	dicts    map[string]*dictionary
+	pages    map[string][]pageLocation
 	child    *RowGroup
+	// offset is the position in the file of the row group's first
+	// column chunk (see Metadata.SetOffset).
+	offset int64
 
 	Rows int64
 }
@@ -280,7 +614,7 @@ func (r *RowGroup) Columns() []*sch.ColumnChunk {
 	return r.rowGroup.Columns
 }
 
-func (r *RowGroup) updateColumnChunk(pth []string, dataLen, compressedLen, count int, fields schema, comp sch.CompressionCodec) error {
+func (r *RowGroup) updateColumnChunk(pth []string, dataLen, compressedLen, count int, fields schema, comp sch.CompressionCodec, stats *sch.Statistics, encs []sch.Encoding) error {
 	col := strings.Join(pth, ".")
 
 	ch, ok := r.columns[col]
@@ -293,20 +627,35 @@ func (r *RowGroup) updateColumnChunk(pth []string, dataLen, compressedLen, count
 		ch = sch.ColumnChunk{
 			MetaData: &sch.ColumnMetaData{
 				Type:         t,
-				Encodings:    []sch.Encoding{sch.Encoding_PLAIN},
 				PathInSchema: pth,
 				Codec:        comp,
 			},
 		}
 	}
 
+	for _, enc := range encs {
+		if !hasEncoding(ch.MetaData.Encodings, enc) {
+			ch.MetaData.Encodings = append(ch.MetaData.Encodings, enc)
+		}
+	}
+
 	ch.MetaData.NumValues += int64(count)
 	ch.MetaData.TotalUncompressedSize += int64(dataLen)
 	ch.MetaData.TotalCompressedSize += int64(compressedLen)
+	ch.MetaData.Statistics = mergeStats(fields.lookup[col], ch.MetaData.Statistics, stats)
 	r.columns[col] = ch
 	return nil
 }
 
+func hasEncoding(encs []sch.Encoding, enc sch.Encoding) bool {
+	for _, e := range encs {
+		if e == enc {
+			return true
+		}
+	}
+	return false
+}
+
 func schemaElements(fields []Field) schema {
 	m := make(map[string]sch.SchemaElement)
 	for _, f := range fields {
@@ -333,27 +682,59 @@ func (m *Metadata) Pages() (map[string][]Page, error) {
 		return nil, nil
 	}
 	out := map[string][]Page{}
-	for _, rg := range m.metadata.RowGroups {
+	for i, rg := range m.metadata.RowGroups {
+		if m.selected != nil && len(m.selected[i]) == 0 {
+			continue
+		}
+
 		for _, ch := range rg.Columns {
 			pth := ch.MetaData.PathInSchema
-			_, ok := m.schema.lookup[strings.Join(pth, ".")]
+			k := strings.Join(pth, ".")
+			_, ok := m.schema.lookup[k]
 			if !ok {
 				return nil, fmt.Errorf("could not find schema for %v", pth)
 			}
 
-			pg := Page{
-				N:      int(ch.MetaData.NumValues),
-				Offset: ch.FileOffset,
-				Size:   int(ch.MetaData.TotalCompressedSize),
-				Codec:  ch.MetaData.Codec,
+			pg := m.page(ch)
+			pg.numRows = rg.NumRows
+			if m.selected != nil {
+				pg.rows = m.selected[i]
+				pg.locations = m.locations[i][k]
+				// the values of required columns are read by
+				// counting them, and each row has one value.
+				if m.maxLevels(k).Def == 0 {
+					pg.N = int(pg.rows.count())
+				}
 			}
-			k := strings.Join(pth, ".")
 			out[k] = append(out[k], pg)
 		}
 	}
 	return out, nil
 }
 
+// page returns the Page of a column chunk.  Its TypeLength is the one in
+// the file, which might not be the one of the field that reads it.
+func (m *Metadata) page(ch *sch.ColumnChunk) Page {
+	pth := strings.Join(ch.MetaData.PathInSchema, ".")
+	se := m.elements[pth]
+	tl := typeLength(m.schema.lookup[pth])
+	if se != nil {
+		tl = typeLength(*se)
+	}
+
+	return Page{
+		N:          int(ch.MetaData.NumValues),
+		Offset:     chunkOffset(ch),
+		Size:       int(ch.MetaData.TotalCompressedSize),
+		Codec:      ch.MetaData.Codec,
+		Type:       ch.MetaData.Type,
+		TypeLength: tl,
+		chunk:      ch,
+		levels:     m.levels[pth],
+		element:    se,
+	}
+}
+
 // ReadMetaData reads the FileMetaData from the end of a parquet file
 func ReadMetaData(r io.ReadSeeker) (*sch.FileMetaData, error) {
 	p := thrift.NewTCompactProtocol(&thrift.StreamTransport{Reader: r})
@@ -371,11 +752,16 @@ func ReadMetaData(r io.ReadSeeker) (*sch.FileMetaData, error) {
 	return m, m.Read(context.TODO(), p)
 }
 
-// ReadFooter reads the parquet metadata
+// ReadFooter reads the parquet metadata and matches the file's columns
+// to the fields, whichever layout the file's lists are written in.
 func (m *Metadata) ReadFooter(r io.ReadSeeker) error {
 	meta, err := ReadMetaData(r)
 	m.metadata = meta
-	return err
+	if err != nil {
+		return err
+	}
+	m.matchColumns()
+	return nil
 }
 
 // PageHeader reads the page header from a column page
@@ -392,7 +778,7 @@ func PageHeaders(footer *sch.FileMetaData, r io.ReadSeeker) ([]sch.PageHeader, e
 	var pageHeaders []sch.PageHeader
 	for _, rg := range footer.RowGroups {
 		for _, col := range rg.Columns {
-			h, err := PageHeadersAtOffset(r, col.MetaData.DataPageOffset, col.MetaData.NumValues)
+			h, err := PageHeadersAtOffset(r, chunkOffset(col), col.MetaData.NumValues)
 			if err != nil {
 				return nil, err
 			}
@@ -432,11 +818,25 @@ func PageHeadersAtOffset(r io.ReadSeeker, o, n int64) ([]sch.PageHeader, error)
 			return nil, fmt.Errorf("unable to seek to next page: %s", err)
 		}
 
-		nRead += int64(ph.DataPageHeader.NumValues)
+		switch {
+		case ph.DataPageHeader != nil:
+			nRead += int64(ph.DataPageHeader.NumValues)
+		case ph.DataPageHeaderV2 != nil:
+			nRead += int64(ph.DataPageHeaderV2.NumValues)
+		}
 	}
 	return out, nil
 }
 
+// chunkOffset returns the offset of the first page of a column chunk,
+// which is the dictionary page if the column chunk has one.
+func chunkOffset(col *sch.ColumnChunk) int64 {
+	if col.MetaData.DictionaryPageOffset != nil {
+		return *col.MetaData.DictionaryPageOffset
+	}
+	return col.MetaData.DataPageOffset
+}
+
 // FieldFunc is used to set some of the metadata for each column
 type FieldFunc func(*sch.SchemaElement)
 
@@ -460,6 +860,14 @@ func RepetitionRepeated(se *sch.SchemaElement) {
 
 var fieldFuncs = []FieldFunc{RepetitionRequired, RepetitionOptional, RepetitionRepeated}
 
+// MapType sets the logical type of a group to MAP.  The group
+// holds a repeated key_value group with a key and a value column.
+func MapType(se *sch.SchemaElement) {
+	ct := sch.ConvertedType_MAP
+	se.ConvertedType = &ct
+	se.LogicalType = &sch.LogicalType{MAP: sch.NewMapType()}
+}
+
 // GetBools reads a byte array and turns each bit into a bool
 func GetBools(r io.Reader, n int, pageSizes []int) ([]bool, error) {
 	var vals [8]bool
@@ -2,15 +2,21 @@ package parquet_test
 
 import (
 	"bytes"
+	"context"
 	"encoding/binary"
 	"fmt"
 	"io"
 	"math"
+	"math/big"
 	"math/rand"
 	"os"
+	"runtime"
+	"sort"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/apache/thrift/lib/go/thrift"
 	"github.com/parsyl/parquet"
 	sch "github.com/parsyl/parquet/schema"
 	"github.com/stretchr/testify/assert"
@@ -21,13 +27,14 @@ import (
 func init() {
 	rand.Seed(time.Now().UnixNano())
 	if os.Getenv("INCLUDE+GZIP") == "true" {
-		compressionCases = append(compressionCases, "gzip")
+		compressionCases = append(compressionCases, "gzip", "zstd", "lz4", "brotli")
 	}
 }
 
 var (
 	letterRunes      = []rune("abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ")
 	compressionCases = []string{"uncompressed", "snappy"}
+	encodingCases    = []string{"plain", "dictionary", "v2", "v2 dictionary"}
 )
 
 func TestParquet(t *testing.T) {
@@ -454,316 +461,2389 @@ func TestParquet(t *testing.T) {
 
 	for i, tc := range testCases {
 		for j, comp := range compressionCases {
+			t.Run(fmt.Sprintf("%02d %s %s", len(compressionCases)*i+j, tc.name, comp), func(t *testing.T) {
+				testParquet(t, tc.input, tc.expected, tc.pageSize, compressionTest[comp])
+			})
+		}
+	}
+}
+
+// TestCodecsAndEncodings writes and reads a few row groups of people,
+// who have every type of column, with each codec and encoding.
+func TestCodecsAndEncodings(t *testing.T) {
+	input := getPeople(25, 60)
+	codecs := []string{"uncompressed", "snappy", "gzip", "zstd", "lz4", "brotli"}
+	for i, comp := range codecs {
+		for j, enc := range encodingCases {
+			t.Run(fmt.Sprintf("%02d %s %s", len(encodingCases)*i+j, comp, enc), func(t *testing.T) {
+				testParquet(t, input, nil, 10, compressionTest[comp], encodingTest[enc])
+			})
+		}
+	}
+}
+
+func testParquet(t *testing.T, input, expected [][]Person, pageSize int, opts ...func(*ParquetWriter) error) {
+	if pageSize == 0 {
+		pageSize = 100
+	}
+	b := writePeople(t, input, append(opts, MaxPageSize(pageSize))...)
+	r, err := NewParquetReader(bytes.NewReader(b))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	if expected == nil {
+		expected = input
+	}
+
+	if !assert.Equal(t, getLen(expected), int(r.Rows())) {
+		return
+	}
+
+	var i int
+	for r.Next() {
+		var p Person
+		r.Scan(&p)
+		exp := getExpected(expected, i)
+		assert.Equal(t, *exp, p, fmt.Sprintf("row %d", i))
+		i++
+	}
+
+	assert.Nil(t, r.Error())
+	assert.Equal(t, getLen(expected), i)
+}
+
+// writePeople writes each of the row groups of people and returns the
+// parquet file.
+func writePeople(t testing.TB, input [][]Person, opts ...func(*ParquetWriter) error) []byte {
+	var buf bytes.Buffer
+	w, err := NewParquetWriter(&buf, opts...)
+	if !assert.NoError(t, err) {
+		return nil
+	}
+	for _, rowgroup := range input {
+		for _, p := range rowgroup {
+			w.Add(p)
+		}
+		assert.NoError(t, w.Write())
+	}
+	assert.NoError(t, w.Close())
+	return buf.Bytes()
+}
+
+// rowGroups splits people into row groups of size people.
+func rowGroups(people []Person, size int) [][]Person {
+	var out [][]Person
+	for len(people) > size {
+		out = append(out, people[:size])
+		people = people[size:]
+	}
+	return append(out, people)
+}
+
+// readPeople reads all the people of a parquet file.
+func readPeople(t testing.TB, rs io.ReadSeeker, opts ...func(*ParquetReader)) []Person {
+	r, err := NewParquetReader(rs, opts...)
+	if !assert.NoError(t, err) {
+		return nil
+	}
+	return scanPeople(t, r)
+}
+
+// scanPeople reads the rest of the people of a reader.
+func scanPeople(t testing.TB, r *ParquetReader) []Person {
+	var out []Person
+	for r.Next() {
+		var p Person
+		r.Scan(&p)
+		out = append(out, p)
+	}
+	assert.NoError(t, r.Error())
+	return out
+}
+
+func TestPageHeaders(t *testing.T) {
+	docs := [][]Person{
+		{{}, {}, {}, {}},
+		{{}, {}, {}, {}},
+	}
+
+	rd := bytes.NewReader(writePeople(t, docs, MaxPageSize(2)))
+	footer, err := parquet.ReadMetaData(rd)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	pageHeaders, err := parquet.PageHeaders(footer, rd)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.Equal(t, 88, len(pageHeaders))
+}
+
+func TestDataPageV2(t *testing.T) {
+	type testCase struct {
+		name     string
+		input    []Person
+		pageSize int
+		col      string
+		headers  []sch.DataPageHeaderV2
+	}
+
+	testCases := []testCase{
+		{
+			name:     "required",
+			col:      "birthday",
+			pageSize: 2,
+			input:    []Person{{Birthday: 1}, {Birthday: 2}, {Birthday: 3}},
+			headers: []sch.DataPageHeaderV2{
+				{NumValues: 2, NumRows: 2, Encoding: sch.Encoding_PLAIN},
+				{NumValues: 1, NumRows: 1, Encoding: sch.Encoding_PLAIN},
+			},
+		},
+		{
+			name:  "optional",
+			col:   "code",
+			input: []Person{{Code: pstring("a")}, {}, {Code: pstring("b")}},
+			headers: []sch.DataPageHeaderV2{
+				{NumValues: 3, NumNulls: 1, NumRows: 3, Encoding: sch.Encoding_PLAIN, DefinitionLevelsByteLength: 2},
+			},
+		},
+		{
+			name: "repeated",
+			col:  "friends.list.element.id",
+			input: []Person{
+				{Friends: []Being{{ID: 1}, {ID: 2}}},
+				{},
+				{Friends: []Being{{ID: 3}}},
+			},
+			headers: []sch.DataPageHeaderV2{
+				{NumValues: 4, NumNulls: 1, NumRows: 3, Encoding: sch.Encoding_PLAIN, DefinitionLevelsByteLength: 2, RepetitionLevelsByteLength: 2},
+			},
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("%02d %s", i, tc.name), func(t *testing.T) {
+			if tc.pageSize == 0 {
+				tc.pageSize = 100
+			}
+			r := bytes.NewReader(writePeople(t, [][]Person{tc.input}, MaxPageSize(tc.pageSize), DataPageV2, Uncompressed))
+			footer, err := parquet.ReadMetaData(r)
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			pages, err := getPageHeaders(r, tc.col, footer)
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			if !assert.Equal(t, len(tc.headers), len(pages)) {
+				return
+			}
+
+			for i, ph := range pages {
+				assert.Equal(t, sch.PageType_DATA_PAGE_V2, ph.Type)
+				assert.Nil(t, ph.DataPageHeader)
+				h := ph.DataPageHeaderV2
+				exp := tc.headers[i]
+				assert.Equal(t, exp.NumValues, h.NumValues)
+				assert.Equal(t, exp.NumNulls, h.NumNulls)
+				assert.Equal(t, exp.NumRows, h.NumRows)
+				assert.Equal(t, exp.Encoding, h.Encoding)
+				assert.Equal(t, exp.DefinitionLevelsByteLength, h.DefinitionLevelsByteLength)
+				assert.Equal(t, exp.RepetitionLevelsByteLength, h.RepetitionLevelsByteLength)
+				assert.False(t, h.IsCompressed)
+			}
+		})
+	}
+}
+
+func TestCompression(t *testing.T) {
+	testCases := []struct {
+		opt   func(*ParquetWriter) error
+		codec sch.CompressionCodec
+	}{
+		{opt: Uncompressed, codec: sch.CompressionCodec_UNCOMPRESSED},
+		{opt: Snappy, codec: sch.CompressionCodec_SNAPPY},
+		{opt: Gzip, codec: sch.CompressionCodec_GZIP},
+		{opt: Zstd, codec: sch.CompressionCodec_ZSTD},
+		{opt: Lz4, codec: sch.CompressionCodec_LZ4_RAW},
+		{opt: Brotli, codec: sch.CompressionCodec_BROTLI},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("%02d %s", i, tc.codec), func(t *testing.T) {
+			input := []Person{
+				{BFF: "Fred", Hobby: &Hobby{Name: "knitting"}},
+				{BFF: strings.Repeat("Fred", 100)},
+				{BFF: "Val", Hobby: &Hobby{Name: "kayaking", Skills: []Skill{{Name: "paddling"}}}},
+			}
+
+			b := writePeople(t, [][]Person{input}, tc.opt, MaxPageSize(2))
+			footer, err := parquet.ReadMetaData(bytes.NewReader(b))
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			for _, col := range footer.RowGroups[0].Columns {
+				assert.Equal(t, tc.codec, col.MetaData.Codec, strings.Join(col.MetaData.PathInSchema, "."))
+			}
+			assert.Equal(t, input, readPeople(t, bytes.NewReader(b)))
+		})
+	}
+}
+
+func TestColumnCompression(t *testing.T) {
+	input := make([]Person, 500)
+	for i := range input {
+		input[i] = Person{
+			BFF:   fmt.Sprintf("%d %s", i%37, strings.Repeat("friend ", i%13)),
+			Hobby: &Hobby{Name: "kayaking", Skills: []Skill{{Name: "paddling", Difficulty: "hard"}}},
+		}
+	}
+
+	write := func(opts ...func(*ParquetWriter) error) ([]byte, *sch.FileMetaData) {
+		b := writePeople(t, [][]Person{input}, opts...)
+		footer, err := parquet.ReadMetaData(bytes.NewReader(b))
+		assert.NoError(t, err)
+		return b, footer
+	}
+
+	codecs := func(footer *sch.FileMetaData) map[string]sch.CompressionCodec {
+		out := map[string]sch.CompressionCodec{}
+		for _, col := range footer.RowGroups[0].Columns {
+			out[strings.Join(col.MetaData.PathInSchema, ".")] = col.MetaData.Codec
+		}
+		return out
+	}
+
+	b, footer := write(Uncompressed, ColumnCompression("bff", GzipLevel(9)), ColumnCompression("hobby.skills.difficulty", Snappy))
+	cols := codecs(footer)
+	assert.Equal(t, sch.CompressionCodec_GZIP, cols["bff"])
+	assert.Equal(t, sch.CompressionCodec_SNAPPY, cols["hobby.skills.list.element.difficulty"])
+	assert.Equal(t, sch.CompressionCodec_UNCOMPRESSED, cols["hobby.name"])
+	assert.Equal(t, input, readPeople(t, bytes.NewReader(b)))
+
+	size := func(footer *sch.FileMetaData, col string) int64 {
+		for _, ch := range footer.RowGroups[0].Columns {
+			if strings.Join(ch.MetaData.PathInSchema, ".") == col {
+				return ch.MetaData.TotalCompressedSize
+			}
+		}
+		return 0
+	}
+
+	for _, tc := range []struct {
+		name        string
+		fast, small func(*ParquetWriter) error
+	}{
+		{name: "gzip", fast: GzipLevel(1), small: GzipLevel(9)},
+		{name: "zstd", fast: ZstdLevel(1), small: ZstdLevel(19)},
+		{name: "brotli", fast: BrotliLevel(1), small: BrotliLevel(11)},
+	} {
+		_, fast := write(tc.fast)
+		_, small := write(tc.small)
+		assert.Less(t, size(small, "bff"), size(fast, "bff"), tc.name)
+	}
+
+	for _, opt := range []func(*ParquetWriter) error{
+		GzipLevel(0),
+		ZstdLevel(23),
+		BrotliLevel(12),
+		ColumnCompression("bff", Dictionary),
+		ColumnCompression("nope", Gzip),
+		ColumnCompression("bff", GzipLevel(10)),
+	} {
+		_, err := NewParquetWriter(&bytes.Buffer{}, opt)
+		assert.Error(t, err)
+	}
+}
+
+func TestStats(t *testing.T) {
+	type stats struct {
+		min      []byte
+		max      []byte
+		nilCount *int64
+	}
+
+	type testCase struct {
+		name     string
+		input    [][]Person
+		pageSize int
+		stats    []stats
+		col      string
+		// chunks are the statistics of each row group's column chunk
+		chunks []stats
+	}
+
+	testCases := []testCase{
+		{
+			name: "int64 single stat",
+			col:  "happiness",
+			input: [][]Person{
+				{
+					{Happiness: 1},
+					{Happiness: 2},
+					{Happiness: 22},
+				},
+			},
+			stats: []stats{
+				{min: writeInt64(1), max: writeInt64(22)},
+			},
+		},
+		{
+			name:     "int64 two pages",
+			col:      "happiness",
+			pageSize: 2,
+			input: [][]Person{
+				{
+					{Happiness: 1},
+					{Happiness: 2},
+					{Happiness: 22},
+				},
+			},
+			stats: []stats{
+				{min: writeInt64(1), max: writeInt64(2)},
+				{min: writeInt64(22), max: writeInt64(22)},
+			},
+		},
+		{
+			name: "int64 no stats",
+			col:  "happiness",
+			input: [][]Person{
+				{
+					{Being: Being{ID: 1}},
+					{Being: Being{ID: 2}},
+					{Being: Being{ID: 3}},
+				},
+			},
+			stats: []stats{
+				{min: writeInt64(0), max: writeInt64(0)},
+			},
+		},
+		{
+			name: "optional int64 no stats",
+			col:  "sadness",
+			input: [][]Person{
+				{
+					{Being: Being{ID: 1}},
+					{Being: Being{ID: 2}},
+					{Being: Being{ID: 3}},
+				},
+			},
+			stats: []stats{
+				{min: nil, max: nil, nilCount: pint64(3)},
+			},
+		},
+		{
+			name: "int32 stats",
+			col:  "birthday",
+			input: [][]Person{
+				{
+					{Birthday: 10},
+					{Birthday: 20},
+					{Birthday: 30},
+				},
+			},
+			stats: []stats{
+				{min: writeInt32(10), max: writeInt32(30)},
+			},
+		},
+		{
+			name: "float64 stats",
+			col:  "boldness",
+			input: [][]Person{
+				{
+					{Boldness: 0.5},
+					{Boldness: 500.0},
+					{Boldness: -50.5},
+				},
+			},
+			stats: []stats{
+				{min: writeFloat64(-50.5), max: writeFloat64(500.0)},
+			},
+		},
+		{
+			name: "float32 optional stats",
+			col:  "lameness",
+			input: [][]Person{
+				{
+					{Lameness: pfloat32(0.5)},
+					{Lameness: pfloat32(500.0)},
+					{Lameness: pfloat32(50.5)},
+					{Lameness: nil},
+				},
+			},
+			stats: []stats{
+				{min: writeFloat32(0.5), max: writeFloat32(500.0), nilCount: pint64(1)},
+			},
+		},
+		{
+			name:     "int64 negative column chunk",
+			col:      "happiness",
+			pageSize: 2,
+			input: [][]Person{
+				{
+					{Happiness: -10},
+					{Happiness: -3},
+					{Happiness: -22},
+				},
+				{
+					{Happiness: -1},
+				},
+			},
+			stats: []stats{
+				{min: writeInt64(-10), max: writeInt64(-3)},
+				{min: writeInt64(-22), max: writeInt64(-22)},
+				{min: writeInt64(-1), max: writeInt64(-1)},
+			},
+			chunks: []stats{
+				{min: writeInt64(-22), max: writeInt64(-3), nilCount: pint64(0)},
+				{min: writeInt64(-1), max: writeInt64(-1), nilCount: pint64(0)},
+			},
+		},
+		{
+			name:     "uint32 column chunk",
+			col:      "birthday",
+			pageSize: 2,
+			input: [][]Person{
+				{
+					{Birthday: 10},
+					{Birthday: math.MaxUint32},
+					{Birthday: 1 << 31},
+				},
+			},
+			stats: []stats{
+				{min: writeUint32(10), max: writeUint32(math.MaxUint32)},
+				{min: writeUint32(1 << 31), max: writeUint32(1 << 31)},
+			},
+			chunks: []stats{
+				{min: writeUint32(10), max: writeUint32(math.MaxUint32), nilCount: pint64(0)},
+			},
+		},
+		{
+			name:     "float32 optional column chunk",
+			col:      "lameness",
+			pageSize: 2,
+			input: [][]Person{
+				{
+					{Lameness: pfloat32(-0.5)},
+					{Lameness: nil},
+					{Lameness: pfloat32(-50.5)},
+					{Lameness: nil},
+					{Lameness: nil},
+				},
+			},
+			stats: []stats{
+				{min: writeFloat32(-0.5), max: writeFloat32(-0.5), nilCount: pint64(1)},
+				{min: writeFloat32(-50.5), max: writeFloat32(-50.5), nilCount: pint64(1)},
+				{nilCount: pint64(1)},
+			},
+			chunks: []stats{
+				{min: writeFloat32(-50.5), max: writeFloat32(-0.5), nilCount: pint64(3)},
+			},
+		},
+		{
+			name: "bool stats",
+			col:  "hungry",
+			input: [][]Person{
+				{
+					{Hungry: true},
+					{Hungry: false},
+					{Hungry: true},
+				},
+			},
+			stats: []stats{
+				{},
+			},
+		},
+		{
+			name: "optional bool stats",
+			col:  "keen",
+			input: [][]Person{
+				{
+					{Keen: pbool(true)},
+					{Keen: nil},
+					{Keen: nil},
+				},
+			},
+			stats: []stats{
+				{nilCount: pint64(2)},
+			},
+		},
+		{
+			name: "string stats",
+			col:  "bff",
+			input: [][]Person{
+				{
+					{BFF: "Fred"},
+					{BFF: "Val"},
+					{BFF: "Miranda"},
+				},
+			},
+			stats: []stats{
+				{min: []byte("Fred"), max: []byte("Val")},
+			},
+		},
+		{
+			name: "string optional stats",
+			col:  "code",
+			input: [][]Person{
+				{
+					{Code: pstring("Fred")},
+					{Code: nil},
+					{Code: pstring("Miranda")},
+				},
+			},
+			stats: []stats{
+				{min: []byte("Fred"), max: []byte("Miranda"), nilCount: pint64(1)},
+			},
+		},
+		{
+			name:     "string optional column chunk",
+			col:      "code",
+			pageSize: 2,
+			input: [][]Person{
+				{
+					{Code: pstring("Fred")},
+					{Code: nil},
+					{Code: pstring("Miranda")},
+					{Code: pstring("Alice")},
+					{Code: pstring("\xff")},
+				},
+			},
+			stats: []stats{
+				{min: []byte("Fred"), max: []byte("Fred"), nilCount: pint64(1)},
+				{min: []byte("Alice"), max: []byte("Miranda"), nilCount: pint64(0)},
+				{min: []byte("\xff"), max: []byte("\xff"), nilCount: pint64(0)},
+			},
+			chunks: []stats{
+				{min: []byte("Alice"), max: []byte("\xff"), nilCount: pint64(1)},
+			},
+		},
+	}
+
+	for i, tc := range testCases {
+		for j, comp := range []string{"uncompressed", "snappy"} {
 			t.Run(fmt.Sprintf("%02d %s %s", 2*i+j, tc.name, comp), func(t *testing.T) {
 				if tc.pageSize == 0 {
 					tc.pageSize = 100
 				}
-				var buf bytes.Buffer
-				w, err := NewParquetWriter(&buf, MaxPageSize(tc.pageSize), compressionTest[comp])
-				assert.Nil(t, err, tc.name)
-				for _, rowgroup := range tc.input {
-					for _, p := range rowgroup {
-						w.Add(p)
-					}
-					assert.Nil(t, w.Write(), tc.name)
+				r := bytes.NewReader(writePeople(t, tc.input, MaxPageSize(tc.pageSize), compressionTest[comp]))
+				footer, err := parquet.ReadMetaData(r)
+				if !assert.NoError(t, err) {
+					return
+				}
+
+				pages, err := getPageHeaders(r, tc.col, footer)
+				if !assert.NoError(t, err) {
+					return
+				}
+
+				if !assert.Equal(t, len(tc.stats), len(pages), tc.name) {
+					return
+				}
+				for i, st := range tc.stats {
+					ph := pages[i]
+					assert.Equal(t, st.min, ph.DataPageHeader.Statistics.MinValue)
+					assert.Equal(t, st.max, ph.DataPageHeader.Statistics.MaxValue)
+					if st.nilCount == nil {
+						assert.Equal(t, st.nilCount, ph.DataPageHeader.Statistics.NullCount)
+					} else {
+						assert.Equal(t, *st.nilCount, *ph.DataPageHeader.Statistics.NullCount)
+					}
+				}
+
+				if tc.chunks != nil && !assert.Equal(t, len(tc.chunks), len(footer.RowGroups)) {
+					return
+				}
+				for i, st := range tc.chunks {
+					col := getColumn(&sch.FileMetaData{RowGroups: footer.RowGroups[i : i+1]}, tc.col)
+					if !assert.NotNil(t, col) || !assert.NotNil(t, col.MetaData.Statistics) {
+						return
+					}
+					assert.Equal(t, st.min, col.MetaData.Statistics.MinValue)
+					assert.Equal(t, st.max, col.MetaData.Statistics.MaxValue)
+					assert.Equal(t, st.nilCount, col.MetaData.Statistics.NullCount)
+				}
+
+				var leaves int
+				for _, se := range footer.Schema {
+					if se.Type != nil {
+						leaves++
+					}
+				}
+				assert.Equal(t, leaves, len(footer.ColumnOrders))
+				for _, co := range footer.ColumnOrders {
+					assert.NotNil(t, co.TYPE_ORDER)
+				}
+			})
+		}
+	}
+}
+
+func TestPageIndex(t *testing.T) {
+	type index struct {
+		rows       []int64
+		nullPages  []bool
+		min        [][]byte
+		max        [][]byte
+		nullCounts []int64
+		order      sch.BoundaryOrder
+	}
+
+	type testCase struct {
+		name     string
+		col      string
+		pageSize int
+		input    [][]Person
+		// indexes are the expected page indexes of each row group's column
+		// chunk.  A nil min means the column chunk doesn't have a ColumnIndex.
+		indexes []index
+	}
+
+	testCases := []testCase{
+		{
+			name:     "ascending and descending",
+			col:      "happiness",
+			pageSize: 2,
+			input: [][]Person{
+				{{Happiness: 1}, {Happiness: 2}, {Happiness: 3}, {Happiness: 4}, {Happiness: 5}},
+				{{Happiness: 9}, {Happiness: 8}, {Happiness: 7}},
+			},
+			indexes: []index{
+				{
+					rows:      []int64{0, 2, 4},
+					nullPages: []bool{false, false, false},
+					min:       [][]byte{writeInt64(1), writeInt64(3), writeInt64(5)},
+					max:       [][]byte{writeInt64(2), writeInt64(4), writeInt64(5)},
+					order:     sch.BoundaryOrder_ASCENDING,
+				},
+				{
+					rows:      []int64{0, 2},
+					nullPages: []bool{false, false},
+					min:       [][]byte{writeInt64(8), writeInt64(7)},
+					max:       [][]byte{writeInt64(9), writeInt64(7)},
+					order:     sch.BoundaryOrder_DESCENDING,
+				},
+			},
+		},
+		{
+			name:     "unordered",
+			col:      "happiness",
+			pageSize: 2,
+			input: [][]Person{
+				{{Happiness: 5}, {Happiness: 6}, {Happiness: 1}, {Happiness: 2}, {Happiness: 7}, {Happiness: 8}},
+			},
+			indexes: []index{
+				{
+					rows:      []int64{0, 2, 4},
+					nullPages: []bool{false, false, false},
+					min:       [][]byte{writeInt64(5), writeInt64(1), writeInt64(7)},
+					max:       [][]byte{writeInt64(6), writeInt64(2), writeInt64(8)},
+					order:     sch.BoundaryOrder_UNORDERED,
+				},
+			},
+		},
+		{
+			name:     "null page",
+			col:      "sadness",
+			pageSize: 2,
+			input: [][]Person{
+				{{}, {}, {Sadness: pint64(3)}, {Sadness: pint64(4)}, {}, {Sadness: pint64(5)}},
+			},
+			indexes: []index{
+				{
+					rows:       []int64{0, 2, 4},
+					nullPages:  []bool{true, false, false},
+					min:        [][]byte{{}, writeInt64(3), writeInt64(5)},
+					max:        [][]byte{{}, writeInt64(4), writeInt64(5)},
+					nullCounts: []int64{2, 0, 1},
+					order:      sch.BoundaryOrder_ASCENDING,
+				},
+			},
+		},
+		{
+			name:     "no column index for bools",
+			col:      "hungry",
+			pageSize: 2,
+			input: [][]Person{
+				{{Hungry: true}, {}, {Hungry: true}},
+			},
+			indexes: []index{
+				{rows: []int64{0, 2}},
+			},
+		},
+		{
+			name:     "repeated",
+			col:      "friends.list.element.id",
+			pageSize: 2,
+			input: [][]Person{
+				{
+					{Friends: []Being{{ID: 3}, {ID: 4}, {ID: 5}}},
+					{Friends: []Being{{ID: 1}}},
+					{Friends: []Being{{ID: 8}, {ID: 6}}},
+				},
+			},
+			indexes: []index{
+				{
+					rows:       []int64{0, 2},
+					nullPages:  []bool{false, false},
+					min:        [][]byte{writeInt32(1), writeInt32(6)},
+					max:        [][]byte{writeInt32(5), writeInt32(8)},
+					nullCounts: []int64{0, 0},
+					order:      sch.BoundaryOrder_ASCENDING,
+				},
+			},
+		},
+	}
+
+	for i, tc := range testCases {
+		for j, enc := range encodingCases {
+			t.Run(fmt.Sprintf("%02d %s %s", len(encodingCases)*i+j, tc.name, enc), func(t *testing.T) {
+				r := bytes.NewReader(writePeople(t, tc.input, MaxPageSize(tc.pageSize), encodingTest[enc]))
+				footer, err := parquet.ReadMetaData(r)
+				if !assert.NoError(t, err) || !assert.Equal(t, len(tc.indexes), len(footer.RowGroups)) {
+					return
+				}
+
+				for i, exp := range tc.indexes {
+					assert.Equal(t, int16(i), footer.RowGroups[i].GetOrdinal())
+					col := getColumn(&sch.FileMetaData{RowGroups: footer.RowGroups[i : i+1]}, tc.col)
+					if !assert.NotNil(t, col) {
+						return
+					}
+
+					oi, err := parquet.ReadOffsetIndex(r, col)
+					if !assert.NoError(t, err) || !assert.NotNil(t, oi) || !assert.Equal(t, len(exp.rows), len(oi.PageLocations)) {
+						return
+					}
+
+					// the pages are right after each other and the last one
+					// ends where the column chunk ends.
+					off := col.MetaData.DataPageOffset
+					for k, loc := range oi.PageLocations {
+						assert.Equal(t, off, loc.Offset)
+						assert.Equal(t, exp.rows[k], loc.FirstRowIndex)
+						off += int64(loc.CompressedPageSize)
+
+						ph, err := parquet.PageHeadersAtOffset(r, loc.Offset, 0)
+						if assert.NoError(t, err) {
+							assert.Contains(t, []sch.PageType{sch.PageType_DATA_PAGE, sch.PageType_DATA_PAGE_V2}, ph[0].Type)
+						}
+					}
+					start := col.MetaData.DataPageOffset
+					if col.MetaData.DictionaryPageOffset != nil {
+						start = *col.MetaData.DictionaryPageOffset
+					}
+					assert.Equal(t, start+col.MetaData.TotalCompressedSize, off)
+
+					ci, err := parquet.ReadColumnIndex(r, col)
+					if !assert.NoError(t, err) {
+						return
+					}
+
+					if exp.min == nil {
+						assert.Nil(t, ci)
+						assert.Nil(t, col.ColumnIndexOffset)
+						continue
+					}
+
+					if !assert.NotNil(t, ci) {
+						return
+					}
+					assert.Equal(t, exp.nullPages, ci.NullPages)
+					assert.Equal(t, exp.min, ci.MinValues)
+					assert.Equal(t, exp.max, ci.MaxValues)
+					assert.Equal(t, exp.order, ci.BoundaryOrder)
+					if exp.nullCounts == nil {
+						assert.Empty(t, ci.NullCounts)
+					} else {
+						assert.Equal(t, exp.nullCounts, ci.NullCounts)
+					}
+				}
+			})
+		}
+	}
+}
+
+func TestWhere(t *testing.T) {
+	var input []Person
+	for i := 0; i < 350; i++ {
+		p := newPerson(i)
+		p.BFF = fmt.Sprintf("bff-%d", i%10)
+		p.Hungry = i%3 == 0
+		if i%4 != 0 {
+			p.Friends = []Being{{ID: int32(i % 10)}, {ID: int32(i % 13)}}
+		}
+		if i%7 == 0 {
+			p.Hobby = &Hobby{Name: []string{"knitting", "sewing"}[i%2]}
+		}
+		input = append(input, p)
+	}
+
+	type testCase struct {
+		name    string
+		filters []func(*ParquetReader)
+		match   func(p Person) bool
+	}
+
+	testCases := []testCase{
+		{
+			name:    "int64 greater than",
+			filters: []func(*ParquetReader){Where("happiness", parquet.Gt(int64(500)))},
+			match:   func(p Person) bool { return p.Happiness > 500 },
+		},
+		{
+			name:    "int64 point lookup",
+			filters: []func(*ParquetReader){Where("happiness", parquet.Eq(int64(222)))},
+			match:   func(p Person) bool { return p.Happiness == 222 },
+		},
+		{
+			name:    "optional int32 less than",
+			filters: []func(*ParquetReader){Where("age", parquet.Lt(int32(22)))},
+			match:   func(p Person) bool { return p.Age != nil && *p.Age < 22 },
+		},
+		{
+			name:    "uint32 greater than or equal",
+			filters: []func(*ParquetReader){Where("birthday", parquet.Gte(uint32(340000)))},
+			match:   func(p Person) bool { return p.Birthday >= 340000 },
+		},
+		{
+			name:    "unsigned uint64 less than or equal",
+			filters: []func(*ParquetReader){Where("anniversary", parquet.Lte(uint64(math.MaxUint64-30000)))},
+			match:   func(p Person) bool { return p.Anniversary != nil && *p.Anniversary <= math.MaxUint64-30000 },
+		},
+		{
+			name:    "float32",
+			filters: []func(*ParquetReader){Where("funkiness", parquet.Gt(float32(0.9)))},
+			match:   func(p Person) bool { return p.Funkiness > 0.9 },
+		},
+		{
+			name:    "string",
+			filters: []func(*ParquetReader){Where("bff", parquet.Eq("bff-7"))},
+			match:   func(p Person) bool { return p.BFF == "bff-7" },
+		},
+		{
+			name:    "bool",
+			filters: []func(*ParquetReader){Where("hungry", parquet.Eq(true))},
+			match:   func(p Person) bool { return p.Hungry },
+		},
+		{
+			name:    "repeated",
+			filters: []func(*ParquetReader){Where("friends.id", parquet.Eq(int32(7)))},
+			match: func(p Person) bool {
+				for _, f := range p.Friends {
+					if f.ID == 7 {
+						return true
+					}
+				}
+				return false
+			},
+		},
+		{
+			name:    "repeated with its full name",
+			filters: []func(*ParquetReader){Where("friends.list.element.id", parquet.Eq(int32(7)))},
+			match: func(p Person) bool {
+				for _, f := range p.Friends {
+					if f.ID == 7 {
+						return true
+					}
+				}
+				return false
+			},
+		},
+		{
+			name:    "nested",
+			filters: []func(*ParquetReader){Where("hobby.name", parquet.Eq("knitting"))},
+			match:   func(p Person) bool { return p.Hobby != nil && p.Hobby.Name == "knitting" },
+		},
+		{
+			name: "two filters",
+			filters: []func(*ParquetReader){
+				Where("happiness", parquet.Gt(int64(100))),
+				Where("age", parquet.Eq(int32(22))),
+			},
+			match: func(p Person) bool { return p.Happiness > 100 && p.Age != nil && *p.Age == 22 },
+		},
+		{
+			name:    "no matches",
+			filters: []func(*ParquetReader){Where("happiness", parquet.Gt(int64(1000000)))},
+			match:   func(p Person) bool { return false },
+		},
+	}
+
+	for i, tc := range testCases {
+		for j, enc := range encodingCases {
+			t.Run(fmt.Sprintf("%02d %s %s", len(encodingCases)*i+j, tc.name, enc), func(t *testing.T) {
+				b := writePeople(t, rowGroups(input, 100), MaxPageSize(10), encodingTest[enc])
+
+				var expected []Person
+				for _, p := range input {
+					if tc.match(p) {
+						expected = append(expected, p)
+					}
+				}
+
+				r, err := NewParquetReader(bytes.NewReader(b), tc.filters...)
+				if !assert.NoError(t, err) || !assert.Equal(t, len(expected), int(r.Rows())) {
+					return
+				}
+				assert.Equal(t, expected, scanPeople(t, r))
+			})
+		}
+	}
+}
+
+func TestWhereSkipsPages(t *testing.T) {
+	b := writePeople(t, getPeople(100, 1000), MaxPageSize(10))
+
+	read := func(opts ...func(*ParquetReader)) (int64, []Person) {
+		rc := &readCounter{r: bytes.NewReader(b)}
+		out := readPeople(t, rc, opts...)
+		return rc.n, out
+	}
+
+	rc := &readCounter{r: bytes.NewReader(b)}
+	_, err := parquet.ReadMetaData(rc)
+	assert.NoError(t, err)
+	footer := rc.n
+
+	all, _ := read()
+	n, people := read(Where("happiness", parquet.Eq(int64(1234))))
+	if assert.Len(t, people, 1) {
+		assert.Equal(t, int32(617), people[0].ID)
+	}
+
+	// both readers read the footer, but only a few of the pages and page
+	// indexes should be read by the one with a filter.
+	assert.Less(t, n-footer, (all-footer)/20)
+}
+
+func TestWhereEmptyWrites(t *testing.T) {
+	var buf bytes.Buffer
+	w, err := NewParquetWriter(&buf)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	// the empty row groups aren't in the footer, so the offsets of the
+	// column chunks and page indexes after them have to be right anyway.
+	for _, rg := range getPeople(10, 20) {
+		for _, p := range rg {
+			w.Add(p)
+		}
+		assert.NoError(t, w.Write())
+		assert.NoError(t, w.Write())
+	}
+	assert.NoError(t, w.Close())
+
+	out := readPeople(t, bytes.NewReader(buf.Bytes()), Where("happiness", parquet.Eq(int64(34))))
+	if assert.Len(t, out, 1) {
+		assert.Equal(t, int32(17), out[0].ID)
+	}
+}
+
+func TestWhereReadsIndexes(t *testing.T) {
+	b := writePeople(t, getPeople(100, 1000), MaxPageSize(10))
+	footer, err := parquet.ReadMetaData(bytes.NewReader(b))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	rc := &readCounter{r: bytes.NewReader(b)}
+	_, err = parquet.ReadMetaData(rc)
+	assert.NoError(t, err)
+	n := rc.n
+
+	rc = &readCounter{r: bytes.NewReader(b)}
+	_, err = NewParquetReader(rc, Columns("happiness"), Where("happiness", parquet.Eq(int64(1234))))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	// only the row group with happiness 1234 is kept, and only the page
+	// indexes of its happiness column are read, along with its pages
+	// (once to filter the row group and once to read it).
+	var ch *sch.ColumnChunk
+	for _, col := range footer.RowGroups[6].Columns {
+		if strings.Join(col.MetaData.PathInSchema, ".") == "happiness" {
+			ch = col
+		}
+	}
+	if assert.NotNil(t, ch) {
+		assert.LessOrEqual(t, rc.n-n, int64(*ch.OffsetIndexLength)+int64(*ch.ColumnIndexLength)+2*ch.MetaData.TotalCompressedSize)
+	}
+}
+
+func TestWhereErrors(t *testing.T) {
+	b := writePeople(t, [][]Person{{{}}})
+
+	_, err := NewParquetReader(bytes.NewReader(b), Where("nope", parquet.Eq(int32(1))))
+	assert.EqualError(t, err, "unknown column nope")
+
+	_, err = NewParquetReader(bytes.NewReader(b), Where("happiness", parquet.Gt(int32(1))))
+	assert.EqualError(t, err, "can't compare a value of type int32 to column happiness of type INT64")
+
+	_, err = NewParquetReader(bytes.NewReader(b), Where("birthday", parquet.Gt(int32(1))))
+	assert.EqualError(t, err, "can't compare a value of type int32 to column birthday of type INT32")
+
+	_, err = NewParquetReader(bytes.NewReader(b), Where("bff", parquet.Gt(big.NewInt(1))))
+	assert.EqualError(t, err, "can't compare a value of type *big.Int to column bff of type BYTE_ARRAY")
+}
+
+func TestColumns(t *testing.T) {
+	var input []Person
+	for i := 0; i < 250; i++ {
+		p := newPerson(i)
+		if i%3 != 0 {
+			p.Friends = []Being{{ID: int32(i), Name: fmt.Sprintf("friend-%d", i)}, {ID: int32(i + 1), Age: pint32(int32(i % 50))}}
+		}
+		switch i % 4 {
+		case 1:
+			p.Hobby = &Hobby{Name: "knitting", Difficulty: pint32(int32(i % 10))}
+		case 2:
+			p.Hobby = &Hobby{Name: "sewing", Skills: []Skill{{Name: "needles", Difficulty: "hard"}, {Name: "thread", Difficulty: fmt.Sprintf("level %d", i)}}}
+		}
+		input = append(input, p)
+	}
+
+	type testCase struct {
+		name    string
+		opts    []func(*ParquetReader)
+		match   func(p Person) bool
+		project func(p Person) Person
+	}
+
+	testCases := []testCase{
+		{
+			name: "required",
+			opts: []func(*ParquetReader){Columns("id", "happiness")},
+			project: func(p Person) Person {
+				return Person{Being: Being{ID: p.ID}, Happiness: p.Happiness}
+			},
+		},
+		{
+			name: "optional",
+			opts: []func(*ParquetReader){Columns("age", "keen")},
+			project: func(p Person) Person {
+				return Person{Being: Being{Age: p.Age}, Keen: p.Keen}
+			},
+		},
+		{
+			name: "repeated first column",
+			opts: []func(*ParquetReader){Columns("friends.id")},
+			project: func(p Person) Person {
+				var out Person
+				for _, f := range p.Friends {
+					out.Friends = append(out.Friends, Being{ID: f.ID})
+				}
+				return out
+			},
+		},
+		{
+			name: "repeated first column with its full name",
+			opts: []func(*ParquetReader){Columns("friends.list.element.id")},
+			project: func(p Person) Person {
+				var out Person
+				for _, f := range p.Friends {
+					out.Friends = append(out.Friends, Being{ID: f.ID})
+				}
+				return out
+			},
+		},
+		{
+			name: "repeated later column",
+			opts: []func(*ParquetReader){Columns("id", "friends.name")},
+			project: func(p Person) Person {
+				out := Person{Being: Being{ID: p.ID}}
+				for _, f := range p.Friends {
+					out.Friends = append(out.Friends, Being{Name: f.Name})
+				}
+				return out
+			},
+		},
+		{
+			name: "repeated optional column",
+			opts: []func(*ParquetReader){Columns("friends.age")},
+			project: func(p Person) Person {
+				var out Person
+				for _, f := range p.Friends {
+					out.Friends = append(out.Friends, Being{Age: f.Age})
+				}
+				return out
+			},
+		},
+		{
+			name: "nested optional column",
+			opts: []func(*ParquetReader){Columns("hobby.difficulty")},
+			project: func(p Person) Person {
+				var out Person
+				if p.Hobby != nil {
+					out.Hobby = &Hobby{Difficulty: p.Hobby.Difficulty}
+				}
+				return out
+			},
+		},
+		{
+			name: "nested repeated column",
+			opts: []func(*ParquetReader){Columns("hobby.skills.difficulty")},
+			project: func(p Person) Person {
+				var out Person
+				if p.Hobby != nil {
+					out.Hobby = &Hobby{}
+					for _, s := range p.Hobby.Skills {
+						out.Hobby.Skills = append(out.Hobby.Skills, Skill{Difficulty: s.Difficulty})
+					}
+				}
+				return out
+			},
+		},
+		{
+			name: "group",
+			opts: []func(*ParquetReader){Columns("hobby")},
+			project: func(p Person) Person {
+				return Person{Hobby: p.Hobby}
+			},
+		},
+		{
+			name:  "with a filter",
+			opts:  []func(*ParquetReader){Columns("friends.name"), Where("happiness", parquet.Lt(int64(100)))},
+			match: func(p Person) bool { return p.Happiness < 100 },
+			project: func(p Person) Person {
+				var out Person
+				for _, f := range p.Friends {
+					out.Friends = append(out.Friends, Being{Name: f.Name})
+				}
+				return out
+			},
+		},
+	}
+
+	for i, tc := range testCases {
+		for j, enc := range encodingCases {
+			t.Run(fmt.Sprintf("%02d %s %s", len(encodingCases)*i+j, tc.name, enc), func(t *testing.T) {
+				b := writePeople(t, rowGroups(input, 100), MaxPageSize(10), encodingTest[enc])
+
+				var expected []Person
+				for _, p := range input {
+					if tc.match == nil || tc.match(p) {
+						expected = append(expected, tc.project(p))
+					}
+				}
+				assert.Equal(t, expected, readPeople(t, bytes.NewReader(b), tc.opts...))
+			})
+		}
+	}
+}
+
+func TestColumnsSkipsColumns(t *testing.T) {
+	rc := &readCounter{r: bytes.NewReader(writePeople(t, getPeople(100, 1000)))}
+	footer, err := parquet.ReadMetaData(rc)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	var size int64
+	for _, rg := range footer.RowGroups {
+		for _, ch := range rg.Columns {
+			if col := strings.Join(ch.MetaData.PathInSchema, "."); col == "id" || col == "friends.list.element.name" {
+				size += ch.MetaData.TotalCompressedSize
+			}
+		}
+	}
+
+	n := rc.n
+	rc.n = 0
+	assert.Len(t, readPeople(t, rc, Columns("id", "friends.name")), 1000)
+
+	// the reader only reads the footer and the two column chunks.
+	assert.Equal(t, n+size, rc.n)
+}
+
+func TestColumnsErrors(t *testing.T) {
+	b := writePeople(t, [][]Person{{{}}})
+
+	_, err := NewParquetReader(bytes.NewReader(b), Columns("id", "nope"))
+	assert.EqualError(t, err, "unknown column nope")
+
+	_, err = NewParquetReader(bytes.NewReader(b), Columns("friend"))
+	assert.EqualError(t, err, "unknown column friend")
+}
+
+func TestSeek(t *testing.T) {
+	input := getFriendlyPeople(350)
+	for _, enc := range encodingCases {
+		t.Run(enc, func(t *testing.T) {
+			b := writePeople(t, rowGroups(input, 100), MaxPageSize(10), encodingTest[enc])
+			r, err := NewParquetReader(bytes.NewReader(b))
+			if !assert.NoError(t, err) {
+				return
+			}
+			assert.Equal(t, 4, r.NumRowGroups())
+
+			// seeking backwards and forwards with the same reader
+			for _, n := range []int64{349, 0, 5, 99, 100, 234, 17} {
+				if assert.NoError(t, r.SeekToRow(n), fmt.Sprintf("row %d", n)) {
+					assert.Equal(t, input[n:], scanPeople(t, r), fmt.Sprintf("row %d", n))
+				}
+			}
+
+			for _, i := range []int{2, 0, 3} {
+				if assert.NoError(t, r.SeekToRowGroup(i), fmt.Sprintf("row group %d", i)) {
+					assert.Equal(t, input[i*100:], scanPeople(t, r), fmt.Sprintf("row group %d", i))
+				}
+			}
+
+			assert.EqualError(t, r.SeekToRow(350), "row 350 is out of range, the reader has 350 rows")
+			assert.EqualError(t, r.SeekToRowGroup(4), "row group 4 is out of range, the reader has 4 row groups")
+
+			// the rows of a reader with a filter are the ones that match
+			var expected []Person
+			for _, p := range input {
+				if p.Hobby != nil {
+					expected = append(expected, p)
+				}
+			}
+
+			r, err = NewParquetReader(bytes.NewReader(b), Where("hobby.name", parquet.Eq("knitting")))
+			if !assert.NoError(t, err) {
+				return
+			}
+			for _, n := range []int64{30, 0, 80} {
+				if assert.NoError(t, r.SeekToRow(n), fmt.Sprintf("row %d", n)) {
+					assert.Equal(t, expected[n:], scanPeople(t, r), fmt.Sprintf("row %d", n))
+				}
+			}
+		})
+	}
+}
+
+func TestSeekSkipsPages(t *testing.T) {
+	b := writePeople(t, getPeople(1000, 1000), MaxPageSize(10))
+	rc := &readCounter{r: bytes.NewReader(b)}
+	r, err := NewParquetReader(rc)
+	if !assert.NoError(t, err) {
+		return
+	}
+	all := rc.n
+
+	assert.NoError(t, r.SeekToRow(995))
+	n := rc.n - all
+	people := scanPeople(t, r)
+	if assert.Len(t, people, 5) {
+		assert.Equal(t, int32(995), people[0].ID)
+	}
+
+	footer, err := parquet.ReadMetaData(bytes.NewReader(b))
+	if !assert.NoError(t, err) {
+		return
+	}
+	var indexes int64
+	for _, ch := range footer.RowGroups[0].Columns {
+		indexes += int64(*ch.OffsetIndexLength)
+	}
+
+	// the first reader reads the footer and all of the pages, but seeking
+	// only reads the OffsetIndex and the last page of each column.
+	assert.Less(t, n-indexes, all/20)
+}
+
+func TestConcurrency(t *testing.T) {
+	input := getFriendlyPeople(1000)
+	b := writePeople(t, rowGroups(input, 90), MaxPageSize(30))
+
+	t.Run("in order", func(t *testing.T) {
+		for _, n := range []int{1, 2, 4, 32} {
+			r, err := NewParquetReader(bytes.NewReader(b), Concurrency(n))
+			if assert.NoError(t, err) {
+				assert.Equal(t, input, scanPeople(t, r), fmt.Sprintf("concurrency %d", n))
+			}
+		}
+	})
+
+	t.Run("io.ReaderAt", func(t *testing.T) {
+		r, err := NewParquetReaderAt(readerAt{bytes.NewReader(b)}, int64(len(b)), Concurrency(4))
+		if assert.NoError(t, err) {
+			assert.Equal(t, input, scanPeople(t, r))
+		}
+	})
+
+	t.Run("seek", func(t *testing.T) {
+		r, err := NewParquetReader(bytes.NewReader(b), Concurrency(4))
+		if !assert.NoError(t, err) {
+			return
+		}
+
+		for i := 0; i < 100 && r.Next(); i++ {
+			var p Person
+			r.Scan(&p)
+		}
+
+		for _, n := range []int64{500, 3, 999} {
+			if assert.NoError(t, r.SeekToRow(n)) {
+				assert.Equal(t, input[n:], scanPeople(t, r), fmt.Sprintf("row %d", n))
+			}
+		}
+	})
+
+	t.Run("filter and columns", func(t *testing.T) {
+		var expected []Person
+		for _, p := range input {
+			if p.Happiness > 700 {
+				expected = append(expected, Person{Being: Being{ID: p.ID}, Friends: p.Friends})
+			}
+		}
+
+		r, err := NewParquetReader(bytes.NewReader(b), Concurrency(4), Where("happiness", parquet.Gt(int64(700))), Columns("id", "friends"))
+		if assert.NoError(t, err) {
+			assert.Equal(t, expected, scanPeople(t, r))
+		}
+	})
+
+	t.Run("unordered", func(t *testing.T) {
+		for _, n := range []int{1, 4} {
+			r, err := NewParquetReader(bytes.NewReader(b), Concurrency(n))
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			// the rows that have already been read aren't sent
+			for i := 0; i < 10 && r.Next(); i++ {
+				var p Person
+				r.Scan(&p)
+			}
+
+			var out []Person
+			for p := range r.Unordered() {
+				out = append(out, p)
+			}
+			assert.NoError(t, r.Error())
+			sort.Slice(out, func(i, j int) bool { return out[i].ID < out[j].ID })
+			assert.Equal(t, input[10:], out, fmt.Sprintf("concurrency %d", n))
+		}
+	})
+
+	t.Run("close", func(t *testing.T) {
+		r, err := NewParquetReader(bytes.NewReader(b), Concurrency(4))
+		if !assert.NoError(t, err) {
+			return
+		}
+		assert.True(t, r.Next())
+		assert.NoError(t, r.Close())
+
+		r, err = NewParquetReader(bytes.NewReader(b), Concurrency(4))
+		if !assert.NoError(t, err) {
+			return
+		}
+		ch := r.Unordered()
+		<-ch
+		assert.NoError(t, r.Close())
+	})
+
+	t.Run("not an io.ReaderAt", func(t *testing.T) {
+		_, err := NewParquetReader(&readCounter{r: bytes.NewReader(b)}, Concurrency(4))
+		assert.EqualError(t, err, "concurrency needs a reader that is an io.ReaderAt")
+	})
+}
+
+func TestWriteConcurrency(t *testing.T) {
+	input := getFriendlyPeople(1000)
+	write := func(opts ...func(*ParquetWriter) error) []byte {
+		return writePeople(t, rowGroups(input, 300), append(opts, MaxPageSize(30))...)
+	}
+
+	opts := []struct {
+		name string
+		opts []func(*ParquetWriter) error
+	}{
+		{name: "snappy"},
+		{name: "dictionary", opts: []func(*ParquetWriter) error{Dictionary}},
+		{name: "v2 zstd", opts: []func(*ParquetWriter) error{DataPageV2, Zstd}},
+	}
+
+	for _, o := range opts {
+		t.Run(o.name, func(t *testing.T) {
+			// with GOMAXPROCS 1 the column chunks are written one after
+			// another, without any goroutines, like they were before
+			// they were written concurrently.
+			procs := runtime.GOMAXPROCS(1)
+			serial := write(o.opts...)
+			runtime.GOMAXPROCS(procs)
+			if procs < 8 {
+				defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(8))
+			}
+
+			assert.Equal(t, serial, write(o.opts...))
+			assert.Equal(t, input, readPeople(t, bytes.NewReader(serial)))
+		})
+	}
+}
+
+// readerAt hides everything but r's ReadAt method.
+type readerAt struct {
+	r io.ReaderAt
+}
+
+func (r readerAt) ReadAt(p []byte, off int64) (int, error) {
+	return r.r.ReadAt(p, off)
+}
+
+func TestReadBatch(t *testing.T) {
+	input := getFriendlyPeople(350)
+	b := writePeople(t, rowGroups(input, 100), MaxPageSize(30))
+
+	for _, size := range []int{1, 7, 100, 1000} {
+		t.Run(fmt.Sprintf("batches of %d", size), func(t *testing.T) {
+			r, err := NewParquetReader(bytes.NewReader(b))
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			// ReadBatch picks up where Next and Scan left off
+			var out []Person
+			for i := 0; i < 3 && r.Next(); i++ {
+				var p Person
+				r.Scan(&p)
+				out = append(out, p)
+			}
+
+			// the batch is reused, so stale values have to be cleared
+			batch := make([]Person, size)
+			for {
+				n, err := r.ReadBatch(batch)
+				if err == io.EOF {
+					assert.Equal(t, 0, n)
+					break
+				}
+				if !assert.NoError(t, err) {
+					return
 				}
+				out = append(out, batch[:n]...)
+			}
+			assert.Equal(t, input, out)
+		})
+	}
+
+	t.Run("filter and columns", func(t *testing.T) {
+		var expected []Person
+		for _, p := range input {
+			if p.Hobby != nil {
+				expected = append(expected, Person{Being: Being{ID: p.ID}, Friends: p.Friends})
+			}
+		}
+
+		r, err := NewParquetReader(bytes.NewReader(b), Where("hobby.name", parquet.Eq("knitting")), Columns("id", "friends"))
+		if !assert.NoError(t, err) {
+			return
+		}
+
+		batch := make([]Person, 1000)
+		n, err := r.ReadBatch(batch)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, batch[:n])
+		_, err = r.ReadBatch(batch)
+		assert.Equal(t, io.EOF, err)
+	})
+}
+
+func TestReadColumns(t *testing.T) {
+	input := getFriendlyPeople(350)
+	b := writePeople(t, rowGroups(input, 100), MaxPageSize(30))
+
+	var happiness, friends int64
+	var ages int
+	for _, p := range input[1:] {
+		happiness += p.Happiness
+		if p.Age != nil {
+			ages++
+		}
+		for _, f := range p.Friends {
+			friends += int64(f.ID)
+		}
+	}
+
+	r, err := NewParquetReader(bytes.NewReader(b), Columns("happiness", "age", "friends.id"))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	// the rest of the current row group is returned
+	assert.True(t, r.Next())
+	var p Person
+	r.Scan(&p)
+
+	var rowGroups, nulls int
+	var sumHappiness, sumFriends int64
+	var nAges int
+	for {
+		cols, err := r.ReadColumns()
+		if err == io.EOF {
+			break
+		}
+		if !assert.NoError(t, err) || !assert.Len(t, cols, 3) {
+			return
+		}
+		rowGroups++
+
+		assert.Equal(t, "age", cols[0].Name)
+		assert.Equal(t, "happiness", cols[1].Name)
+		assert.Equal(t, "friends.list.element.id", cols[2].Name)
+
+		nAges += len(cols[0].Vals.([]int32))
+		for _, d := range cols[0].Defs {
+			if d == 0 {
+				nulls++
+			}
+		}
+
+		assert.Nil(t, cols[1].Defs)
+		for _, v := range cols[1].Vals.([]int64) {
+			sumHappiness += v
+		}
+
+		assert.Equal(t, len(cols[2].Defs), len(cols[2].Reps))
+		for _, v := range cols[2].Vals.([]int32) {
+			sumFriends += int64(v)
+		}
+	}
+
+	assert.Equal(t, 4, rowGroups)
+	assert.Equal(t, happiness, sumHappiness)
+	assert.Equal(t, friends, sumFriends)
+	assert.Equal(t, ages, nAges)
+	assert.Equal(t, 349-ages, nulls)
+	assert.False(t, r.Next())
+}
 
-				err = w.Close()
-				assert.Nil(t, err, tc.name)
+func TestAddBatch(t *testing.T) {
+	input := rowGroups(getFriendlyPeople(350), 100)
+	for _, dict := range []bool{false, true} {
+		opts := []func(*ParquetWriter) error{MaxPageSize(30)}
+		if dict {
+			opts = append(opts, Dictionary)
+		}
+		expected := writePeople(t, input, opts...)
 
-				r, err := NewParquetReader(bytes.NewReader(buf.Bytes()))
+		for _, size := range []int{1, 7, 30, 100} {
+			t.Run(fmt.Sprintf("dictionary %t batches of %d", dict, size), func(t *testing.T) {
+				var buf bytes.Buffer
+				w, err := NewParquetWriter(&buf, opts...)
 				if !assert.NoError(t, err) {
 					return
 				}
+				for _, rowgroup := range input {
+					for _, batch := range rowGroups(rowgroup, size) {
+						w.AddBatch(batch)
+					}
+					assert.NoError(t, w.Write())
+				}
+				assert.NoError(t, w.Close())
+				assert.Equal(t, expected, buf.Bytes())
+			})
+		}
+	}
+}
+
+func TestAppendColumns(t *testing.T) {
+	input := getFriendlyPeople(350)
+	for _, p := range input {
+		if p.Hobby != nil {
+			p.Hobby.Skills = append(p.Hobby.Skills, Skill{Name: "yarn"})
+		}
+	}
+
+	write := func(twice bool) []byte {
+		var rgs [][]Person
+		for _, rg := range rowGroups(input, 100) {
+			if twice {
+				rg = append(append([]Person{}, rg...), rg...)
+			}
+			rgs = append(rgs, rg)
+		}
+		return writePeople(t, rgs, MaxPageSize(30))
+	}
+
+	// appending the columns of each row group of a file twice makes the
+	// same file as adding each row group's rows twice.  The second time
+	// the columns are appended the last page of the row group is only
+	// partly full.
+	r, err := NewParquetReader(bytes.NewReader(write(false)))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	var out bytes.Buffer
+	w, err := NewParquetWriter(&out, MaxPageSize(30))
+	if !assert.NoError(t, err) {
+		return
+	}
+	for {
+		cols, err := r.ReadColumns()
+		if err == io.EOF {
+			break
+		}
+		if !assert.NoError(t, err) {
+			return
+		}
+
+		assert.NoError(t, w.AppendColumns(cols))
+		assert.NoError(t, w.AppendColumns(cols))
+		assert.NoError(t, w.Write())
+	}
+	assert.NoError(t, w.Close())
+	assert.Equal(t, write(true), out.Bytes())
+}
+
+func TestAppendColumnsErrors(t *testing.T) {
+	columns := func() []Column {
+		b := writePeople(t, [][]Person{{{Friends: []Being{{ID: 1}, {ID: 2}}}, {}}})
+		r, err := NewParquetReader(bytes.NewReader(b))
+		if !assert.NoError(t, err) {
+			return nil
+		}
+		cols, err := r.ReadColumns()
+		assert.NoError(t, err)
+		return cols
+	}
 
-				expected := tc.expected
-				if expected == nil {
-					expected = tc.input
+	testCases := []struct {
+		name   string
+		change func(cols []Column) []Column
+		err    string
+	}{
+		{
+			name:   "missing column",
+			change: func(cols []Column) []Column { return cols[1:] },
+			err:    "missing column id",
+		},
+		{
+			name:   "unknown column",
+			change: func(cols []Column) []Column { return append(cols, Column{Name: "nope"}) },
+			err:    "unknown column nope",
+		},
+		{
+			name: "wrong type",
+			change: func(cols []Column) []Column {
+				cols[0].Vals = []int64{1, 2}
+				return cols
+			},
+			err: "can't append a []int64 to column id, it needs a []int32",
+		},
+		{
+			name: "too many rows",
+			change: func(cols []Column) []Column {
+				cols[1].Vals = []string{"a", "b", "c"}
+				return cols
+			},
+			err: "column name has 3 rows, but column id has 2",
+		},
+		{
+			name: "levels for a required column",
+			change: func(cols []Column) []Column {
+				cols[0].Defs = []uint8{0, 0}
+				return cols
+			},
+			err: "column id is required, so it can't have levels",
+		},
+		{
+			name: "values that don't match the levels",
+			change: func(cols []Column) []Column {
+				cols[2].Vals = []int32{5}
+				return cols
+			},
+			err: "column age has 1 values, but its definition levels have 0",
+		},
+		{
+			name: "repetition levels that don't match",
+			change: func(cols []Column) []Column {
+				for i, c := range cols {
+					if c.Name == "friends.list.element.id" {
+						cols[i].Reps = cols[i].Reps[1:]
+					}
 				}
+				return cols
+			},
+			err: "column friends.list.element.id has 2 repetition levels and 3 definition levels",
+		},
+	}
 
-				if !assert.Equal(t, getLen(expected), int(r.Rows()), tc.name) {
-					return
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			w, err := NewParquetWriter(&bytes.Buffer{})
+			if !assert.NoError(t, err) {
+				return
+			}
+			assert.EqualError(t, w.AppendColumns(tc.change(columns())), tc.err)
+		})
+	}
+}
+
+func TestTargetBytes(t *testing.T) {
+	input := getFriendlyPeople(1000)
+
+	const pageBytes, rowGroupBytes = 2000, 20000
+
+	testCases := []struct {
+		name string
+		add  func(w *ParquetWriter, people []Person) error
+	}{
+		{
+			name: "add",
+			add: func(w *ParquetWriter, people []Person) error {
+				for _, p := range people {
+					w.Add(p)
+				}
+				return nil
+			},
+		},
+		{
+			name: "add batch",
+			add: func(w *ParquetWriter, people []Person) error {
+				w.AddBatch(people)
+				return nil
+			},
+		},
+		{
+			name: "append columns",
+			add: func(w *ParquetWriter, people []Person) error {
+				r, err := NewParquetReader(bytes.NewReader(writePeople(t, [][]Person{people})))
+				if err != nil {
+					return err
+				}
+				cols, err := r.ReadColumns()
+				if err != nil {
+					return err
 				}
+				return w.AppendColumns(cols)
+			},
+		},
+	}
 
-				var i int
-				for r.Next() {
-					var p Person
-					r.Scan(&p)
-					exp := getExpected(expected, i)
-					assert.Equal(t, *exp, p, fmt.Sprintf("%s-%d", tc.name, i))
-					i++
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			w, err := NewParquetWriter(&buf, TargetPageBytes(pageBytes), TargetRowGroupBytes(rowGroupBytes), Uncompressed)
+			if !assert.NoError(t, err) {
+				return
+			}
+			if !assert.NoError(t, tc.add(w, input)) {
+				return
+			}
+			assert.NoError(t, w.Write())
+			assert.NoError(t, w.Close())
+
+			footer, err := parquet.ReadMetaData(bytes.NewReader(buf.Bytes()))
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			// the estimates don't include the page headers, so only the
+			// pages are compared to the targets, and they are allowed to
+			// go over them a bit.
+			assert.True(t, len(footer.RowGroups) > 1)
+			var pages int
+			for _, rg := range footer.RowGroups {
+				assert.True(t, rg.NumRows > 0)
+
+				var size int32
+				for _, col := range rg.Columns {
+					headers, err := parquet.PageHeadersAtOffset(bytes.NewReader(buf.Bytes()), col.MetaData.DataPageOffset, col.MetaData.NumValues)
+					if !assert.NoError(t, err) {
+						return
+					}
+					for _, h := range headers {
+						assert.True(t, h.UncompressedPageSize < pageBytes*3/2, h.UncompressedPageSize)
+						size += h.UncompressedPageSize
+					}
+					pages += len(headers)
 				}
+				assert.True(t, size < rowGroupBytes*3/2, size)
+			}
+			assert.True(t, pages > len(footer.RowGroups)*len(footer.RowGroups[0].Columns))
+			assert.Equal(t, input, readPeople(t, bytes.NewReader(buf.Bytes())))
+		})
+	}
+}
 
-				assert.Nil(t, r.Error(), tc.name)
-				assert.Equal(t, getLen(expected), i, tc.name)
-			})
+func TestTargetPageBytesRows(t *testing.T) {
+	input := make([]Person, 3000)
+	for i := range input {
+		input[i].ID = int32(i)
+	}
+
+	pages := func(opts ...func(*ParquetWriter) error) int {
+		b := writePeople(t, [][]Person{input}, opts...)
+		footer, err := parquet.ReadMetaData(bytes.NewReader(b))
+		if !assert.NoError(t, err) {
+			return 0
 		}
+		col := footer.RowGroups[0].Columns[0]
+		headers, err := parquet.PageHeadersAtOffset(bytes.NewReader(b), col.MetaData.DataPageOffset, col.MetaData.NumValues)
+		assert.NoError(t, err)
+		return len(headers)
 	}
+
+	// a page has up to 1000 rows unless only its size is limited
+	assert.Equal(t, 3, pages())
+	assert.Equal(t, 1, pages(TargetPageBytes(1<<20)))
+	assert.Equal(t, 3, pages(TargetPageBytes(1<<20), MaxPageSize(1000)))
 }
 
-func TestPageHeaders(t *testing.T) {
+func TestTargetBytesErrors(t *testing.T) {
+	_, err := NewParquetWriter(&bytes.Buffer{}, TargetPageBytes(0))
+	assert.EqualError(t, err, "invalid page size 0, it must be greater than 0")
+
+	_, err = NewParquetWriter(&bytes.Buffer{}, TargetRowGroupBytes(-1))
+	assert.EqualError(t, err, "invalid row group size -1, it must be greater than 0")
+}
+
+func TestMaxBufferedBytes(t *testing.T) {
+	input := getFriendlyPeople(1000)
+
+	const maxBuffered = 10000
+
+	for name, enc := range encodingTest {
+		t.Run(name, func(t *testing.T) {
+			var buf bytes.Buffer
+			w, err := NewParquetWriter(&buf, MaxPageSize(20), MaxBufferedBytes(maxBuffered), enc)
+			if !assert.NoError(t, err) {
+				return
+			}
+			var max int
+			for _, p := range input {
+				w.Add(p)
+				if n := w.buffered() + w.pageSize(); n > max {
+					max = n
+				}
+			}
+			assert.True(t, max < maxBuffered, max)
+			assert.NoError(t, w.Write())
+			assert.NoError(t, w.Close())
+
+			footer, err := parquet.ReadMetaData(bytes.NewReader(buf.Bytes()))
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			// the page headers and the dictionary pages' headers aren't
+			// counted, so a row group can be a bit bigger than maxBuffered.
+			assert.True(t, len(footer.RowGroups) > 1)
+			for _, rg := range footer.RowGroups {
+				var size int64
+				for _, col := range rg.Columns {
+					size += col.MetaData.TotalCompressedSize
+				}
+				assert.True(t, size < maxBuffered*3/2, size)
+			}
+			assert.Equal(t, input, readPeople(t, bytes.NewReader(buf.Bytes())))
+		})
+	}
+
+	_, err := NewParquetWriter(&bytes.Buffer{}, MaxBufferedBytes(0))
+	assert.EqualError(t, err, "invalid buffer size 0, it must be greater than 0")
+}
+
+func TestEmptyWrite(t *testing.T) {
 	var buf bytes.Buffer
-	w, err := NewParquetWriter(&buf, MaxPageSize(2))
+	w, err := NewParquetWriter(&buf)
 	if !assert.NoError(t, err) {
 		return
 	}
+	assert.NoError(t, w.Write())
+	assert.Equal(t, 4, buf.Len())
+
+	w.Add(newPerson(1))
+	assert.NoError(t, w.Write())
+	n := buf.Len()
+	assert.NoError(t, w.Write())
+	assert.Equal(t, n, buf.Len())
+}
 
-	docs := [][]Person{
-		{{}, {}, {}, {}},
-		{{}, {}, {}, {}},
+func TestKeyValue(t *testing.T) {
+	write := func(opts ...func(*ParquetWriter) error) []byte {
+		return writePeople(t, [][]Person{{newPerson(1)}}, opts...)
 	}
-	for _, rowgroup := range docs {
-		for _, p := range rowgroup {
-			w.Add(p)
+
+	b := write(KeyValue("lineage", "job-1"), KeyValue("owner", "data"), KeyValue("lineage", "job-2"))
+
+	footer, err := parquet.ReadMetaData(bytes.NewReader(b))
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, parquet.CreatedBy, footer.GetCreatedBy())
+	if assert.Len(t, footer.KeyValueMetadata, 2) {
+		assert.Equal(t, "lineage", footer.KeyValueMetadata[0].Key)
+		assert.Equal(t, "owner", footer.KeyValueMetadata[1].Key)
+	}
+
+	r, err := NewParquetReader(bytes.NewReader(b))
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, map[string]string{"lineage": "job-2", "owner": "data"}, r.KeyValueMetadata())
+
+	r, err = NewParquetReader(bytes.NewReader(write()))
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, map[string]string{}, r.KeyValueMetadata())
+}
+
+// readCounter counts the bytes that are read from r.
+type readCounter struct {
+	r io.ReadSeeker
+	n int64
+}
+
+func (r *readCounter) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	r.n += int64(n)
+	return n, err
+}
+
+func (r *readCounter) Seek(offset int64, whence int) (int64, error) {
+	return r.r.Seek(offset, whence)
+}
+
+func TestLists(t *testing.T) {
+	var input []Person
+	for i := 0; i < 50; i++ {
+		p := newPerson(i)
+		if i%3 > 0 {
+			p.Friends = []Being{{ID: int32(i), Name: fmt.Sprintf("friend-%d", i)}, {ID: int32(i + 1)}}
+		}
+		if i%2 == 0 {
+			p.Hobby = &Hobby{Name: "juggling", Skills: []Skill{{Name: "balls", Difficulty: "easy"}, {Name: "clubs", Difficulty: "hard"}}}
+		}
+		input = append(input, p)
+	}
+
+	b := writePeople(t, [][]Person{input}, MaxPageSize(10))
+	footer, err := parquet.ReadMetaData(bytes.NewReader(b))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	schema := schemaPaths(footer.Schema)
+	for _, name := range []string{"friends", "hobby.skills"} {
+		l := schema[name]
+		assert.Equal(t, sch.FieldRepetitionType_REQUIRED, l.GetRepetitionType(), name)
+		assert.Equal(t, sch.ConvertedType_LIST, l.GetConvertedType(), name)
+		assert.NotNil(t, l.LogicalType.LIST, name)
+		assert.Equal(t, int32(1), l.GetNumChildren(), name)
+
+		list := schema[name+".list"]
+		assert.Equal(t, sch.FieldRepetitionType_REPEATED, list.GetRepetitionType(), name)
+		assert.Nil(t, list.LogicalType, name)
+		assert.Equal(t, int32(1), list.GetNumChildren(), name)
+		assert.Equal(t, sch.FieldRepetitionType_REQUIRED, schema[name+".list.element"].GetRepetitionType(), name)
+	}
+	assert.Equal(t, int32(3), schema["friends.list.element"].GetNumChildren())
+
+	testCases := []struct {
+		name string
+		file []byte
+	}{
+		{name: "standard", file: b},
+		{name: "legacy", file: legacyLists(t, b)},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, input, readPeople(t, bytes.NewReader(tc.file)))
+
+			out := readPeople(t, bytes.NewReader(tc.file), Columns("friends.id"), Where("friends.id", parquet.Eq(int32(7))))
+			if assert.Len(t, out, 1) {
+				assert.Equal(t, []Being{{ID: 7}, {ID: 8}}, out[0].Friends)
+			}
+		})
+	}
+}
+
+// legacyLists rewrites the footer of a parquet file so that its lists
+// are repeated fields, like the files written by code that was
+// generated with the -legacy-lists flag.
+func legacyLists(t *testing.T, b []byte) []byte {
+	footer, err := parquet.ReadMetaData(bytes.NewReader(b))
+	if !assert.NoError(t, err) {
+		return nil
+	}
+
+	var schema []*sch.SchemaElement
+	for i := 0; i < len(footer.Schema); i++ {
+		se := footer.Schema[i]
+		if se.GetConvertedType() == sch.ConvertedType_LIST {
+			elem := *footer.Schema[i+2]
+			elem.Name = se.Name
+			elem.RepetitionType = sch.FieldRepetitionTypePtr(sch.FieldRepetitionType_REPEATED)
+			schema = append(schema, &elem)
+			i += 2
+			continue
+		}
+		schema = append(schema, se)
+	}
+	footer.Schema = schema
+
+	for _, rg := range footer.RowGroups {
+		for _, ch := range rg.Columns {
+			var pth []string
+			for i := 0; i < len(ch.MetaData.PathInSchema); i++ {
+				if ch.MetaData.PathInSchema[i] == "list" && ch.MetaData.PathInSchema[i+1] == "element" {
+					i++
+					continue
+				}
+				pth = append(pth, ch.MetaData.PathInSchema[i])
+			}
+			ch.MetaData.PathInSchema = pth
+		}
+	}
+
+	ts := thrift.NewTSerializer()
+	ts.Protocol = thrift.NewTCompactProtocolFactory().GetProtocol(ts.Transport)
+	meta, err := ts.Write(context.TODO(), footer)
+	if !assert.NoError(t, err) {
+		return nil
+	}
+
+	size := binary.LittleEndian.Uint32(b[len(b)-8:])
+	out := append([]byte{}, b[:len(b)-8-int(size)]...)
+	out = append(out, meta...)
+	out = binary.LittleEndian.AppendUint32(out, uint32(len(meta)))
+	return append(out, "PAR1"...)
+}
+
+// TestForeignLists reads lists that were written by arrow, whose lists
+// and elements are optional.
+func TestForeignLists(t *testing.T) {
+	var expected []Person
+	for i := 0; i < 20; i++ {
+		p := Person{Being: Being{ID: int32(i)}}
+		if i%4 > 1 {
+			p.Friends = []Being{
+				{ID: int32(i), Name: fmt.Sprintf("friend-%d-0", i)},
+				{ID: int32(-i), Name: fmt.Sprintf("friend-%d-1", i)},
+			}
+		}
+		if i%5 != 0 {
+			p.Hobby = &Hobby{Name: fmt.Sprintf("hobby-%d", i)}
+			if i%2 == 1 {
+				p.Hobby.Skills = []Skill{{Name: fmt.Sprintf("skill-%d", i), Difficulty: "hard"}}
+			}
+		}
+		expected = append(expected, p)
+	}
+
+	cols := Columns("id", "friends.id", "friends.name", "hobby.name", "hobby.skills.name", "hobby.skills.difficulty")
+
+	f, err := os.Open("testdata/lists.parquet")
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer f.Close()
+
+	assert.Equal(t, expected, readPeople(t, f, cols))
+	assert.Equal(t, expected[6:7], readPeople(t, f, cols, Where("friends.id", parquet.Eq(int32(-6)))))
+}
+
+// schemaPaths returns the schema elements by their dotted paths.
+func schemaPaths(schema []*sch.SchemaElement) map[string]*sch.SchemaElement {
+	out := map[string]*sch.SchemaElement{}
+	var walk func(i int, prefix string) int
+	walk = func(i int, prefix string) int {
+		se := schema[i]
+		i++
+		for j := int32(0); j < se.GetNumChildren(); j++ {
+			name := prefix + schema[i].Name
+			out[name] = schema[i]
+			i = walk(i, name+".")
+		}
+		return i
+	}
+	walk(0, "")
+	return out
+}
+
+// TestForeignDictionary reads dictionary encoded pages that were written
+// by arrow.
+func TestForeignDictionary(t *testing.T) {
+	testForeignPages(t, "testdata/dictionary.parquet", func(ph sch.PageHeader) bool {
+		return ph.DictionaryPageHeader != nil || ph.DataPageHeader != nil && ph.DataPageHeader.Encoding == sch.Encoding_RLE_DICTIONARY
+	})
+}
+
+// TestForeignDataPageV2 reads DATA_PAGE_V2 pages that were written by
+// arrow.
+func TestForeignDataPageV2(t *testing.T) {
+	testForeignPages(t, "testdata/data_page_v2.parquet", func(ph sch.PageHeader) bool {
+		return ph.DataPageHeaderV2 != nil
+	})
+}
+
+// testForeignPages reads a file that arrow wrote from the people of
+// arrowPerson and checks that some of its pages are the ones the test
+// is about.
+func testForeignPages(t *testing.T, pth string, isPage func(sch.PageHeader) bool) {
+	f, err := os.Open(pth)
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer f.Close()
+
+	footer, err := parquet.ReadMetaData(f)
+	if !assert.NoError(t, err) {
+		return
+	}
+	pageHeaders, err := parquet.PageHeaders(footer, f)
+	if !assert.NoError(t, err) {
+		return
+	}
+	var n int
+	for _, ph := range pageHeaders {
+		if isPage(ph) {
+			n++
 		}
-		assert.NoError(t, w.Write())
 	}
+	assert.True(t, n > 1, "not enough pages")
 
-	assert.NoError(t, w.Close())
+	var expected []Person
+	for i := 0; i < 100; i++ {
+		expected = append(expected, arrowPerson(i))
+	}
 
-	rd := bytes.NewReader(buf.Bytes())
-	footer, err := parquet.ReadMetaData(rd)
-	if !assert.NoError(t, err) {
-		return
+	assert.Equal(t, expected, readPeople(t, f))
+
+	var ids []int32
+	for _, p := range readPeople(t, f, Columns("id", "friends.id"), Where("friends.id", parquet.Eq(int32(6)))) {
+		ids = append(ids, p.ID)
 	}
 
-	pageHeaders, err := parquet.PageHeaders(footer, rd)
-	if !assert.NoError(t, err) {
-		return
+	var expectedIDs []int32
+	for _, p := range expected {
+		for _, f := range p.Friends {
+			if f.ID == 6 {
+				expectedIDs = append(expectedIDs, p.ID)
+				break
+			}
+		}
 	}
+	assert.Equal(t, expectedIDs, ids)
+}
 
-	assert.Equal(t, 88, len(pageHeaders))
+// arrowPerson returns the people in the files in testdata that arrow
+// wrote in row groups of 50.
+func arrowPerson(i int) Person {
+	p := Person{
+		Being:     Being{ID: int32(i), Name: fmt.Sprintf("name-%d", i%7)},
+		Happiness: int64(i%4) * 100,
+		Funkiness: float32(i%3) / 2,
+		Boldness:  float64(i%5) * 1.5,
+		Birthday:  uint32(i%10) * 1000,
+		BFF:       fmt.Sprintf("bff-%d", i%4),
+		Hungry:    i%3 == 0,
+	}
+	if i%3 != 0 {
+		p.Age = pint32(int32(20 + i%5))
+	}
+	if i%5 != 0 {
+		p.Sadness = pint64(int64(i % 3))
+	}
+	if i%4 != 0 {
+		p.Code = pstring(fmt.Sprintf("code-%d", i%6))
+	}
+	if i%2 != 0 {
+		p.Lameness = pfloat32(float32(i % 4))
+	}
+	if i%3 != 1 {
+		p.Keen = pbool(i%2 == 0)
+	}
+	if i%6 != 0 {
+		p.Anniversary = puint64(1<<40 + uint64(i%3))
+	}
+	if i%4 != 0 {
+		p.Hobby = &Hobby{Name: fmt.Sprintf("hobby-%d", i%3)}
+		if i%2 != 0 {
+			p.Hobby.Difficulty = pint32(int32(i % 5))
+		}
+		if i%3 == 1 {
+			p.Hobby.Skills = []Skill{{Name: fmt.Sprintf("skill-%d", i%2), Difficulty: "easy"}}
+		}
+	}
+	for j := 0; j < i%3 && i%5 != 0; j++ {
+		f := Being{ID: int32((i + j) % 7), Name: fmt.Sprintf("friend-%d", j)}
+		if j > 0 {
+			f.Age = pint32(int32(i % 4))
+		}
+		p.Friends = append(p.Friends, f)
+	}
+	return p
 }
 
-func TestStats(t *testing.T) {
-	type stats struct {
-		min      []byte
-		max      []byte
-		nilCount *int64
+func TestPutDecimal(t *testing.T) {
+	testCases := []struct {
+		val      int64
+		size     int
+		expected []byte
+	}{
+		{val: 0, size: 2, expected: []byte{0, 0}},
+		{val: 1, size: 2, expected: []byte{0, 1}},
+		{val: -1, size: 2, expected: []byte{0xff, 0xff}},
+		{val: 256, size: 2, expected: []byte{1, 0}},
+		{val: -256, size: 2, expected: []byte{0xff, 0}},
+		{val: 32767, size: 2, expected: []byte{0x7f, 0xff}},
+		{val: -32768, size: 2, expected: []byte{0x80, 0}},
+		{val: 32768, size: 2},
+		{val: -32769, size: 2},
+		{val: -1 << 17, size: 2},
+	}
+
+	for _, tc := range testCases {
+		t.Run(fmt.Sprint(tc.val), func(t *testing.T) {
+			b := make([]byte, tc.size)
+			err := parquet.PutDecimal(b, big.NewInt(tc.val))
+			if tc.expected == nil {
+				assert.Error(t, err)
+				return
+			}
+
+			if assert.NoError(t, err) {
+				assert.Equal(t, tc.expected, b)
+				assert.Equal(t, big.NewInt(tc.val), parquet.Decimal(b))
+			}
+		})
 	}
+}
 
+func TestDictionary(t *testing.T) {
 	type testCase struct {
-		name     string
-		input    [][]Person
-		pageSize int
-		stats    []stats
-		col      string
+		name      string
+		input     []Person
+		pageSize  int
+		col       string
+		dictSize  int32
+		encodings []sch.Encoding
 	}
 
 	testCases := []testCase{
 		{
-			name: "int64 single stat",
-			col:  "happiness",
-			input: [][]Person{
-				{
-					{Happiness: 1},
-					{Happiness: 2},
-					{Happiness: 22},
-				},
-			},
-			stats: []stats{
-				{min: writeInt64(1), max: writeInt64(22)},
-			},
-		},
-		{
-			name:     "int64 two pages",
-			col:      "happiness",
-			pageSize: 2,
-			input: [][]Person{
-				{
-					{Happiness: 1},
-					{Happiness: 2},
-					{Happiness: 22},
-				},
-			},
-			stats: []stats{
-				{min: writeInt64(1), max: writeInt64(2)},
-				{min: writeInt64(22), max: writeInt64(22)},
-			},
-		},
-		{
-			name: "int64 no stats",
-			col:  "happiness",
-			input: [][]Person{
-				{
-					{Being: Being{ID: 1}},
-					{Being: Being{ID: 2}},
-					{Being: Being{ID: 3}},
-				},
-			},
-			stats: []stats{
-				{min: writeInt64(0), max: writeInt64(0)},
-			},
-		},
-		{
-			name: "optional int64 no stats",
-			col:  "sadness",
-			input: [][]Person{
-				{
-					{Being: Being{ID: 1}},
-					{Being: Being{ID: 2}},
-					{Being: Being{ID: 3}},
-				},
-			},
-			stats: []stats{
-				{min: nil, max: nil, nilCount: pint64(3)},
-			},
-		},
-		{
-			name: "int32 stats",
-			col:  "birthday",
-			input: [][]Person{
-				{
-					{Birthday: 10},
-					{Birthday: 20},
-					{Birthday: 30},
-				},
-			},
-			stats: []stats{
-				{min: writeInt32(10), max: writeInt32(30)},
-			},
-		},
-		{
-			name: "float64 stats",
-			col:  "boldness",
-			input: [][]Person{
-				{
-					{Boldness: 0.5},
-					{Boldness: 500.0},
-					{Boldness: -50.5},
-				},
-			},
-			stats: []stats{
-				{min: writeFloat64(-50.5), max: writeFloat64(500.0)},
-			},
-		},
-		{
-			name: "float32 optional stats",
-			col:  "lameness",
-			input: [][]Person{
-				{
-					{Lameness: pfloat32(0.5)},
-					{Lameness: pfloat32(500.0)},
-					{Lameness: pfloat32(50.5)},
-					{Lameness: nil},
-				},
-			},
-			stats: []stats{
-				{min: writeFloat32(0.5), max: writeFloat32(500.0), nilCount: pint64(1)},
-			},
+			name:      "strings",
+			col:       "bff",
+			pageSize:  2,
+			input:     []Person{{BFF: "Fred"}, {BFF: "Val"}, {BFF: "Fred"}, {BFF: "Val"}, {BFF: "Fred"}},
+			dictSize:  2,
+			encodings: []sch.Encoding{sch.Encoding_RLE_DICTIONARY, sch.Encoding_RLE_DICTIONARY, sch.Encoding_RLE_DICTIONARY},
 		},
 		{
-			name: "bool stats",
-			col:  "hungry",
-			input: [][]Person{
-				{
-					{Hungry: true},
-					{Hungry: false},
-					{Hungry: true},
-				},
-			},
-			stats: []stats{
-				{},
-			},
+			name:      "optional int64",
+			col:       "sadness",
+			pageSize:  3,
+			input:     []Person{{Sadness: pint64(5)}, {}, {Sadness: pint64(5)}, {Sadness: pint64(-5)}},
+			dictSize:  2,
+			encodings: []sch.Encoding{sch.Encoding_RLE_DICTIONARY, sch.Encoding_RLE_DICTIONARY},
 		},
 		{
-			name: "optional bool stats",
-			col:  "keen",
-			input: [][]Person{
-				{
-					{Keen: pbool(true)},
-					{Keen: nil},
-					{Keen: nil},
-				},
-			},
-			stats: []stats{
-				{nilCount: pint64(2)},
-			},
+			name:      "optional string all nil",
+			col:       "code",
+			input:     []Person{{}, {}},
+			encodings: []sch.Encoding{sch.Encoding_PLAIN},
 		},
 		{
-			name: "string stats",
-			col:  "bff",
-			input: [][]Person{
-				{
-					{BFF: "Fred"},
-					{BFF: "Val"},
-					{BFF: "Miranda"},
-				},
-			},
-			stats: []stats{
-				{min: []byte("Fred"), max: []byte("Val")},
-			},
+			name:      "bool",
+			col:       "hungry",
+			input:     []Person{{Hungry: true}, {Hungry: false}},
+			encodings: []sch.Encoding{sch.Encoding_PLAIN},
 		},
 		{
-			name: "string optional stats",
-			col:  "code",
-			input: [][]Person{
-				{
-					{Code: pstring("Fred")},
-					{Code: nil},
-					{Code: pstring("Miranda")},
-				},
-			},
-			stats: []stats{
-				{min: []byte("Fred"), max: []byte("Miranda"), nilCount: pint64(1)},
-			},
+			name:      "fall back to plain",
+			col:       "bff",
+			pageSize:  500,
+			input:     getDistinctBFFs(2000, 1000),
+			dictSize:  1000,
+			encodings: []sch.Encoding{sch.Encoding_RLE_DICTIONARY, sch.Encoding_RLE_DICTIONARY, sch.Encoding_PLAIN, sch.Encoding_PLAIN},
 		},
 	}
 
 	for i, tc := range testCases {
-		for j, comp := range []string{"uncompressed", "snappy"} {
-			t.Run(fmt.Sprintf("%02d %s %s", 2*i+j, tc.name, comp), func(t *testing.T) {
-				if tc.pageSize == 0 {
-					tc.pageSize = 100
-				}
-				var buf bytes.Buffer
-				w, err := NewParquetWriter(&buf, MaxPageSize(tc.pageSize), compressionTest[comp])
-				assert.Nil(t, err, tc.name)
-				for _, rowgroup := range tc.input {
-					for _, p := range rowgroup {
-						w.Add(p)
-					}
-					assert.Nil(t, w.Write(), tc.name)
-				}
+		t.Run(fmt.Sprintf("%02d %s", i, tc.name), func(t *testing.T) {
+			if tc.pageSize == 0 {
+				tc.pageSize = 100
+			}
+			b := writePeople(t, [][]Person{tc.input}, MaxPageSize(tc.pageSize), Dictionary)
+			r := bytes.NewReader(b)
+			footer, err := parquet.ReadMetaData(r)
+			if !assert.NoError(t, err) {
+				return
+			}
 
-				err = w.Close()
-				assert.Nil(t, err, tc.name)
+			col := getColumn(footer, tc.col)
+			if !assert.NotNil(t, col) {
+				return
+			}
 
-				r := bytes.NewReader(buf.Bytes())
-				footer, err := parquet.ReadMetaData(r)
-				if !assert.NoError(t, err) {
-					return
-				}
+			md := col.MetaData
+			offset := md.DataPageOffset
+			if tc.dictSize == 0 {
+				assert.Nil(t, md.DictionaryPageOffset)
+			} else if assert.NotNil(t, md.DictionaryPageOffset) {
+				offset = *md.DictionaryPageOffset
+				assert.True(t, md.DataPageOffset > offset)
+			}
 
-				pages, err := getPageHeaders(r, tc.col, footer)
-				if !assert.NoError(t, err) {
-					return
-				}
+			pages, err := parquet.PageHeadersAtOffset(r, offset, md.NumValues)
+			if !assert.NoError(t, err) {
+				return
+			}
 
-				if !assert.Equal(t, len(tc.stats), len(pages), tc.name) {
-					return
-				}
-				for i, st := range tc.stats {
-					ph := pages[i]
-					assert.Equal(t, st.min, ph.DataPageHeader.Statistics.MinValue)
-					assert.Equal(t, st.max, ph.DataPageHeader.Statistics.MaxValue)
-					if st.nilCount == nil {
-						assert.Equal(t, st.nilCount, ph.DataPageHeader.Statistics.NullCount)
-					} else {
-						assert.Equal(t, *st.nilCount, *ph.DataPageHeader.Statistics.NullCount)
-					}
-				}
-			})
+			if tc.dictSize > 0 {
+				assert.Equal(t, sch.PageType_DICTIONARY_PAGE, pages[0].Type)
+				assert.Equal(t, tc.dictSize, pages[0].DictionaryPageHeader.NumValues)
+				pages = pages[1:]
+			}
+
+			var encs []sch.Encoding
+			for _, ph := range pages {
+				assert.Equal(t, sch.PageType_DATA_PAGE, ph.Type)
+				encs = append(encs, ph.DataPageHeader.Encoding)
+			}
+			assert.Equal(t, tc.encodings, encs)
+
+			for _, enc := range tc.encodings {
+				assert.Contains(t, md.Encodings, enc)
+			}
+
+			assert.Equal(t, tc.input, readPeople(t, bytes.NewReader(b)))
+		})
+	}
+}
+
+func getColumn(footer *sch.FileMetaData, name string) *sch.ColumnChunk {
+	for _, rg := range footer.RowGroups {
+		for _, col := range rg.Columns {
+			if strings.Join(col.MetaData.PathInSchema, ".") == name {
+				return col
+			}
 		}
 	}
+	return nil
+}
+
+func getDistinctBFFs(n, size int) []Person {
+	out := make([]Person, n)
+	for i := range out {
+		s := fmt.Sprintf("%0*d", size, i)
+		out[i] = Person{BFF: s}
+	}
+	return out
 }
 
 func getPageHeaders(r io.ReadSeeker, name string, footer *sch.FileMetaData) ([]sch.PageHeader, error) {
 	var out []sch.PageHeader
 	for _, rg := range footer.RowGroups {
 		for _, col := range rg.Columns {
-			pth := col.MetaData.PathInSchema
-			if pth[len(pth)-1] == name {
+			if strings.Join(col.MetaData.PathInSchema, ".") == name {
 				h, err := parquet.PageHeadersAtOffset(r, col.MetaData.DataPageOffset, col.MetaData.NumValues)
 				if err != nil {
 					return nil, err
@@ -779,6 +2859,21 @@ var compressionTest = map[string]func(*ParquetWriter) error{
 	"uncompressed": Uncompressed,
 	"snappy":       Snappy,
 	"gzip":         Gzip,
+	"zstd":         Zstd,
+	"lz4":          Lz4,
+	"brotli":       Brotli,
+}
+
+var encodingTest = map[string]func(*ParquetWriter) error{
+	"plain":      func(*ParquetWriter) error { return nil },
+	"dictionary": Dictionary,
+	"v2":         DataPageV2,
+	"v2 dictionary": func(p *ParquetWriter) error {
+		if err := DataPageV2(p); err != nil {
+			return err
+		}
+		return Dictionary(p)
+	},
 }
 
 func getLen(peeps [][]Person) int {
@@ -854,6 +2949,23 @@ func randString(n int) *string {
 	return &s
 }
 
+// getFriendlyPeople returns n people, two thirds of whom have two
+// friends and a quarter of whom knit.
+func getFriendlyPeople(n int) []Person {
+	var out []Person
+	for i := 0; i < n; i++ {
+		p := newPerson(i)
+		if i%3 != 0 {
+			p.Friends = []Being{{ID: int32(i), Name: fmt.Sprintf("friend-%d", i)}, {ID: int32(i + 1)}}
+		}
+		if i%4 == 0 {
+			p.Hobby = &Hobby{Name: "knitting", Skills: []Skill{{Name: "needles"}}}
+		}
+		out = append(out, p)
+	}
+	return out
+}
+
 func getPeople(rgSize, n int) [][]Person {
 	var out [][]Person
 	var rg []Person
@@ -974,6 +3086,12 @@ func writeInt32(i int32) []byte {
 	return buf.Bytes()
 }
 
+func writeUint32(i uint32) []byte {
+	var buf bytes.Buffer
+	binary.Write(&buf, binary.LittleEndian, i)
+	return buf.Bytes()
+}
+
 func writeFloat32(f float32) []byte {
 	var buf bytes.Buffer
 	binary.Write(&buf, binary.LittleEndian, f)
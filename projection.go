@@ -0,0 +1,199 @@
+package parquet
+
+import (
+	"fmt"
+	"strings"
+
+	sch "github.com/parsyl/parquet/schema"
+)
+
+// projection keeps track of the columns that a reader reads.  A column
+// that isn't read can still be needed to build the nested structs of a
+// column that is (the code that parquetgen generates only creates a
+// nested struct when it scans the struct's first column), so its levels
+// are derived from the levels of one of the columns that are read.
+type projection struct {
+	columns map[string]bool
+	sources map[string]string
+}
+
+// Project makes the reader only read the columns in cols.  A column can
+// be a leaf (like "friends.name") or a group (like "friends"), in which
+// case all of the group's columns are read.  Columns are named as they
+// are by Column.  All of the columns are read if cols is empty.
+func (m *Metadata) Project(cols ...string) error {
+	if len(cols) == 0 {
+		m.projection = nil
+		return nil
+	}
+
+	p := &projection{
+		columns: map[string]bool{},
+		sources: map[string]string{},
+	}
+
+	for _, col := range cols {
+		var found bool
+		for _, f := range m.schema.fields {
+			name := strings.Join(f.Path, ".")
+			short := segmentsName(fieldSegments(f))
+			if name == col || short == col || strings.HasPrefix(name, col+".") || strings.HasPrefix(short, col+".") {
+				p.columns[name] = true
+				found = true
+			}
+		}
+		if !found {
+			return fmt.Errorf("unknown column %s", col)
+		}
+	}
+
+	// the values of a map can't be read without its keys
+	for _, f := range m.schema.fields {
+		if p.columns[strings.Join(f.Path, ".")] {
+			if key, ok := m.mapKey(f); ok {
+				p.columns[key] = true
+			}
+		}
+	}
+
+	for _, f := range m.schema.fields {
+		name := strings.Join(f.Path, ".")
+		if p.columns[name] {
+			continue
+		}
+
+		var depth int
+		for _, g := range m.schema.fields {
+			if !p.columns[strings.Join(g.Path, ".")] {
+				continue
+			}
+
+			if d := m.sharedDepth(f, g); d > depth {
+				depth = d
+				p.sources[name] = strings.Join(g.Path, ".")
+			}
+		}
+	}
+
+	m.projection = p
+	return nil
+}
+
+// Projected returns true if column col is read.
+func (m *Metadata) Projected(col string) bool {
+	return m.projection == nil || m.projection.columns[col]
+}
+
+// LevelsSource returns the column that the levels of column col are
+// derived from (see DeriveLevels).  It returns false if col is read or
+// isn't needed to build the nested structs of the columns that are.
+func (m *Metadata) LevelsSource(col string) (string, bool) {
+	if m.projection == nil {
+		return "", false
+	}
+	src, ok := m.projection.sources[col]
+	return src, ok
+}
+
+// DeriveLevels returns the definition and repetition levels of column col
+// given the levels (defs and reps) of the column returned by LevelsSource.
+// The levels go as deep as the groups that the two columns share, which is
+// deep enough for col to build the structs that the other column needs.
+func (m *Metadata) DeriveLevels(col string, defs, reps []uint8) ([]uint8, []uint8) {
+	f, _ := m.field(col)
+	g, _ := m.field(m.projection.sources[col])
+
+	var def, rep uint8
+	for _, t := range f.Types[:m.sharedDepth(f, g)] {
+		if sch.FieldRepetitionType(t) != sch.FieldRepetitionType_REQUIRED {
+			def++
+		}
+		if sch.FieldRepetitionType(t) == sch.FieldRepetitionType_REPEATED {
+			rep++
+		}
+	}
+
+	outDefs := make([]uint8, 0, len(defs))
+	var outReps []uint8
+	if m.maxLevels(col).Rep > 0 {
+		outReps = make([]uint8, 0, len(defs))
+	}
+
+	for i, d := range defs {
+		var r uint8
+		if reps != nil {
+			r = reps[i]
+		}
+
+		if r > rep {
+			continue
+		}
+
+		if d > def {
+			d = def
+		}
+
+		outDefs = append(outDefs, d)
+		if outReps != nil {
+			outReps = append(outReps, r)
+		}
+	}
+
+	return outDefs, outReps
+}
+
+// sharedDepth returns the number of groups that the columns f and g both
+// belong to, not counting groups that are required all the way up to the
+// root (since they don't need to be built).
+func (m *Metadata) sharedDepth(f, g Field) int {
+	if m.maxLevels(strings.Join(f.Path, ".")).Def == 0 || m.maxLevels(strings.Join(g.Path, ".")).Def == 0 {
+		return 0
+	}
+
+	var depth, defined int
+	for i := 0; i < len(f.Path)-1 && i < len(g.Path)-1; i++ {
+		if f.Path[i] != g.Path[i] {
+			break
+		}
+
+		depth++
+		if sch.FieldRepetitionType(f.Types[i]) != sch.FieldRepetitionType_REQUIRED {
+			defined = depth
+		}
+	}
+	return defined
+}
+
+// mapKey returns the key column of the map (if any) that column f is
+// the key or value of.
+func (m *Metadata) mapKey(f Field) (string, bool) {
+	for i, g := range f.Groups {
+		if g == nil {
+			continue
+		}
+
+		se := &sch.SchemaElement{}
+		g(se)
+		if se.LogicalType == nil || se.LogicalType.MAP == nil || len(f.Path) < i+3 {
+			continue
+		}
+
+		// the key is the first column in the map's key_value group
+		prefix := strings.Join(f.Path[:i+2], ".") + "."
+		for _, k := range m.schema.fields {
+			if name := strings.Join(k.Path, "."); strings.HasPrefix(name, prefix) {
+				return name, true
+			}
+		}
+	}
+	return "", false
+}
+
+func (m *Metadata) field(col string) (Field, bool) {
+	for _, f := range m.schema.fields {
+		if strings.Join(f.Path, ".") == col {
+			return f, true
+		}
+	}
+	return Field{}, false
+}
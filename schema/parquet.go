@@ -286,6 +286,7 @@ const (
   Encoding_DELTA_LENGTH_BYTE_ARRAY Encoding = 6
   Encoding_DELTA_BYTE_ARRAY Encoding = 7
   Encoding_RLE_DICTIONARY Encoding = 8
+  Encoding_BYTE_STREAM_SPLIT Encoding = 9
 )
 
 func (p Encoding) String() string {
@@ -298,6 +299,7 @@ func (p Encoding) String() string {
   case Encoding_DELTA_LENGTH_BYTE_ARRAY: return "DELTA_LENGTH_BYTE_ARRAY"
   case Encoding_DELTA_BYTE_ARRAY: return "DELTA_BYTE_ARRAY"
   case Encoding_RLE_DICTIONARY: return "RLE_DICTIONARY"
+  case Encoding_BYTE_STREAM_SPLIT: return "BYTE_STREAM_SPLIT"
   }
   return "<UNSET>"
 }
@@ -312,6 +314,7 @@ func EncodingFromString(s string) (Encoding, error) {
   case "DELTA_LENGTH_BYTE_ARRAY": return Encoding_DELTA_LENGTH_BYTE_ARRAY, nil 
   case "DELTA_BYTE_ARRAY": return Encoding_DELTA_BYTE_ARRAY, nil 
   case "RLE_DICTIONARY": return Encoding_RLE_DICTIONARY, nil 
+  case "BYTE_STREAM_SPLIT": return Encoding_BYTE_STREAM_SPLIT, nil 
   }
   return Encoding(0), fmt.Errorf("not a valid Encoding string")
 }
@@ -362,6 +365,7 @@ const (
   CompressionCodec_BROTLI CompressionCodec = 4
   CompressionCodec_LZ4 CompressionCodec = 5
   CompressionCodec_ZSTD CompressionCodec = 6
+  CompressionCodec_LZ4_RAW CompressionCodec = 7
 )
 
 func (p CompressionCodec) String() string {
@@ -373,6 +377,7 @@ func (p CompressionCodec) String() string {
   case CompressionCodec_BROTLI: return "BROTLI"
   case CompressionCodec_LZ4: return "LZ4"
   case CompressionCodec_ZSTD: return "ZSTD"
+  case CompressionCodec_LZ4_RAW: return "LZ4_RAW"
   }
   return "<UNSET>"
 }
@@ -386,6 +391,7 @@ func CompressionCodecFromString(s string) (CompressionCodec, error) {
   case "BROTLI": return CompressionCodec_BROTLI, nil 
   case "LZ4": return CompressionCodec_LZ4, nil 
   case "ZSTD": return CompressionCodec_ZSTD, nil 
+  case "LZ4_RAW": return CompressionCodec_LZ4_RAW, nil 
   }
   return CompressionCodec(0), fmt.Errorf("not a valid CompressionCodec string")
 }
@@ -6967,11 +6973,19 @@ func (p *ColumnChunk) Validate() error {
 //  - NumRows: Number of rows in this row group *
 //  - SortingColumns: If set, specifies a sort ordering of the rows in this RowGroup.
 // The sorting columns can be a subset of all the columns.
+//  - FileOffset: Byte offset from beginning of file to first page (data or dictionary)
+// in this row group *
+//  - TotalCompressedSize: Total byte size of all compressed (and potentially encrypted) column data
+//  in this row group *
+//  - Ordinal: Row group ordinal in the file *
 type RowGroup struct {
   Columns []*ColumnChunk `thrift:"columns,1,required" db:"columns" json:"columns"`
   TotalByteSize int64 `thrift:"total_byte_size,2,required" db:"total_byte_size" json:"total_byte_size"`
   NumRows int64 `thrift:"num_rows,3,required" db:"num_rows" json:"num_rows"`
   SortingColumns []*SortingColumn `thrift:"sorting_columns,4" db:"sorting_columns" json:"sorting_columns,omitempty"`
+  FileOffset *int64 `thrift:"file_offset,5" db:"file_offset" json:"file_offset,omitempty"`
+  TotalCompressedSize *int64 `thrift:"total_compressed_size,6" db:"total_compressed_size" json:"total_compressed_size,omitempty"`
+  Ordinal *int16 `thrift:"ordinal,7" db:"ordinal" json:"ordinal,omitempty"`
 }
 
 func NewRowGroup() *RowGroup {
@@ -6995,10 +7009,43 @@ var RowGroup_SortingColumns_DEFAULT []*SortingColumn
 func (p *RowGroup) GetSortingColumns() []*SortingColumn {
   return p.SortingColumns
 }
+var RowGroup_FileOffset_DEFAULT int64
+func (p *RowGroup) GetFileOffset() int64 {
+  if !p.IsSetFileOffset() {
+    return RowGroup_FileOffset_DEFAULT
+  }
+return *p.FileOffset
+}
+var RowGroup_TotalCompressedSize_DEFAULT int64
+func (p *RowGroup) GetTotalCompressedSize() int64 {
+  if !p.IsSetTotalCompressedSize() {
+    return RowGroup_TotalCompressedSize_DEFAULT
+  }
+return *p.TotalCompressedSize
+}
+var RowGroup_Ordinal_DEFAULT int16
+func (p *RowGroup) GetOrdinal() int16 {
+  if !p.IsSetOrdinal() {
+    return RowGroup_Ordinal_DEFAULT
+  }
+return *p.Ordinal
+}
 func (p *RowGroup) IsSetSortingColumns() bool {
   return p.SortingColumns != nil
 }
 
+func (p *RowGroup) IsSetFileOffset() bool {
+  return p.FileOffset != nil
+}
+
+func (p *RowGroup) IsSetTotalCompressedSize() bool {
+  return p.TotalCompressedSize != nil
+}
+
+func (p *RowGroup) IsSetOrdinal() bool {
+  return p.Ordinal != nil
+}
+
 func (p *RowGroup) Read(ctx context.Context, iprot thrift.TProtocol) error {
   if _, err := iprot.ReadStructBegin(ctx); err != nil {
     return thrift.PrependError(fmt.Sprintf("%T read error: ", p), err)
@@ -7058,6 +7105,36 @@ func (p *RowGroup) Read(ctx context.Context, iprot thrift.TProtocol) error {
           return err
         }
       }
+    case 5:
+      if fieldTypeId == thrift.I64 {
+        if err := p.ReadField5(ctx, iprot); err != nil {
+          return err
+        }
+      } else {
+        if err := iprot.Skip(ctx, fieldTypeId); err != nil {
+          return err
+        }
+      }
+    case 6:
+      if fieldTypeId == thrift.I64 {
+        if err := p.ReadField6(ctx, iprot); err != nil {
+          return err
+        }
+      } else {
+        if err := iprot.Skip(ctx, fieldTypeId); err != nil {
+          return err
+        }
+      }
+    case 7:
+      if fieldTypeId == thrift.I16 {
+        if err := p.ReadField7(ctx, iprot); err != nil {
+          return err
+        }
+      } else {
+        if err := iprot.Skip(ctx, fieldTypeId); err != nil {
+          return err
+        }
+      }
     default:
       if err := iprot.Skip(ctx, fieldTypeId); err != nil {
         return err
@@ -7140,6 +7217,33 @@ func (p *RowGroup)  ReadField4(ctx context.Context, iprot thrift.TProtocol) erro
   return nil
 }
 
+func (p *RowGroup)  ReadField5(ctx context.Context, iprot thrift.TProtocol) error {
+  if v, err := iprot.ReadI64(ctx); err != nil {
+  return thrift.PrependError("error reading field 5: ", err)
+} else {
+  p.FileOffset = &v
+}
+  return nil
+}
+
+func (p *RowGroup)  ReadField6(ctx context.Context, iprot thrift.TProtocol) error {
+  if v, err := iprot.ReadI64(ctx); err != nil {
+  return thrift.PrependError("error reading field 6: ", err)
+} else {
+  p.TotalCompressedSize = &v
+}
+  return nil
+}
+
+func (p *RowGroup)  ReadField7(ctx context.Context, iprot thrift.TProtocol) error {
+  if v, err := iprot.ReadI16(ctx); err != nil {
+  return thrift.PrependError("error reading field 7: ", err)
+} else {
+  p.Ordinal = &v
+}
+  return nil
+}
+
 func (p *RowGroup) Write(ctx context.Context, oprot thrift.TProtocol) error {
   if err := oprot.WriteStructBegin(ctx, "RowGroup"); err != nil {
     return thrift.PrependError(fmt.Sprintf("%T write struct begin error: ", p), err) }
@@ -7148,6 +7252,9 @@ func (p *RowGroup) Write(ctx context.Context, oprot thrift.TProtocol) error {
     if err := p.writeField2(ctx, oprot); err != nil { return err }
     if err := p.writeField3(ctx, oprot); err != nil { return err }
     if err := p.writeField4(ctx, oprot); err != nil { return err }
+    if err := p.writeField5(ctx, oprot); err != nil { return err }
+    if err := p.writeField6(ctx, oprot); err != nil { return err }
+    if err := p.writeField7(ctx, oprot); err != nil { return err }
   }
   if err := oprot.WriteFieldStop(ctx); err != nil {
     return thrift.PrependError("write field stop error: ", err) }
@@ -7216,6 +7323,42 @@ func (p *RowGroup) writeField4(ctx context.Context, oprot thrift.TProtocol) (err
   return err
 }
 
+func (p *RowGroup) writeField5(ctx context.Context, oprot thrift.TProtocol) (err error) {
+  if p.IsSetFileOffset() {
+    if err := oprot.WriteFieldBegin(ctx, "file_offset", thrift.I64, 5); err != nil {
+      return thrift.PrependError(fmt.Sprintf("%T write field begin error 5:file_offset: ", p), err) }
+    if err := oprot.WriteI64(ctx, int64(*p.FileOffset)); err != nil {
+    return thrift.PrependError(fmt.Sprintf("%T.file_offset (5) field write error: ", p), err) }
+    if err := oprot.WriteFieldEnd(ctx); err != nil {
+      return thrift.PrependError(fmt.Sprintf("%T write field end error 5:file_offset: ", p), err) }
+  }
+  return err
+}
+
+func (p *RowGroup) writeField6(ctx context.Context, oprot thrift.TProtocol) (err error) {
+  if p.IsSetTotalCompressedSize() {
+    if err := oprot.WriteFieldBegin(ctx, "total_compressed_size", thrift.I64, 6); err != nil {
+      return thrift.PrependError(fmt.Sprintf("%T write field begin error 6:total_compressed_size: ", p), err) }
+    if err := oprot.WriteI64(ctx, int64(*p.TotalCompressedSize)); err != nil {
+    return thrift.PrependError(fmt.Sprintf("%T.total_compressed_size (6) field write error: ", p), err) }
+    if err := oprot.WriteFieldEnd(ctx); err != nil {
+      return thrift.PrependError(fmt.Sprintf("%T write field end error 6:total_compressed_size: ", p), err) }
+  }
+  return err
+}
+
+func (p *RowGroup) writeField7(ctx context.Context, oprot thrift.TProtocol) (err error) {
+  if p.IsSetOrdinal() {
+    if err := oprot.WriteFieldBegin(ctx, "ordinal", thrift.I16, 7); err != nil {
+      return thrift.PrependError(fmt.Sprintf("%T write field begin error 7:ordinal: ", p), err) }
+    if err := oprot.WriteI16(ctx, int16(*p.Ordinal)); err != nil {
+    return thrift.PrependError(fmt.Sprintf("%T.ordinal (7) field write error: ", p), err) }
+    if err := oprot.WriteFieldEnd(ctx); err != nil {
+      return thrift.PrependError(fmt.Sprintf("%T write field end error 7:ordinal: ", p), err) }
+  }
+  return err
+}
+
 func (p *RowGroup) Equals(other *RowGroup) bool {
   if p == other {
     return true
@@ -7234,6 +7377,24 @@ func (p *RowGroup) Equals(other *RowGroup) bool {
     _src11 := other.SortingColumns[i]
     if !_tgt.Equals(_src11) { return false }
   }
+  if p.FileOffset != other.FileOffset {
+    if p.FileOffset == nil || other.FileOffset == nil {
+      return false
+    }
+    if (*p.FileOffset) != (*other.FileOffset) { return false }
+  }
+  if p.TotalCompressedSize != other.TotalCompressedSize {
+    if p.TotalCompressedSize == nil || other.TotalCompressedSize == nil {
+      return false
+    }
+    if (*p.TotalCompressedSize) != (*other.TotalCompressedSize) { return false }
+  }
+  if p.Ordinal != other.Ordinal {
+    if p.Ordinal == nil || other.Ordinal == nil {
+      return false
+    }
+    if (*p.Ordinal) != (*other.Ordinal) { return false }
+  }
   return true
 }
 
@@ -450,6 +450,17 @@ enum Encoding {
   /** Dictionary encoding: the ids are encoded using the RLE encoding
    */
   RLE_DICTIONARY = 8;
+
+  /** Encoding for fixed-width data (FLOAT, DOUBLE, INT32, INT64, FIXED_LEN_BYTE_ARRAY).
+     K byte-streams are created where K is the size in bytes of the data type.
+     The individual bytes of a value are scattered to the corresponding stream and
+     the streams are concatenated.
+     This itself does not reduce the size of the data but can lead to better compression
+     afterwards.
+
+     Added in 2.8 for FLOAT and DOUBLE.
+   */
+  BYTE_STREAM_SPLIT = 9;
 }
 
 /**
@@ -468,6 +479,7 @@ enum CompressionCodec {
   BROTLI = 4; // Added in 2.4
   LZ4 = 5;    // Added in 2.4
   ZSTD = 6;   // Added in 2.4
+  LZ4_RAW = 7; // Added in 2.9
 }
 
 enum PageType {
@@ -706,6 +718,17 @@ struct RowGroup {
    * The sorting columns can be a subset of all the columns.
    */
   4: optional list<SortingColumn> sorting_columns
+
+  /** Byte offset from beginning of file to first page (data or dictionary)
+   * in this row group **/
+  5: optional i64 file_offset
+
+  /** Total byte size of all compressed (and potentially encrypted) column data
+   *  in this row group **/
+  6: optional i64 total_compressed_size
+
+  /** Row group ordinal in the file **/
+  7: optional i16 ordinal
 }
 
 /** Empty struct to signal the order defined by the physical or logical type */
@@ -0,0 +1,54 @@
+package parquet
+
+import (
+	"io"
+	"strings"
+)
+
+// SkipRows returns a copy of pg (one of the Pages returned by Pages) that
+// doesn't read the first n of the rows that pg would read.  If the column
+// chunk has an OffsetIndex the data pages before the one that has the
+// n'th row aren't read.
+func (m *Metadata) SkipRows(r io.ReadSeeker, pg Page, n int64) (Page, error) {
+	if n == 0 {
+		return pg, nil
+	}
+
+	rows := pg.rows
+	if rows == nil {
+		rows = rowRanges{{start: 0, end: pg.numRows}}
+	}
+	pg.rows = rows.skip(n)
+
+	if pg.locations == nil {
+		oi, err := ReadOffsetIndex(r, pg.chunk)
+		if err != nil {
+			return pg, err
+		}
+		if oi != nil {
+			pg.locations = oi.PageLocations
+		}
+	}
+
+	// the values of required columns are read by counting them, and
+	// each row has one value.
+	if m.maxLevels(strings.Join(pg.chunk.MetaData.PathInSchema, ".")).Def == 0 {
+		pg.N = int(pg.rows.count())
+	}
+	return pg, nil
+}
+
+// skip returns the rows of r that come after the first n of them.
+func (r rowRanges) skip(n int64) rowRanges {
+	out := rowRanges{}
+	for _, rr := range r {
+		if l := rr.end - rr.start; n >= l {
+			n -= l
+			continue
+		}
+
+		out = append(out, rowRange{start: rr.start + n, end: rr.end})
+		n = 0
+	}
+	return out
+}
@@ -0,0 +1,127 @@
+package parquet
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+
+	sch "github.com/parsyl/parquet/schema"
+)
+
+// mergeStats adds the statistics of a page to the statistics of its
+// column chunk.  The min and max values are compared using the type
+// defined order of the column, so unsigned integers are compared as
+// unsigned, byte arrays are compared lexicographically as unsigned
+// bytes and decimal byte arrays are compared as signed integers.  A page
+// without statistics (like a dictionary page) leaves the column chunk's
+// statistics alone.
+func mergeStats(se sch.SchemaElement, chunk, page *sch.Statistics) *sch.Statistics {
+	if page == nil {
+		return chunk
+	}
+
+	if chunk == nil {
+		var z int64
+		chunk = &sch.Statistics{NullCount: &z}
+	}
+
+	if page.NullCount != nil {
+		n := *chunk.NullCount + *page.NullCount
+		chunk.NullCount = &n
+	}
+
+	if page.MinValue != nil && (chunk.MinValue == nil || compare(se, page.MinValue, chunk.MinValue) < 0) {
+		chunk.MinValue = page.MinValue
+	}
+
+	if page.MaxValue != nil && (chunk.MaxValue == nil || compare(se, page.MaxValue, chunk.MaxValue) > 0) {
+		chunk.MaxValue = page.MaxValue
+	}
+
+	return chunk
+}
+
+// compare compares two PLAIN encoded values (without the length prefix
+// of byte arrays) of the column described by se.
+func compare(se sch.SchemaElement, a, b []byte) int {
+	if se.Type == nil {
+		return bytes.Compare(a, b)
+	}
+
+	switch *se.Type {
+	case sch.Type_BYTE_ARRAY, sch.Type_FIXED_LEN_BYTE_ARRAY:
+		if decimal(se) {
+			return Decimal(a).Cmp(Decimal(b))
+		}
+		return bytes.Compare(a, b)
+	case sch.Type_BOOLEAN:
+		return bytes.Compare(a, b)
+	case sch.Type_INT32:
+		x, y := binary.LittleEndian.Uint32(a), binary.LittleEndian.Uint32(b)
+		if unsigned(se) {
+			return compareUint64(uint64(x), uint64(y))
+		}
+		return compareInt64(int64(int32(x)), int64(int32(y)))
+	case sch.Type_INT64:
+		x, y := binary.LittleEndian.Uint64(a), binary.LittleEndian.Uint64(b)
+		if unsigned(se) {
+			return compareUint64(x, y)
+		}
+		return compareInt64(int64(x), int64(y))
+	case sch.Type_FLOAT:
+		x, y := math.Float32frombits(binary.LittleEndian.Uint32(a)), math.Float32frombits(binary.LittleEndian.Uint32(b))
+		return compareFloat64(float64(x), float64(y))
+	case sch.Type_DOUBLE:
+		x, y := math.Float64frombits(binary.LittleEndian.Uint64(a)), math.Float64frombits(binary.LittleEndian.Uint64(b))
+		return compareFloat64(x, y)
+	default:
+		return bytes.Compare(a, b)
+	}
+}
+
+func unsigned(se sch.SchemaElement) bool {
+	if se.ConvertedType != nil {
+		switch *se.ConvertedType {
+		case sch.ConvertedType_UINT_8, sch.ConvertedType_UINT_16, sch.ConvertedType_UINT_32, sch.ConvertedType_UINT_64:
+			return true
+		}
+	}
+
+	if se.LogicalType != nil && se.LogicalType.INTEGER != nil {
+		return !se.LogicalType.INTEGER.IsSigned
+	}
+	return false
+}
+
+func compareInt64(a, b int64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
+
+func compareUint64(a, b uint64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
+
+func compareFloat64(a, b float64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,203 @@
+package parquet
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	sch "github.com/parsyl/parquet/schema"
+)
+
+// TimeUnit is the unit of the values of a column of time.Time fields.
+type TimeUnit int
+
+const (
+	// Millis, Micros and Nanos are the units of a TIMESTAMP column.
+	Millis TimeUnit = iota
+	Micros
+	Nanos
+
+	// Days is the unit of a DATE column.
+	Days
+)
+
+const secondsPerDay = 24 * 60 * 60
+
+var (
+	minNanos = time.Unix(0, math.MinInt64).UTC()
+	maxNanos = time.Unix(0, math.MaxInt64).UTC()
+)
+
+// Int64 returns the number of units between the unix epoch and t.  If utc
+// is false the wall clock time of t (in t's location) is used, which is
+// what a TIMESTAMP column that isn't adjusted to UTC holds.  The wall
+// clock date of t is always used for Days.
+func (u TimeUnit) Int64(t time.Time, utc bool) int64 {
+	t = u.wall(t, utc)
+
+	switch u {
+	case Millis:
+		return t.UnixMilli()
+	case Micros:
+		return t.UnixMicro()
+	case Days:
+		t = time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+		return t.Unix() / secondsPerDay
+	default:
+		return t.UnixNano()
+	}
+}
+
+// CheckRange returns an error if t is too far from the unix epoch for
+// its number of units to fit in a column of unit u, which (for Nanos)
+// are the times before 1677-09-21 and after 2262-04-11.
+func (u TimeUnit) CheckRange(t time.Time, utc bool) error {
+	t = u.wall(t, utc)
+	s := t.Unix()
+
+	var ok bool
+	switch u {
+	case Millis:
+		ok = s > math.MinInt64/1000 && s < math.MaxInt64/1000
+	case Micros:
+		ok = s > math.MinInt64/1000000 && s < math.MaxInt64/1000000
+	case Days:
+		ok = s/secondsPerDay >= math.MinInt32 && s/secondsPerDay <= math.MaxInt32
+	default:
+		ok = !t.Before(minNanos) && !t.After(maxNanos)
+	}
+
+	if !ok {
+		return fmt.Errorf("time %s is out of the range of a column in %s", t.Format(time.RFC3339Nano), u)
+	}
+	return nil
+}
+
+func (u TimeUnit) wall(t time.Time, utc bool) time.Time {
+	if !utc || u == Days {
+		return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), time.UTC)
+	}
+	return t
+}
+
+func (u TimeUnit) String() string {
+	switch u {
+	case Millis:
+		return "milliseconds"
+	case Micros:
+		return "microseconds"
+	case Days:
+		return "days"
+	default:
+		return "nanoseconds"
+	}
+}
+
+// TimeUnit returns the unit of the page's column in the file (the unit of
+// its TIMESTAMP or DATE), which might not be u, the unit of the field that
+// reads it.  It returns u if the column doesn't have a unit or the page
+// isn't from a file, and an error if the column is a DATE and u isn't
+// Days or the other way around.
+func (pg Page) TimeUnit(u TimeUnit) (TimeUnit, error) {
+	if pg.element == nil {
+		return u, nil
+	}
+
+	unit, ok := timeUnit(pg.element)
+	if !ok {
+		return u, nil
+	}
+
+	if (unit == Days) != (u == Days) {
+		return u, fmt.Errorf("column %s is in %s, but its field is in %s", strings.Join(pg.chunk.MetaData.PathInSchema, "."), unit, u)
+	}
+	return unit, nil
+}
+
+// timeUnit returns the unit of a TIMESTAMP or DATE column.
+func timeUnit(se *sch.SchemaElement) (TimeUnit, bool) {
+	if lt := se.LogicalType; lt != nil {
+		switch {
+		case lt.DATE != nil:
+			return Days, true
+		case lt.TIMESTAMP != nil && lt.TIMESTAMP.Unit != nil:
+			switch {
+			case lt.TIMESTAMP.Unit.MILLIS != nil:
+				return Millis, true
+			case lt.TIMESTAMP.Unit.MICROS != nil:
+				return Micros, true
+			case lt.TIMESTAMP.Unit.NANOS != nil:
+				return Nanos, true
+			}
+		}
+	}
+
+	if se.ConvertedType == nil {
+		return 0, false
+	}
+
+	switch *se.ConvertedType {
+	case sch.ConvertedType_DATE:
+		return Days, true
+	case sch.ConvertedType_TIMESTAMP_MILLIS:
+		return Millis, true
+	case sch.ConvertedType_TIMESTAMP_MICROS:
+		return Micros, true
+	}
+	return 0, false
+}
+
+// Time is the reverse of Int64.  The time it returns is in UTC.
+func (u TimeUnit) Time(v int64) time.Time {
+	switch u {
+	case Millis:
+		return time.UnixMilli(v).UTC()
+	case Micros:
+		return time.UnixMicro(v).UTC()
+	case Days:
+		return time.Unix(v*secondsPerDay, 0).UTC()
+	default:
+		return time.Unix(0, v).UTC()
+	}
+}
+
+// TimeType returns the FieldFunc of a column of times in unit u.  Days
+// makes it an INT32 DATE column and the other units make it an INT64
+// TIMESTAMP column, where utc is the TIMESTAMP's isAdjustedToUTC.
+func TimeType(u TimeUnit, utc bool) FieldFunc {
+	return func(se *sch.SchemaElement) {
+		if u == Days {
+			t := sch.Type_INT32
+			ct := sch.ConvertedType_DATE
+			se.Type = &t
+			se.ConvertedType = &ct
+			se.LogicalType = &sch.LogicalType{DATE: sch.NewDateType()}
+			return
+		}
+
+		t := sch.Type_INT64
+		se.Type = &t
+
+		unit := sch.NewTimeUnit()
+		var ct sch.ConvertedType
+		switch u {
+		case Millis:
+			unit.MILLIS = sch.NewMilliSeconds()
+			ct = sch.ConvertedType_TIMESTAMP_MILLIS
+		case Micros:
+			unit.MICROS = sch.NewMicroSeconds()
+			ct = sch.ConvertedType_TIMESTAMP_MICROS
+		default:
+			unit.NANOS = sch.NewNanoSeconds()
+		}
+
+		se.LogicalType = &sch.LogicalType{TIMESTAMP: &sch.TimestampType{IsAdjustedToUTC: utc, Unit: unit}}
+
+		// the converted types only exist for times that are adjusted
+		// to UTC and aren't in nanoseconds.
+		if utc && u != Nanos {
+			se.ConvertedType = &ct
+		}
+	}
+}